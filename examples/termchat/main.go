@@ -24,11 +24,13 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
 	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/memories"
 	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/redaction"
 	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/textualopenai"
 )
 
@@ -37,7 +39,50 @@ type sessionOptions struct {
 	MaxOutputTokens    int
 	Instructions       string
 	Thinking           bool
+	Reasoning          *textualopenai.ReasoningConfig
+	Stateless          bool
+	OllamaNative       bool
 	DisplayHeaderInfos bool
+	Redactor           *redaction.Redactor
+	ApproveTools       bool
+	Tools              *textualopenai.ToolRegistry
+}
+
+// stdinLines delivers the lines of stdin to the REPL and to the tool approval prompt.
+// stdin is read in a goroutine so the approval prompt can give up on ctx (timeout, Ctrl-C)
+// without losing the next line. The channel is closed at EOF (stdinErr holds the read error, if any).
+var (
+	stdinLines = make(chan string)
+	stdinErr   error
+	stdinOnce  sync.Once
+)
+
+// readStdin starts reading stdin (once) and returns its lines.
+func readStdin() <-chan string {
+	stdinOnce.Do(func() {
+		go func() {
+			defer close(stdinLines)
+			scanner := bufio.NewScanner(os.Stdin)
+			for scanner.Scan() {
+				stdinLines <- scanner.Text()
+			}
+			stdinErr = scanner.Err()
+		}()
+	})
+	return stdinLines
+}
+
+// approvalMu serializes approval prompts when the model calls several tools in parallel.
+var approvalMu sync.Mutex
+
+// pathList is a repeatable flag.
+type pathList []string
+
+func (p *pathList) String() string { return strings.Join(*p, ",") }
+
+func (p *pathList) Set(v string) error {
+	*p = append(*p, v)
+	return nil
 }
 
 func main() {
@@ -48,7 +93,16 @@ func main() {
 		instructionsFlag     = flag.String("instructions", "", "Optional assistant instructions (system prompt)")
 		nonInteractivePrompt = flag.String("prompt", "", "If set, runs a single request and exits (otherwise starts a tiny REPL)")
 		thinking             = flag.Bool("thinking", false, "If set, thinking mode is requested (only supported by reasoning models)")
+		reasoningEffortFlag  = flag.String("reasoning-effort", "", "Reasoning effort: minimal, low, medium or high (reasoning models only)")
+		reasoningSummaryFlag = flag.String("reasoning-summary", "", "Reasoning summary: auto, concise or detailed (streamed to stderr)")
+		statelessFlag        = flag.Bool("stateless", false, "Do not store responses (store=false): reasoning items are re-injected encrypted in the tool loop")
+		pullFlag             = flag.Bool("pull", false, "Pull the Ollama model first when it is not present locally")
+		ollamaNativeFlag     = flag.Bool("ollama-native", false, "Use the native Ollama /api/chat API (keep_alive, options, timing metrics) instead of the OpenAI-compatible layer")
 		displayHeaderInfos   = flag.Bool("display-header-infos", false, "Display header infos")
+		logHTTPFlag          = flag.Bool("log-http", false, "Log the HTTP exchanges (method, URL, status, latency) to stderr")
+		redactFlag           = flag.Bool("redact", false, "Redact emails, phone numbers, credit cards and API keys before they reach the provider or the history")
+		approveToolsFlag     = flag.Bool("approve-tools", false, "Ask for a y/n confirmation before executing each tool call")
+		imageFlags           pathList
 
 		historyUUIDFlag      = flag.String("history-uuid", "", "Optional UUID for the in-memory REPL history")
 		historyAutoPurgeFlag = flag.Duration("history-auto-purge", 0, "Optional periodic purge frequency for REPL history (<=0 disables; purge is always enforced on Add)")
@@ -56,6 +110,7 @@ func main() {
 		historyTimeoutFlag   = flag.Duration("history-timeout", 0, "Auto-expire REPL history messages older than this duration (0 = disabled, examples: 30s, 5m, 1h)")
 	)
 
+	flag.Var(&imageFlags, "image", "Attach a local image or file to the first user turn (repeatable)")
 	flag.Parse()
 
 	// Resolve model
@@ -70,16 +125,71 @@ func main() {
 		log.Fatalf("termchat: -thinking requested but model %q does not advertise reasoning/thinking support", model.Name)
 	}
 
+	var reasoning *textualopenai.ReasoningConfig
+	if *reasoningEffortFlag != "" || *reasoningSummaryFlag != "" {
+		if !model.SupportsThinking() {
+			log.Fatalf("termchat: reasoning options requested but model %q does not advertise reasoning/thinking support", model.Name)
+		}
+		reasoning = &textualopenai.ReasoningConfig{
+			Effort:  textualopenai.ReasoningEffort(*reasoningEffortFlag),
+			Summary: textualopenai.ReasoningSummary(*reasoningSummaryFlag),
+		}
+	}
+
 	client, err := textualopenai.ClientFrom(*baseURLFlag, model, context.Background())
 	if err != nil {
 		log.Fatal(err)
 	}
+	if *logHTTPFlag {
+		client = client.WithMiddleware(textualopenai.LogRequests(log.Printf))
+	}
+	if *pullFlag && model.ProviderName == models.ProviderOllama {
+		pulled := false
+		models.RegisterEnsurePresent(models.ProviderOllama, client.Ollama().EnsurePresentFunc(func(p textualopenai.OllamaProgress) {
+			pulled = true
+			_, _ = fmt.Fprintf(os.Stderr, "\r\033[K%s %3.0f%%", p.Status, p.Percent())
+		}))
+		if err := models.EnsurePresent(context.Background(), model); err != nil {
+			log.Fatalf("termchat: %v", err)
+		}
+		if pulled {
+			_, _ = fmt.Fprintln(os.Stderr)
+		}
+	}
 	opts := sessionOptions{
 		Model:              model,
 		MaxOutputTokens:    *maxOutputTokensFlag,
 		Instructions:       *instructionsFlag,
 		Thinking:           *thinking,
+		Reasoning:          reasoning,
+		Stateless:          *statelessFlag,
+		OllamaNative:       *ollamaNativeFlag,
 		DisplayHeaderInfos: *displayHeaderInfos,
+		ApproveTools:       *approveToolsFlag,
+	}
+	if opts.OllamaNative && model.ProviderName != models.ProviderOllama {
+		log.Fatalf("termchat: -ollama-native requires an ollama model, got %q", model.ProviderName)
+	}
+	// The Messages, Gemini and Ollama chat APIs keep no server side state: tool loops replay the turn.
+	if wire := model.ProviderInfo().WireAPI; opts.OllamaNative || wire == models.WireAPIMessages || wire == models.WireAPIGenerateContent {
+		opts.Stateless = true
+	}
+	if *redactFlag {
+		opts.Redactor = redaction.NewRedactor()
+	}
+	// Tools are registered once and shared by every request of the session.
+	if opts.Tools, err = newToolRegistry(opts); err != nil {
+		log.Fatal(err)
+	}
+
+	// Attachments given on the command line are checked before any request.
+	var attachments []textualopenai.AttachmentRef
+	for _, path := range imageFlags {
+		ref, err := newAttachment(opts, path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		attachments = append(attachments, ref)
 	}
 
 	// Ctrl-C cancellation.
@@ -88,7 +198,7 @@ func main() {
 
 	// One-shot mode.
 	if strings.TrimSpace(*nonInteractivePrompt) != "" {
-		if err := runOnce(ctx, client, opts, *nonInteractivePrompt); err != nil {
+		if err := runOnce(ctx, client, opts, *nonInteractivePrompt, attachments); err != nil {
 			_, _ = fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
@@ -97,7 +207,7 @@ func main() {
 
 	// If no -prompt was provided but args exist, treat them as a one-shot prompt.
 	if argPrompt := strings.TrimSpace(strings.Join(flag.Args(), " ")); argPrompt != "" {
-		if err := runOnce(ctx, client, opts, argPrompt); err != nil {
+		if err := runOnce(ctx, client, opts, argPrompt, attachments); err != nil {
 			_, _ = fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
@@ -107,12 +217,15 @@ func main() {
 	// Interactive mode: keep history only in memory (no filesystem persistence).
 	history := initReplHistory(*historyUUIDFlag, *historyLimitFlag, *historyTimeoutFlag, *historyAutoPurgeFlag)
 	defer history.HaltAutoPurge()
+	if opts.Redactor != nil {
+		history.SetAddProcessor(textualopenai.InputItemRedactor(opts.Redactor))
+	}
 
 	if opts.DisplayHeaderInfos {
 		_, _ = fmt.Fprintf(os.Stderr, "termchat: history=memory uuid=%s items=%d timeout=%s\n", history.UUID, history.Size(), history.Timeout())
 	}
 
-	runRepl(ctx, client, opts, history)
+	runRepl(ctx, client, opts, history, attachments)
 }
 
 // initReplHistory creates a new in-memory conversation history.
@@ -122,36 +235,51 @@ func initReplHistory(uuidFlag string, limit int, timeout time.Duration, autoPurg
 }
 
 // runRepl is a Minimal REPL that keeps conversation history in a textualai memories.Memory.
-func runRepl(ctx context.Context, client textualopenai.Client, opts sessionOptions, history *memories.Memory[textualopenai.InputItem]) {
-	_, _ = fmt.Fprintln(os.Stderr, "Enter a prompt and press Enter (Ctrl-D to quit, Ctrl-C to interrupt).")
-	scanner := bufio.NewScanner(os.Stdin)
+// pending attachments are sent with the next user turn.
+func runRepl(ctx context.Context, client textualopenai.Client, opts sessionOptions, history *memories.Memory[textualopenai.InputItem], pending []textualopenai.AttachmentRef) {
+	_, _ = fmt.Fprintln(os.Stderr, "Enter a prompt and press Enter (Ctrl-D to quit, Ctrl-C to interrupt, /attach <path> to attach an image or a file).")
+	lines := readStdin()
 
 	for {
+		_, _ = fmt.Fprint(os.Stderr, "> ")
+		var line string
 		select {
 		case <-ctx.Done():
 			_, _ = fmt.Fprintln(os.Stderr, "\ninterrupted")
 			return
-		default:
-		}
-
-		_, _ = fmt.Fprint(os.Stderr, "> ")
-		if !scanner.Scan() {
-			// EOF or error.
-			if err := scanner.Err(); err != nil {
-				_, _ = fmt.Fprintln(os.Stderr, "\nstdin error:", err)
+		case l, ok := <-lines:
+			if !ok {
+				// EOF or error.
+				if stdinErr != nil {
+					_, _ = fmt.Fprintln(os.Stderr, "\nstdin error:", stdinErr)
+				}
+				return
 			}
-			return
+			line = l
 		}
-		content := strings.TrimSpace(scanner.Text())
+		content := strings.TrimSpace(line)
 		if content == "" {
 			continue
 		}
 
-		// Add user turn.
-		history.Add(textualopenai.InputItem{Role: "user", Content: content})
+		if path, ok := strings.CutPrefix(content, "/attach "); ok || content == "/attach" {
+			ref, err := newAttachment(opts, strings.TrimSpace(path))
+			if err != nil {
+				_, _ = fmt.Fprintln(os.Stderr, "error:", err)
+				continue
+			}
+			pending = append(pending, ref)
+			_, _ = fmt.Fprintf(os.Stderr, "termchat: %s attached to the next message (%d pending)\n", ref.Path, len(pending))
+			continue
+		}
+
+		// Add user turn: attachments are stored as references (path + hash), not as base64 blobs.
+		history.Add(userItem(content, pending))
+		pending = nil
 
 		// Stream assistant response (with tool loop) and append it to history.
-		assistantText, err := streamResponsesWithTools(ctx, client, opts, historySnapshotSorted(history))
+		// Attachment references are re-inlined on each replay.
+		assistantText, err := streamResponsesWithTools(ctx, client, opts, textualopenai.InlineAttachments(historySnapshotSorted(history)))
 		if err != nil {
 			_, _ = fmt.Fprintln(os.Stderr, "\nerror:", err)
 			continue
@@ -164,12 +292,39 @@ func runRepl(ctx context.Context, client textualopenai.Client, opts sessionOptio
 
 // runOnce just runs the request once (but will transparently perform extra API calls
 // if the model invokes tools and needs function_call_output round-trips).
-func runOnce(ctx context.Context, client textualopenai.Client, opts sessionOptions, prompt string) error {
-	input := []textualopenai.InputItem{{Role: "user", Content: prompt}}
+func runOnce(ctx context.Context, client textualopenai.Client, opts sessionOptions, prompt string, attachments []textualopenai.AttachmentRef) error {
+	input := textualopenai.InlineAttachments([]textualopenai.InputItem{userItem(prompt, attachments)})
 	_, err := streamResponsesWithTools(ctx, client, opts, input)
 	return err
 }
 
+// userItem builds a user turn: plain text, or multimodal parts when there are attachments.
+func userItem(text string, attachments []textualopenai.AttachmentRef) textualopenai.InputItem {
+	if len(attachments) == 0 {
+		return textualopenai.InputItem{Role: "user", Content: text}
+	}
+	parts := []any{textualopenai.Text(text)}
+	for _, ref := range attachments {
+		parts = append(parts, ref)
+	}
+	return textualopenai.InputItem{Role: "user", Content: parts}
+}
+
+// newAttachment checks that path can be attached with the selected model.
+func newAttachment(opts sessionOptions, path string) (textualopenai.AttachmentRef, error) {
+	if path == "" {
+		return textualopenai.AttachmentRef{}, fmt.Errorf("usage: /attach <path>")
+	}
+	ref, err := textualopenai.NewAttachmentRef(path)
+	if err != nil {
+		return ref, err
+	}
+	if ref.IsImage() && !opts.Model.SupportsVision() {
+		return ref, fmt.Errorf("termchat: model %q does not support image inputs", opts.Model.Name)
+	}
+	return ref, nil
+}
+
 // streamResponsesWithTools performs a Responses request, streaming text to stdout,
 // and automatically handles custom function tool calls by:
 //  1. capturing tool call arguments during streaming,
@@ -182,16 +337,29 @@ func streamResponsesWithTools(ctx context.Context, client textualopenai.Client,
 	input := initialInput
 	previousResponseID := ""
 
+	var prev *textualopenai.ResponsesRequest
 	for {
 		var responseID string
-		req, err := buildRequest(ctx, opts, input, previousResponseID, &responseID)
+		req, streamReq, err := buildRequest(ctx, opts, input, previousResponseID, &responseID)
 		if err != nil {
 			return full.String(), err
 		}
+		if prev != nil {
+			// Stateless: replay the whole turn, with the encrypted reasoning and the tool calls.
+			req.ContinueFrom(prev)
+		}
 
-		assistantText, headerInfos, stErr := client.StreamAndTranscodeResponses(ctx, req)
+		assistantText, headerInfos, stErr := client.StreamAndTranscodeResponses(ctx, streamReq)
 		if opts.DisplayHeaderInfos {
 			_, _ = fmt.Fprintln(os.Stdout, "\n", headerInfos.ToString())
+			if ollamaReq, ok := streamReq.(*textualopenai.OllamaChatRequest); ok {
+				if m, ok := ollamaReq.Metrics(); ok {
+					_, _ = fmt.Fprintf(os.Stdout, "ollama: %d tokens, %.1f tokens/s, load %s, total %s\n", m.EvalCount, m.TokensPerSecond(), m.LoadDuration, m.TotalDuration)
+				}
+			}
+			if opts.Redactor != nil {
+				_, _ = fmt.Fprintln(os.Stdout, opts.Redactor.AuditReport())
+			}
 		}
 		full.WriteString(assistantText)
 
@@ -204,6 +372,11 @@ func streamResponsesWithTools(ctx context.Context, client textualopenai.Client,
 			return full.String(), nil
 		}
 
+		if opts.Stateless {
+			prev, input = req, nil
+			continue
+		}
+
 		// We need the response id to continue the chain with previous_response_id.
 		if strings.TrimSpace(responseID) == "" {
 			return full.String(), fmt.Errorf("tool calls were executed but response id was not captured; cannot continue tool-calling chain")
@@ -216,26 +389,49 @@ func streamResponsesWithTools(ctx context.Context, client textualopenai.Client,
 
 // buildRequest creates and configures a textualopenai.ResponsesRequest with input data,
 // optional instructions, maximum output tokens, thinking mode, and tool wiring. Returns the
-// configured request or an error if listener/observer/tool registration fails.
+// configured request, the request to stream (a MessagesRequest, GenerateContentRequest or
+// OllamaChatRequest embedding it for the Messages, Gemini and native Ollama APIs) or an
+// error if listener/observer/tool registration fails.
 func buildRequest(
 	ctx context.Context,
 	opts sessionOptions,
 	input any,
 	previousResponseID string,
 	responseIDOut *string,
-) (*textualopenai.ResponsesRequest, error) {
-
-	req := textualopenai.NewResponsesRequest(ctx, opts.Model)
+) (*textualopenai.ResponsesRequest, textualopenai.StreamingRequest, error) {
+
+	var req *textualopenai.ResponsesRequest
+	var streamReq textualopenai.StreamingRequest
+	wire := opts.Model.ProviderInfo().WireAPI
+	switch {
+	case opts.OllamaNative:
+		ollamaReq := textualopenai.NewOllamaChatRequest(ctx, opts.Model)
+		req, streamReq = ollamaReq.ResponsesRequest, ollamaReq
+	case wire == models.WireAPIMessages:
+		messagesReq := textualopenai.NewMessagesRequest(ctx, opts.Model)
+		req, streamReq = messagesReq.ResponsesRequest, messagesReq
+	case wire == models.WireAPIGenerateContent:
+		geminiReq := textualopenai.NewGenerateContentRequest(ctx, opts.Model)
+		req, streamReq = geminiReq.ResponsesRequest, geminiReq
+	default:
+		req = textualopenai.NewResponsesRequest(ctx, opts.Model)
+		streamReq = req
+		if opts.Stateless {
+			req.UseEncryptedReasoning()
+		}
+	}
 	req.Input = input
 	req.Thinking = opts.Thinking
+	req.Reasoning = opts.Reasoning
 	req.Instructions = opts.Instructions
 	req.MaxOutputTokens = opts.MaxOutputTokens
 	req.PreviousResponseID = strings.TrimSpace(previousResponseID)
+	req.SetRedactor(opts.Redactor)
+	req.SetFunctionCallApprover(approveToolCall)
 
-	// Register custom function tools (function calling / tool calling).
-	// Only register tools when the model advertises tool support.
-	if err := registerTools(req, opts); err != nil {
-		return nil, err
+	// Attach the shared custom function tools (function calling / tool calling).
+	if opts.Tools != nil {
+		req.SetToolRegistry(opts.Tools)
 	}
 
 	// Capture the response id from the response.created event so we can chain calls
@@ -254,7 +450,7 @@ func buildRequest(
 			}
 		}, textualopenai.ResponseCreated)
 		if obsErr != nil {
-			return nil, obsErr
+			return nil, nil, obsErr
 		}
 	}
 
@@ -269,7 +465,16 @@ func buildRequest(
 		return str
 	}, textualopenai.OutputTextDelta, textualopenai.RefusalDelta, textualopenai.RefusalDone, textualopenai.ResponseFailed, textualopenai.Error)
 	if listErr != nil {
-		return nil, listErr
+		return nil, nil, listErr
+	}
+
+	// Reasoning is rendered on stderr and kept out of the answer (and of the history).
+	listErr = req.AddListeners(func(c textual.JsonGenericCarrier[textualopenai.StreamEvent]) textual.StringCarrier {
+		_, _ = fmt.Fprint(os.Stderr, c.Value.Delta)
+		return textual.StringCarrier{Index: c.Index}
+	}, textualopenai.ReasoningSummaryTextDelta, textualopenai.ReasoningTextDelta)
+	if listErr != nil {
+		return nil, nil, listErr
 	}
 
 	/*
@@ -278,9 +483,9 @@ func buildRequest(
 			_, _ = fmt.Fprint(os.Stderr, e.Value.Summary())
 		}, textualopenai.AllEvent)
 		if obsErr != nil {
-			return nil, obsErr
+			return nil, nil, obsErr
 		}*/
-	return req, nil
+	return req, streamReq, nil
 }
 
 type responseIDEnvelope struct {
@@ -298,11 +503,13 @@ func extractResponseIDFromCreatedEvent(ev textualopenai.StreamEvent) string {
 	return strings.TrimSpace(r.ID)
 }
 
-// registerTools wires custom functions into the Responses API request via function tools.
-func registerTools(req *textualopenai.ResponsesRequest, opts sessionOptions) error {
+// newToolRegistry registers the custom functions exposed to the model as function tools.
+// It returns nil when the model does not advertise tool support.
+func newToolRegistry(opts sessionOptions) (*textualopenai.ToolRegistry, error) {
 	if !opts.Model.SupportsTools() {
-		return nil
+		return nil, nil
 	}
+	tools := textualopenai.NewToolRegistry()
 
 	// JSON Schema for the get_time tool arguments.
 	schema := map[string]any{
@@ -317,23 +524,63 @@ func registerTools(req *textualopenai.ResponsesRequest, opts sessionOptions) err
 		"additionalProperties": false,
 	}
 
+	var toolOpts []textualopenai.FunctionToolOption
+	if opts.ApproveTools {
+		toolOpts = append(toolOpts, textualopenai.WithRequiresApproval())
+	}
+
 	// Strict mode is only enabled when supported by the selected provider.
+	var err error
 	if opts.Model.ProviderInfo().SupportsStrictFunctionTools {
-		return req.RegisterFunctionToolStrict(
+		err = tools.RegisterFunctionToolStrict(
 			"get_time",
 			"Get the current date and time in a given IANA time zone database name.",
 			schema,
 			true,
 			getTimeHandler,
+			toolOpts...,
+		)
+	} else {
+		err = tools.RegisterFunctionTool(
+			"get_time",
+			"Get the current date and time in a given IANA time zone database name.",
+			schema,
+			getTimeHandler,
+			toolOpts...,
 		)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return tools, nil
+}
 
-	return req.RegisterFunctionTool(
-		"get_time",
-		"Get the current date and time in a given IANA time zone database name.",
-		schema,
-		getTimeHandler,
-	)
+// approveToolCall prompts the user on the terminal before a tool requiring approval is executed.
+func approveToolCall(ctx context.Context, call textualopenai.FunctionCall) (textualopenai.ApprovalDecision, error) {
+	approvalMu.Lock()
+	defer approvalMu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return textualopenai.ApprovalDecision{}, err
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "\ntermchat: the model wants to call %s(%s). Allow? [y/N] ", call.Name, string(call.Arguments))
+	var answer string
+	select {
+	case <-ctx.Done():
+		_, _ = fmt.Fprintln(os.Stderr)
+		return textualopenai.ApprovalDecision{}, ctx.Err()
+	case line, ok := <-readStdin():
+		if !ok {
+			return textualopenai.Deny("no answer from the user"), nil
+		}
+		answer = line
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return textualopenai.Approve(), nil
+	default:
+		return textualopenai.Deny("the user refused to run this tool"), nil
+	}
 }
 
 func getTimeHandler(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
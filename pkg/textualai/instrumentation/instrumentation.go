@@ -0,0 +1,149 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package instrumentation defines the tracing and metrics hooks of the LLM calls.
+//
+// The core only depends on the Instrumentation interface: spans and histograms follow
+// the OpenTelemetry GenAI semantic conventions (attribute and metric names below), so an
+// OpenTelemetry adapter is a thin wrapper around a trace.Tracer and a metric.Meter.
+// Nop is the default; InMemory records everything for tests.
+package instrumentation
+
+import (
+	"context"
+)
+
+// Usage sample :
+// // OpenTelemetry adapter (in your module, the core does not depend on OTel).
+// type otelInstrumentation struct {
+// 	tracer trace.Tracer
+// 	meter  metric.Meter
+// }
+//
+// func (o otelInstrumentation) StartSpan(ctx context.Context, name string, kind instrumentation.SpanKind, attrs ...instrumentation.Attribute) (context.Context, instrumentation.Span) {
+// 	ctx, span := o.tracer.Start(ctx, name, trace.WithSpanKind(otelKind(kind)), trace.WithAttributes(otelAttrs(attrs)...))
+// 	return ctx, otelSpan{span}
+// }
+// ...
+// client = client.WithInstrumentation(otelInstrumentation{tracer: otel.Tracer("textualai"), meter: otel.Meter("textualai")})
+
+// GenAI semantic convention attributes.
+const (
+	AttrOperationName         = "gen_ai.operation.name" // "chat", "execute_tool"
+	AttrProviderName          = "gen_ai.provider.name"  // "openai", "anthropic", "ollama", ...
+	AttrRequestModel          = "gen_ai.request.model"
+	AttrRequestMaxTokens      = "gen_ai.request.max_tokens"
+	AttrRequestTemperature    = "gen_ai.request.temperature"
+	AttrRequestTopP           = "gen_ai.request.top_p"
+	AttrResponseID            = "gen_ai.response.id"
+	AttrResponseModel         = "gen_ai.response.model"
+	AttrResponseFinishReasons = "gen_ai.response.finish_reasons"
+	AttrUsageInputTokens      = "gen_ai.usage.input_tokens"
+	AttrUsageOutputTokens     = "gen_ai.usage.output_tokens"
+	AttrTokenType             = "gen_ai.token.type" // "input", "output" (token usage metric)
+	AttrToolName              = "gen_ai.tool.name"
+	AttrToolCallID            = "gen_ai.tool.call.id"
+	AttrToolType              = "gen_ai.tool.type" // "function", "extension"
+	AttrServerAddress         = "server.address"
+	AttrServerPort            = "server.port"
+	AttrErrorType             = "error.type"
+
+	// AttrTimeToFirstToken is the time to the first streamed delta, in seconds.
+	AttrTimeToFirstToken = "gen_ai.response.time_to_first_token"
+)
+
+// Operation names.
+const (
+	OperationChat        = "chat"
+	OperationExecuteTool = "execute_tool"
+)
+
+// Metrics (histograms).
+const (
+	MetricOperationDuration = "gen_ai.client.operation.duration"            // s
+	MetricTokenUsage        = "gen_ai.client.token.usage"                   // {token}
+	MetricTimeToFirstToken  = "gen_ai.client.operation.time_to_first_token" // s
+	MetricTokenThroughput   = "gen_ai.client.token.throughput"              // {token}/s, output tokens after the first token
+)
+
+// SpanKind is the role of a span.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = iota // tool executions
+	SpanKindClient                   // calls to a provider
+)
+
+// Attribute is a key value pair. Values are strings, bools, ints, int64s, float64s
+// or slices of strings.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attribute           { return Attribute{Key: key, Value: value} }
+func Strings(key string, value []string) Attribute { return Attribute{Key: key, Value: value} }
+func Int(key string, value int) Attribute          { return Attribute{Key: key, Value: value} }
+func Float64(key string, value float64) Attribute  { return Attribute{Key: key, Value: value} }
+func Bool(key string, value bool) Attribute        { return Attribute{Key: key, Value: value} }
+
+// Span is an operation in progress.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	AddEvent(name string, attrs ...Attribute)
+
+	// RecordError records err and marks the span as failed.
+	RecordError(err error)
+
+	// End ends the span. Later calls are ignored.
+	End()
+}
+
+// Instrumentation creates spans and records measurements.
+// Implementations must be safe for concurrent use.
+type Instrumentation interface {
+	// StartSpan starts a span, child of the span carried by ctx (if any),
+	// and returns a context carrying the new span.
+	StartSpan(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span)
+
+	// ContextWithSpan returns a copy of ctx carrying span (a span started by this Instrumentation).
+	ContextWithSpan(ctx context.Context, span Span) context.Context
+
+	// RecordHistogram records a histogram measurement (see the Metric constants).
+	RecordHistogram(ctx context.Context, name string, value float64, attrs ...Attribute)
+}
+
+// Nop returns an Instrumentation doing nothing.
+func Nop() Instrumentation {
+	return nop{}
+}
+
+type nop struct{}
+
+func (nop) StartSpan(ctx context.Context, _ string, _ SpanKind, _ ...Attribute) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+func (nop) ContextWithSpan(ctx context.Context, _ Span) context.Context {
+	return ctx
+}
+
+func (nop) RecordHistogram(context.Context, string, float64, ...Attribute) {}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(...Attribute)    {}
+func (nopSpan) AddEvent(string, ...Attribute) {}
+func (nopSpan) RecordError(error)             {}
+func (nopSpan) End()                          {}
@@ -0,0 +1,197 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrumentation
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Usage sample :
+// mem := instrumentation.NewInMemory()
+// client = client.WithInstrumentation(mem)
+// _, _, err := client.StreamAndTranscodeResponses(ctx, req)
+// for _, s := range mem.Spans() {
+// 	fmt.Println(s.Name, s.Parent, s.Attributes[instrumentation.AttrUsageOutputTokens], s.Duration())
+// }
+// for _, m := range mem.Measurements(instrumentation.MetricOperationDuration) {
+// 	fmt.Println(m.Value, m.Attributes)
+// }
+
+// SpanData is a span recorded by InMemory.
+type SpanData struct {
+	ID         int
+	Parent     int // 0 for root spans
+	Name       string
+	Kind       SpanKind
+	Start      time.Time
+	End        time.Time // zero while the span is running
+	Attributes map[string]any
+	Events     []EventData
+	Err        error
+}
+
+// Duration returns the duration of an ended span (0 while it is running).
+func (s SpanData) Duration() time.Duration {
+	if s.End.IsZero() {
+		return 0
+	}
+	return s.End.Sub(s.Start)
+}
+
+// EventData is a span event recorded by InMemory.
+type EventData struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]any
+}
+
+// Measurement is a histogram measurement recorded by InMemory.
+type Measurement struct {
+	Name       string
+	Value      float64
+	Attributes map[string]any
+	Time       time.Time
+}
+
+// InMemory is an Instrumentation keeping spans and measurements in memory (tests, debugging).
+type InMemory struct {
+	mu           sync.Mutex
+	spans        []*SpanData
+	measurements []Measurement
+}
+
+// NewInMemory returns an empty InMemory.
+func NewInMemory() *InMemory {
+	return &InMemory{}
+}
+
+type memorySpanKey struct{}
+
+// StartSpan implements Instrumentation.
+func (m *InMemory) StartSpan(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data := &SpanData{
+		ID:         len(m.spans) + 1,
+		Name:       name,
+		Kind:       kind,
+		Start:      time.Now(),
+		Attributes: attributeMap(nil, attrs),
+	}
+	if parent, ok := ctx.Value(memorySpanKey{}).(*memorySpan); ok && parent.m == m {
+		data.Parent = parent.data.ID
+	}
+	m.spans = append(m.spans, data)
+	span := &memorySpan{m: m, data: data}
+	return context.WithValue(ctx, memorySpanKey{}, span), span
+}
+
+// ContextWithSpan implements Instrumentation.
+func (m *InMemory) ContextWithSpan(ctx context.Context, span Span) context.Context {
+	if s, ok := span.(*memorySpan); ok && s.m == m {
+		return context.WithValue(ctx, memorySpanKey{}, s)
+	}
+	return ctx
+}
+
+// RecordHistogram implements Instrumentation.
+func (m *InMemory) RecordHistogram(_ context.Context, name string, value float64, attrs ...Attribute) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.measurements = append(m.measurements, Measurement{Name: name, Value: value, Attributes: attributeMap(nil, attrs), Time: time.Now()})
+}
+
+// Spans returns a copy of the recorded spans, in start order.
+func (m *InMemory) Spans() []SpanData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]SpanData, 0, len(m.spans))
+	for _, s := range m.spans {
+		c := *s
+		c.Attributes = attributeMap(s.Attributes, nil)
+		c.Events = append([]EventData(nil), s.Events...)
+		out = append(out, c)
+	}
+	return out
+}
+
+// Measurements returns the recorded measurements of the given metrics (all when names is empty).
+func (m *InMemory) Measurements(names ...string) []Measurement {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []Measurement
+	for _, ms := range m.measurements {
+		if len(names) == 0 || slices.Contains(names, ms.Name) {
+			out = append(out, ms)
+		}
+	}
+	return out
+}
+
+// Reset drops the recorded spans and measurements.
+func (m *InMemory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spans, m.measurements = nil, nil
+}
+
+type memorySpan struct {
+	m    *InMemory
+	data *SpanData
+}
+
+func (s *memorySpan) SetAttributes(attrs ...Attribute) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	s.data.Attributes = attributeMap(s.data.Attributes, attrs)
+}
+
+func (s *memorySpan) AddEvent(name string, attrs ...Attribute) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	s.data.Events = append(s.data.Events, EventData{Name: name, Time: time.Now(), Attributes: attributeMap(nil, attrs)})
+}
+
+func (s *memorySpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	s.data.Err = err
+}
+
+func (s *memorySpan) End() {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	if s.data.End.IsZero() {
+		s.data.End = time.Now()
+	}
+}
+
+// attributeMap returns a copy of base with attrs set.
+func attributeMap(base map[string]any, attrs []Attribute) map[string]any {
+	out := make(map[string]any, len(base)+len(attrs))
+	for k, v := range base {
+		out[k] = v
+	}
+	for _, a := range attrs {
+		out[a.Key] = a.Value
+	}
+	return out
+}
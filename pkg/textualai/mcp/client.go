@@ -0,0 +1,281 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/textualopenai"
+)
+
+// Usage sample :
+// client, err := mcp.ConnectStdio(ctx, "npx", "-y", "@modelcontextprotocol/server-filesystem", "/tmp")
+// if err != nil { ... }
+// defer client.Close()
+//
+// req := textualopenai.NewResponsesRequest(ctx, model)
+// // Tools are exposed as "fs__read_file", "fs__list_directory", ...
+// names, err := client.RegisterTools(ctx, req.ToolRegistry().Namespace("fs"))
+//
+// // Remote servers use the streamable HTTP transport:
+// remote, err := mcp.ConnectHTTP(ctx, "https://mcp.example.com/mcp", http.Header{"Authorization": {"Bearer " + token}})
+
+// DefaultClientInfo identifies this client during the initialization handshake.
+var DefaultClientInfo = Implementation{Name: "textualai", Version: "1.0.0"}
+
+// Client is an MCP client bound to a Transport.
+// It is safe for concurrent use once initialized.
+type Client struct {
+	transport Transport
+	info      Implementation
+	nextID    atomic.Int64
+
+	mu          sync.Mutex
+	initialized bool
+	server      InitializeResult
+}
+
+// NewClient returns a client using transport. Initialize must be called before any other method
+// (ConnectStdio and ConnectHTTP do it).
+func NewClient(transport Transport) *Client {
+	return &Client{transport: transport, info: DefaultClientInfo}
+}
+
+// ConnectStdio starts an MCP server process and performs the initialization handshake.
+// ctx only bounds the handshake: the process runs until the client is closed.
+func ConnectStdio(ctx context.Context, command string, args ...string) (*Client, error) {
+	t, err := NewStdioTransport(command, args...)
+	if err != nil {
+		return nil, err
+	}
+	return connect(ctx, t)
+}
+
+// ConnectHTTP connects to a streamable HTTP MCP server and performs the initialization handshake.
+func ConnectHTTP(ctx context.Context, endpoint string, header http.Header) (*Client, error) {
+	return connect(ctx, NewHTTPTransport(endpoint, header, nil))
+}
+
+func connect(ctx context.Context, t Transport) (*Client, error) {
+	c := NewClient(t)
+	if _, err := c.Initialize(ctx); err != nil {
+		_ = t.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetClientInfo overrides the clientInfo sent during initialization.
+func (c *Client) SetClientInfo(info Implementation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.info = info
+}
+
+// Initialize performs the MCP initialization handshake.
+func (c *Client) Initialize(ctx context.Context) (*InitializeResult, error) {
+	c.mu.Lock()
+	info := c.info
+	c.mu.Unlock()
+
+	var res InitializeResult
+	err := c.call(ctx, MethodInitialize, InitializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      info,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.transport.Notify(ctx, newNotification(MethodInitialized)); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.initialized = true
+	c.server = res
+	c.mu.Unlock()
+	return &res, nil
+}
+
+// ServerInfo returns the initialization result of the server.
+func (c *Client) ServerInfo() InitializeResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.server
+}
+
+// ListTools returns all the tools exposed by the server (following pagination).
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	cursor := ""
+	for {
+		var res ListToolsResult
+		if err := c.call(ctx, MethodToolsList, ListToolsParams{Cursor: cursor}, &res); err != nil {
+			return nil, err
+		}
+		tools = append(tools, res.Tools...)
+		if res.NextCursor == "" || res.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = res.NextCursor
+	}
+}
+
+// CallTool invokes a tool. A tool level failure is reported through CallToolResult.IsError,
+// not through the returned error.
+func (c *Client) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	if len(arguments) == 0 {
+		arguments = json.RawMessage(`{}`)
+	}
+	var res CallToolResult
+	if err := c.call(ctx, MethodToolsCall, CallToolParams{Name: name, Arguments: arguments}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Close closes the underlying transport.
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	if method != MethodInitialize {
+		c.mu.Lock()
+		initialized := c.initialized
+		c.mu.Unlock()
+		if !initialized {
+			return errors.New("mcp: client is not initialized")
+		}
+	}
+
+	req, err := newRequest(c.nextID.Add(1), method, params)
+	if err != nil {
+		return err
+	}
+	resp, err := c.transport.RoundTrip(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("mcp: decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// RegisterTools lists the server tools and registers them into reg as function tools.
+// Calls are routed to the server through CallTool; opts apply to every tool.
+//
+// Tool names that are not valid function names ([a-zA-Z0-9_-]) are sanitized.
+// Tools that cannot be registered (invalid schema, name collision) are skipped: it returns
+// the registered (qualified) names along with the joined errors of the skipped tools.
+func (c *Client) RegisterTools(ctx context.Context, reg *textualopenai.ToolRegistry, opts ...textualopenai.FunctionToolOption) ([]string, error) {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tools))
+	var errs []error
+	for _, tool := range tools {
+		if err := c.RegisterTool(reg, tool, opts...); err != nil {
+			errs = append(errs, fmt.Errorf("mcp: skip tool %q: %w", tool.Name, err))
+			continue
+		}
+		names = append(names, reg.QualifiedName(sanitizeToolName(tool.Name)))
+	}
+	return names, errors.Join(errs...)
+}
+
+// RegisterTool registers a single MCP tool into reg.
+func (c *Client) RegisterTool(reg *textualopenai.ToolRegistry, tool Tool, opts ...textualopenai.FunctionToolOption) error {
+	description := tool.Description
+	if description == "" {
+		description = tool.Title
+	}
+	var parameters any
+	if len(tool.InputSchema) > 0 {
+		parameters = tool.InputSchema
+	}
+	remote := tool.Name
+	return reg.RegisterFunctionTool(sanitizeToolName(tool.Name), description, parameters,
+		func(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+			res, err := c.CallTool(ctx, remote, args)
+			if err != nil {
+				return nil, err
+			}
+			return ToolOutput(res)
+		}, opts...)
+}
+
+// ToolOutput converts a tool result into a function call output (valid JSON):
+//   - structured content is returned as is,
+//   - text-only content is returned as a JSON string,
+//   - any other content is returned as the JSON array of content blocks.
+//
+// A result flagged IsError is returned as an error.
+func ToolOutput(res *CallToolResult) (json.RawMessage, error) {
+	text, textOnly := res.text()
+	if res.IsError {
+		if text == "" {
+			text = "tool execution failed"
+		}
+		return nil, errors.New(text)
+	}
+	if len(res.StructuredContent) > 0 {
+		return res.StructuredContent, nil
+	}
+	if textOnly {
+		return json.Marshal(text)
+	}
+	return json.Marshal(res.Content)
+}
+
+// text concatenates the text blocks; textOnly reports whether all the blocks are text.
+func (res *CallToolResult) text() (text string, textOnly bool) {
+	var sb strings.Builder
+	textOnly = true
+	for _, c := range res.Content {
+		if c.Type != "text" {
+			textOnly = false
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(c.Text)
+	}
+	return sb.String(), textOnly
+}
+
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+func sanitizeToolName(name string) string {
+	return invalidToolNameChars.ReplaceAllString(strings.TrimSpace(name), "_")
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/textualopenai"
+)
+
+// stdioServerEnv makes the test binary serve testTools over stdio (see TestMain).
+const stdioServerEnv = "MCP_TEST_STDIO_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(stdioServerEnv) == "1" {
+		if err := NewServer(testTools(), Implementation{Name: "test", Version: "1.0.0"}).ServeStdio(context.Background()); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+const echoSchema = `{"type":"object","properties":{"text":{"type":"string"}},"required":["text"]}`
+
+// testTools returns a registry with an "echo" tool (structured output) and a failing "fail" tool.
+func testTools() *textualopenai.ToolRegistry {
+	tools := textualopenai.NewToolRegistry()
+	_ = tools.RegisterFunctionTool("echo", "Echo the text", json.RawMessage(echoSchema),
+		func(_ context.Context, args json.RawMessage) (json.RawMessage, error) {
+			var in struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(args, &in); err != nil {
+				return nil, err
+			}
+			return json.Marshal(map[string]string{"echo": in.Text})
+		})
+	_ = tools.RegisterFunctionTool("fail", "Always fails", nil,
+		func(context.Context, json.RawMessage) (json.RawMessage, error) {
+			return nil, errors.New("boom")
+		})
+	return tools
+}
+
+// checkRoundTrip lists, calls and imports the tools of testTools through c.
+func checkRoundTrip(t *testing.T, c *Client) {
+	t.Helper()
+	ctx := context.Background()
+	if info := c.ServerInfo(); info.ServerInfo.Name != "test" || info.ProtocolVersion != ProtocolVersion {
+		t.Fatalf("server info = %+v", info)
+	}
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 2 || tools[0].Name != "echo" || tools[1].Name != "fail" {
+		t.Fatalf("tools = %+v", tools)
+	}
+
+	res, err := c.CallTool(ctx, "echo", json.RawMessage(`{"text":"hi"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := ToolOutput(res); err != nil || string(out) != `{"echo":"hi"}` {
+		t.Fatalf("echo = %s, %v", out, err)
+	}
+
+	// Handler failures and invalid arguments are tool errors, visible to the model.
+	for _, args := range []string{`{}`, `{"text":1}`} {
+		if res, err := c.CallTool(ctx, "echo", json.RawMessage(args)); err != nil || !res.IsError {
+			t.Fatalf("echo(%s) = %+v, %v, want a tool error", args, res, err)
+		}
+	}
+	res, err = c.CallTool(ctx, "fail", nil)
+	if err != nil || !res.IsError {
+		t.Fatalf("fail = %+v, %v", res, err)
+	}
+	if _, err := ToolOutput(res); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("ToolOutput(fail) = %v", err)
+	}
+
+	// Unknown tools are protocol errors.
+	var rpcErr *RPCError
+	if _, err := c.CallTool(ctx, "missing", nil); !errors.As(err, &rpcErr) || rpcErr.Code != CodeInvalidParams {
+		t.Fatalf("missing = %v", err)
+	}
+
+	// Imported tools are routed to the server.
+	reg := textualopenai.NewToolRegistry()
+	names, err := c.RegisterTools(ctx, reg.Namespace("remote"))
+	if err != nil || strings.Join(names, ",") != "remote__echo,remote__fail" {
+		t.Fatalf("RegisterTools = %v, %v", names, err)
+	}
+	out, err := reg.Call(ctx, "remote__echo", json.RawMessage(`{"text":"again"}`), textualopenai.ToolCallOptions{})
+	if err != nil || string(out) != `{"echo":"again"}` {
+		t.Fatalf("remote__echo = %s, %v", out, err)
+	}
+}
+
+func TestStdioRoundTrip(t *testing.T) {
+	t.Setenv(stdioServerEnv, "1") // inherited by the child process
+	c, err := ConnectStdio(context.Background(), os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, c)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListTools(context.Background()); err == nil {
+		t.Fatal("ListTools succeeded after Close")
+	}
+}
+
+func TestHTTPRoundTrip(t *testing.T) {
+	srv := httptest.NewServer(NewServer(testTools(), Implementation{Name: "test", Version: "1.0.0"}))
+	defer srv.Close()
+
+	c, err := ConnectHTTP(context.Background(), srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.transport.(*HTTPTransport).SessionID() == "" {
+		t.Fatal("no session id assigned")
+	}
+	checkRoundTrip(t, c)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientRequiresInitialize(t *testing.T) {
+	c := NewClient(NewHTTPTransport("http://127.0.0.1:0", nil, nil))
+	if _, err := c.ListTools(context.Background()); err == nil || !strings.Contains(err.Error(), "not initialized") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+// funcTransport answers requests with a function.
+type funcTransport func(msg *Message) *Message
+
+func (f funcTransport) RoundTrip(_ context.Context, msg *Message) (*Message, error) {
+	return f(msg), nil
+}
+func (f funcTransport) Notify(context.Context, *Message) error { return nil }
+func (f funcTransport) Close() error                           { return nil }
+
+func TestRegisterToolsSkipsBadTools(t *testing.T) {
+	c := NewClient(funcTransport(func(msg *Message) *Message {
+		result := `{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"bad","version":"1"}}`
+		if msg.Method == MethodToolsList {
+			result = `{"tools":[
+				{"name":"ok","inputSchema":{"type":"object"}},
+				{"name":"broken","inputSchema":{"type":"object","properties":{"x":{"$ref":"#/$defs/missing"}}}},
+				{"name":"a.b","inputSchema":{"type":"object"}},
+				{"name":"a_b","inputSchema":{"type":"object"}}
+			]}`
+		}
+		return &Message{JSONRPC: "2.0", ID: msg.ID, Result: json.RawMessage(result)}
+	}))
+	if _, err := c.Initialize(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	reg := textualopenai.NewToolRegistry()
+	names, err := c.RegisterTools(context.Background(), reg)
+	if strings.Join(names, ",") != "ok,a_b" {
+		t.Fatalf("names = %v", names)
+	}
+	if err == nil || !strings.Contains(err.Error(), `"broken"`) || !strings.Contains(err.Error(), `already registered: a_b`) {
+		t.Fatalf("err = %v", err)
+	}
+	if reg.Len() != 2 {
+		t.Fatalf("registry has %d tools, want 2", reg.Len())
+	}
+}
+
+func TestReadSSEResponse(t *testing.T) {
+	tests := []struct {
+		name, stream string
+		want         string // result, empty for an error
+	}{
+		{"single event", "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"a\":1}}\n\n", `{"a":1}`},
+		{"notification first", "data: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\ndata: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\n\n", `{}`},
+		{"other id", "data: {\"jsonrpc\":\"2.0\",\"id\":2,\"result\":{}}\n\n", ""},
+		{"multi-line data", "data: {\"jsonrpc\":\"2.0\",\ndata: \"id\":1,\ndata: \"result\":{\"b\":2}}\n\n", `{"b":2}`},
+		{"final event without blank line", "data: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"c\":3}}", `{"c":3}`},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		msg, err := readSSEResponse(strings.NewReader(tt.stream), json.RawMessage("1"))
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("%s: got %+v, want an error", tt.name, msg)
+		case tt.want != "" && (err != nil || string(msg.Result) != tt.want):
+			t.Errorf("%s: got %+v, %v, want %s", tt.name, msg, err, tt.want)
+		}
+	}
+}
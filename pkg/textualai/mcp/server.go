@@ -0,0 +1,452 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/textualopenai"
+)
+
+// Usage sample :
+// tools := textualopenai.NewToolRegistry()
+// _ = textualopenai.RegisterTypedTool(tools, "get_weather", "Current weather of a city", schema, getWeather)
+//
+// srv := mcp.NewServer(tools, mcp.Implementation{Name: "weather", Version: "1.0.0"})
+//
+// // stdio (the process is launched by the MCP host):
+// log.Fatal(srv.ServeStdio(ctx))
+//
+// // streamable HTTP:
+// srv.SetAllowedOrigins("https://app.example.com") // browser clients, if any
+// http.Handle("/mcp", srv)
+// log.Fatal(http.ListenAndServe(":8080", nil))
+//
+// Tools registered on a request are published with mcp.NewServer(req.ToolRegistry(), info).
+
+// Server publishes the tools of a textualopenai.ToolRegistry as an MCP server.
+// Definitions, JSON Schemas, handlers and safeguards are shared with the registry:
+// tools registered after the server creation are published as well.
+//
+// Server implements http.Handler (streamable HTTP transport); ServeStdio and Serve
+// implement the stdio transport.
+type Server struct {
+	tools *textualopenai.ToolRegistry
+	info  Implementation
+
+	mu           sync.Mutex
+	instructions string
+	callOptions  textualopenai.ToolCallOptions
+	origins      []string
+	sessionTTL   time.Duration
+	maxSessions  int
+	sessions     map[string]time.Time // last use
+}
+
+const (
+	// DefaultSessionTTL is the idle duration after which an HTTP session expires.
+	DefaultSessionTTL = 30 * time.Minute
+	// DefaultMaxSessions is the maximum number of live HTTP sessions;
+	// the least recently used session is evicted beyond it.
+	DefaultMaxSessions = 1024
+)
+
+// NewServer returns a server publishing tools.
+func NewServer(tools *textualopenai.ToolRegistry, info Implementation) *Server {
+	return &Server{
+		tools:       tools,
+		info:        info,
+		sessionTTL:  DefaultSessionTTL,
+		maxSessions: DefaultMaxSessions,
+		sessions:    make(map[string]time.Time),
+	}
+}
+
+// SetAllowedOrigins sets the origins (scheme://host[:port], or "*" for any) allowed to call the
+// HTTP transport. Requests with an Origin header that is neither allowed nor the requested host are
+// rejected with 403, which protects local servers against DNS rebinding.
+// Requests without Origin (non-browser clients) are always accepted.
+func (s *Server) SetAllowedOrigins(origins ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.origins = origins
+}
+
+// SetSessionLimits sets the idle duration after which HTTP sessions expire and the maximum
+// number of live sessions (DefaultSessionTTL and DefaultMaxSessions by default).
+// Non-positive values disable the corresponding limit.
+func (s *Server) SetSessionLimits(ttl time.Duration, maxSessions int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessionTTL = ttl
+	s.maxSessions = maxSessions
+}
+
+// SetInstructions sets the instructions returned to the clients during initialization.
+func (s *Server) SetInstructions(instructions string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instructions = instructions
+}
+
+// SetApprover sets the approver consulted for tools registered WithRequiresApproval.
+// Without approver such tools are denied.
+func (s *Server) SetApprover(f textualopenai.FunctionCallApprover) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callOptions.Approver = f
+}
+
+// SetCallOptions sets the settings the tools are called with: approver, default timeout
+// and output limit, redactor and instrumentation (see textualopenai.ToolCallOptions).
+func (s *Server) SetCallOptions(opts textualopenai.ToolCallOptions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callOptions = opts
+}
+
+// Handle processes a single message and returns the response (nil for notifications and responses).
+func (s *Server) Handle(ctx context.Context, msg *Message) *Message {
+	if !msg.IsRequest() {
+		return nil
+	}
+	result, err := s.dispatch(ctx, msg)
+	resp := &Message{JSONRPC: "2.0", ID: msg.ID}
+	if err != nil {
+		var rpcErr *RPCError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &RPCError{Code: CodeInternalError, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+		return resp
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		resp.Error = &RPCError{Code: CodeInternalError, Message: err.Error()}
+		return resp
+	}
+	resp.Result = b
+	return resp
+}
+
+func (s *Server) dispatch(ctx context.Context, msg *Message) (any, error) {
+	switch msg.Method {
+	case MethodInitialize:
+		var params InitializeParams
+		if len(msg.Params) > 0 {
+			if err := json.Unmarshal(msg.Params, &params); err != nil {
+				return nil, &RPCError{Code: CodeInvalidParams, Message: err.Error()}
+			}
+		}
+		s.mu.Lock()
+		instructions := s.instructions
+		s.mu.Unlock()
+		return InitializeResult{
+			// Only one revision is implemented: the client decides whether it can proceed.
+			ProtocolVersion: ProtocolVersion,
+			Capabilities:    map[string]any{"tools": map[string]any{"listChanged": false}},
+			ServerInfo:      s.info,
+			Instructions:    instructions,
+		}, nil
+
+	case MethodPing:
+		return struct{}{}, nil
+
+	case MethodToolsList:
+		return ListToolsResult{Tools: s.listTools()}, nil
+
+	case MethodToolsCall:
+		var params CallToolParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, &RPCError{Code: CodeInvalidParams, Message: err.Error()}
+		}
+		return s.callTool(ctx, params)
+
+	default:
+		return nil, &RPCError{Code: CodeMethodNotFound, Message: "method not found: " + msg.Method}
+	}
+}
+
+func (s *Server) listTools() []Tool {
+	defs := s.tools.Definitions()
+	tools := make([]Tool, 0, len(defs))
+	for _, def := range defs {
+		schema := json.RawMessage(`{"type":"object"}`)
+		if def.Parameters != nil {
+			if b, err := json.Marshal(def.Parameters); err == nil {
+				schema = b
+			}
+		}
+		tools = append(tools, Tool{
+			Name:        def.Name,
+			Description: def.Description,
+			InputSchema: schema,
+		})
+	}
+	return tools
+}
+
+// callTool runs a tool; handler failures are reported as tool errors (IsError),
+// so the calling model can see them, unknown tools as protocol errors.
+func (s *Server) callTool(ctx context.Context, params CallToolParams) (*CallToolResult, error) {
+	s.mu.Lock()
+	opts := s.callOptions
+	s.mu.Unlock()
+
+	out, err := s.tools.Call(ctx, params.Name, params.Arguments, opts)
+	if errors.Is(err, textualopenai.ErrFunctionToolNotFound) {
+		return nil, &RPCError{Code: CodeInvalidParams, Message: "unknown tool: " + params.Name}
+	}
+	if err != nil {
+		return &CallToolResult{Content: []Content{TextContent(err.Error())}, IsError: true}, nil
+	}
+	return toolResult(out), nil
+}
+
+// toolResult converts a function output into a tool result.
+// JSON objects are also returned as structured content.
+func toolResult(out json.RawMessage) *CallToolResult {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return &CallToolResult{Content: []Content{}}
+	}
+	var text string
+	if out[0] == '"' && json.Unmarshal(out, &text) == nil {
+		return &CallToolResult{Content: []Content{TextContent(text)}}
+	}
+	res := &CallToolResult{Content: []Content{TextContent(string(out))}}
+	if out[0] == '{' {
+		res.StructuredContent = out
+	}
+	return res
+}
+
+// ---------------------------------------------------------------------------------------------
+// stdio
+// ---------------------------------------------------------------------------------------------
+
+// ServeStdio serves newline-delimited JSON messages on os.Stdin / os.Stdout.
+// Nothing else must be written to os.Stdout while serving.
+func (s *Server) ServeStdio(ctx context.Context) error {
+	return s.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// Serve serves newline-delimited JSON messages read from r, writing responses to w.
+// Requests are handled concurrently. It returns when r is exhausted or ctx is canceled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		writeMu sync.Mutex
+		wg      sync.WaitGroup
+	)
+	write := func(msg *Message) {
+		b, err := json.Marshal(msg)
+		if err != nil {
+			return
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, _ = w.Write(append(b, '\n'))
+	}
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+		for sc.Scan() {
+			line := append([]byte(nil), bytes.TrimSpace(sc.Bytes())...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- sc.Err()
+		close(lines)
+	}()
+
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				return <-readErr
+			}
+			if len(line) == 0 {
+				continue
+			}
+			var msg Message
+			if err := json.Unmarshal(line, &msg); err != nil {
+				write(&Message{JSONRPC: "2.0", ID: json.RawMessage("null"),
+					Error: &RPCError{Code: CodeParseError, Message: err.Error()}})
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp := s.Handle(ctx, &msg); resp != nil {
+					write(resp)
+				}
+			}()
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------------------------
+// streamable HTTP
+// ---------------------------------------------------------------------------------------------
+
+// ServeHTTP implements the streamable HTTP transport: POSTed messages are answered with JSON.
+// A session identifier (Mcp-Session-Id) is assigned on initialization and released by DELETE,
+// expiry or eviction. Foreign origins are rejected (see SetAllowedOrigins).
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.allowedOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	sessionID := r.Header.Get("Mcp-Session-Id")
+
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		s.mu.Lock()
+		delete(s.sessions, sessionID)
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		// No server initiated stream (GET): the server never sends requests to the client.
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg Message
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxMessageSize)).Decode(&msg); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Message{JSONRPC: "2.0", ID: json.RawMessage("null"),
+			Error: &RPCError{Code: CodeParseError, Message: err.Error()}})
+		return
+	}
+
+	if msg.Method == MethodInitialize {
+		sessionID = s.openSession()
+		w.Header().Set("Mcp-Session-Id", sessionID)
+	} else if sessionID != "" {
+		if !s.touchSession(sessionID) {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+	}
+
+	resp := s.Handle(r.Context(), &msg)
+	if resp == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// allowedOrigin reports whether the Origin header of r, if any, is allowed.
+func (s *Server) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	s.mu.Lock()
+	origins := s.origins
+	s.mu.Unlock()
+	for _, allowed := range origins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host != "" && strings.EqualFold(u.Host, r.Host)
+}
+
+// openSession registers a new session, pruning expired sessions and evicting
+// the least recently used one beyond the limit.
+func (s *Server) openSession() string {
+	id := newSessionID()
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sid, last := range s.sessions {
+		if s.sessionTTL > 0 && now.Sub(last) > s.sessionTTL {
+			delete(s.sessions, sid)
+		}
+	}
+	for s.maxSessions > 0 && len(s.sessions) >= s.maxSessions {
+		oldest, oldestUse := "", now
+		for sid, last := range s.sessions {
+			if oldest == "" || last.Before(oldestUse) {
+				oldest, oldestUse = sid, last
+			}
+		}
+		delete(s.sessions, oldest)
+	}
+	s.sessions[id] = now
+	return id
+}
+
+// touchSession reports whether the session is live and records its use.
+func (s *Server) touchSession(id string) bool {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.sessions[id]
+	if !ok {
+		return false
+	}
+	if s.sessionTTL > 0 && now.Sub(last) > s.sessionTTL {
+		delete(s.sessions, id)
+		return false
+	}
+	s.sessions[id] = now
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, msg *Message) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(msg)
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("mcp: session id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
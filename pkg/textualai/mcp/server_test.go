@@ -0,0 +1,145 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// post sends a JSON-RPC message and returns the status and session identifier.
+func post(t *testing.T, url string, header map[string]string, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode, resp.Header.Get("Mcp-Session-Id")
+}
+
+const (
+	initializeBody = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`
+	pingBody       = `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+)
+
+func TestServerOrigin(t *testing.T) {
+	srv := NewServer(testTools(), Implementation{Name: "test"})
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	check := func(origin string, want int) {
+		t.Helper()
+		header := map[string]string{}
+		if origin != "" {
+			header["Origin"] = origin
+		}
+		if got, _ := post(t, ts.URL, header, pingBody); got != want {
+			t.Errorf("origin %q: status %d, want %d", origin, got, want)
+		}
+	}
+	check("", http.StatusOK)
+	check(ts.URL, http.StatusOK) // same host
+	check("http://evil.example.com", http.StatusForbidden)
+	check("null", http.StatusForbidden)
+
+	srv.SetAllowedOrigins("https://app.example.com")
+	check("https://app.example.com", http.StatusOK)
+	check("http://evil.example.com", http.StatusForbidden)
+
+	srv.SetAllowedOrigins("*")
+	check("http://evil.example.com", http.StatusOK)
+}
+
+func TestServerSessions(t *testing.T) {
+	srv := NewServer(testTools(), Implementation{Name: "test"})
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	_, sid := post(t, ts.URL, nil, initializeBody)
+	if sid == "" {
+		t.Fatal("no session id")
+	}
+	if got, _ := post(t, ts.URL, map[string]string{"Mcp-Session-Id": sid}, pingBody); got != http.StatusOK {
+		t.Fatalf("live session: status %d", got)
+	}
+	if got, _ := post(t, ts.URL, map[string]string{"Mcp-Session-Id": "unknown"}, pingBody); got != http.StatusNotFound {
+		t.Fatalf("unknown session: status %d", got)
+	}
+
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	req.Header.Set("Mcp-Session-Id", sid)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got, _ := post(t, ts.URL, map[string]string{"Mcp-Session-Id": sid}, pingBody); got != http.StatusNotFound {
+		t.Fatalf("deleted session: status %d", got)
+	}
+}
+
+func TestServerSessionLimits(t *testing.T) {
+	srv := NewServer(testTools(), Implementation{Name: "test"})
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	live := func(sid string) bool {
+		got, _ := post(t, ts.URL, map[string]string{"Mcp-Session-Id": sid}, pingBody)
+		return got == http.StatusOK
+	}
+
+	// Least recently used sessions are evicted beyond the cap.
+	srv.SetSessionLimits(0, 2)
+	_, a := post(t, ts.URL, nil, initializeBody)
+	_, b := post(t, ts.URL, nil, initializeBody)
+	time.Sleep(time.Millisecond)
+	live(a) // b is now the least recently used
+	_, c := post(t, ts.URL, nil, initializeBody)
+	if !live(a) || live(b) || !live(c) {
+		t.Fatalf("after eviction: a=%v b=%v c=%v", live(a), live(b), live(c))
+	}
+	if n := sessionCount(srv); n != 2 {
+		t.Fatalf("%d sessions, want 2", n)
+	}
+
+	// Idle sessions expire.
+	srv.SetSessionLimits(50*time.Millisecond, 0)
+	_, d := post(t, ts.URL, nil, initializeBody)
+	time.Sleep(100 * time.Millisecond)
+	if live(d) {
+		t.Fatal("idle session still live")
+	}
+	post(t, ts.URL, nil, initializeBody) // prunes a and c
+	if n := sessionCount(srv); n != 1 {
+		t.Fatalf("%d sessions after pruning, want 1", n)
+	}
+}
+
+func sessionCount(s *Server) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
@@ -0,0 +1,410 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTransportClosed is returned when a message is sent on a closed transport.
+var ErrTransportClosed = errors.New("mcp: transport closed")
+
+// maxMessageSize bounds the size of a single JSON-RPC message read from a stream.
+const maxMessageSize = 16 << 20
+
+// Transport carries JSON-RPC messages between a client and an MCP server.
+type Transport interface {
+	// RoundTrip sends a request and waits for the matching response.
+	RoundTrip(ctx context.Context, msg *Message) (*Message, error)
+	// Notify sends a notification (no response is expected).
+	Notify(ctx context.Context, msg *Message) error
+	// Close releases the transport resources.
+	Close() error
+}
+
+// ---------------------------------------------------------------------------------------------
+// stdio
+// ---------------------------------------------------------------------------------------------
+
+// StdioTransport talks to an MCP server through newline-delimited JSON messages,
+// usually on the standard input/output of a child process.
+type StdioTransport struct {
+	cmd *exec.Cmd
+	w   io.WriteCloser
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[string]chan *Message
+	done    chan struct{}
+	err     error
+}
+
+// NewStdioTransport starts command and connects to its standard input/output.
+// The child process standard error is forwarded to os.Stderr.
+// The process runs until Close, which closes its input and kills it if it does not exit.
+func NewStdioTransport(command string, args ...string) (*StdioTransport, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("mcp: stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("mcp: stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("mcp: start %s: %w", command, err)
+	}
+	t := NewStreamTransport(stdout, stdin)
+	t.cmd = cmd
+	return t, nil
+}
+
+// NewStreamTransport returns a transport exchanging newline-delimited JSON messages over r and w.
+// It is useful to connect to in-process servers (io.Pipe) or to already running processes.
+func NewStreamTransport(r io.Reader, w io.WriteCloser) *StdioTransport {
+	t := &StdioTransport{
+		w:       w,
+		pending: make(map[string]chan *Message),
+		done:    make(chan struct{}),
+	}
+	go t.readLoop(r)
+	return t
+}
+
+// RoundTrip implements Transport.
+func (t *StdioTransport) RoundTrip(ctx context.Context, msg *Message) (*Message, error) {
+	key := string(msg.ID)
+	ch := make(chan *Message, 1)
+
+	t.mu.Lock()
+	if t.err != nil {
+		err := t.err
+		t.mu.Unlock()
+		return nil, err
+	}
+	t.pending[key] = ch
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.pending, key)
+		t.mu.Unlock()
+	}()
+
+	if err := t.write(msg); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-t.done:
+		t.mu.Lock()
+		err := t.err
+		t.mu.Unlock()
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Notify implements Transport.
+func (t *StdioTransport) Notify(_ context.Context, msg *Message) error {
+	return t.write(msg)
+}
+
+// Close closes the server input and waits (briefly) for the child process to exit,
+// then kills it.
+func (t *StdioTransport) Close() error {
+	err := t.w.Close()
+	if t.cmd == nil {
+		return err
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- t.cmd.Wait() }()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		_ = t.cmd.Process.Kill()
+		<-exited
+	}
+	return err
+}
+
+func (t *StdioTransport) write(msg *Message) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("mcp: marshal message: %w", err)
+	}
+	b = append(b, '\n')
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.w.Write(b); err != nil {
+		return fmt.Errorf("mcp: write message: %w", err)
+	}
+	return nil
+}
+
+func (t *StdioTransport) readLoop(r io.Reader) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var msg Message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			// Servers may log garbage on stdout: skip it.
+			continue
+		}
+		switch {
+		case msg.IsResponse():
+			t.mu.Lock()
+			ch := t.pending[string(msg.ID)]
+			t.mu.Unlock()
+			if ch != nil {
+				ch <- &msg
+			}
+		case msg.IsRequest():
+			_ = t.write(replyToServerRequest(&msg))
+		}
+		// Notifications (progress, logging, list_changed, ...) are ignored.
+	}
+
+	err := sc.Err()
+	if err == nil {
+		err = ErrTransportClosed
+	} else {
+		err = fmt.Errorf("mcp: read message: %w", err)
+	}
+	t.mu.Lock()
+	t.err = err
+	t.mu.Unlock()
+	close(t.done)
+}
+
+// replyToServerRequest answers server initiated requests: only "ping" is supported.
+func replyToServerRequest(req *Message) *Message {
+	resp := &Message{JSONRPC: "2.0", ID: req.ID}
+	if req.Method == MethodPing {
+		resp.Result = json.RawMessage(`{}`)
+		return resp
+	}
+	resp.Error = &RPCError{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	return resp
+}
+
+// ---------------------------------------------------------------------------------------------
+// streamable HTTP
+// ---------------------------------------------------------------------------------------------
+
+// HTTPTransport implements the MCP "streamable HTTP" transport:
+// every message is POSTed to a single endpoint which answers with JSON or with an SSE stream.
+type HTTPTransport struct {
+	endpoint   string
+	httpClient *http.Client
+	header     http.Header
+
+	mu              sync.Mutex
+	sessionID       string
+	protocolVersion string
+}
+
+// NewHTTPTransport returns a transport posting messages to endpoint.
+// header is added to every request (e.g. Authorization); httpClient defaults to http.DefaultClient.
+func NewHTTPTransport(endpoint string, header http.Header, httpClient *http.Client) *HTTPTransport {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &HTTPTransport{
+		endpoint:   endpoint,
+		httpClient: httpClient,
+		header:     header.Clone(),
+	}
+}
+
+// SessionID returns the session identifier assigned by the server (empty if none).
+func (t *HTTPTransport) SessionID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sessionID
+}
+
+// RoundTrip implements Transport.
+func (t *HTTPTransport) RoundTrip(ctx context.Context, msg *Message) (*Message, error) {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out *Message
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		out, err = readSSEResponse(resp.Body, msg.ID)
+	} else {
+		out = &Message{}
+		if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+			err = fmt.Errorf("mcp: decode response: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.Method == MethodInitialize && out.Error == nil {
+		var init InitializeResult
+		if json.Unmarshal(out.Result, &init) == nil {
+			t.mu.Lock()
+			t.protocolVersion = init.ProtocolVersion
+			t.mu.Unlock()
+		}
+	}
+	return out, nil
+}
+
+// Notify implements Transport.
+func (t *HTTPTransport) Notify(ctx context.Context, msg *Message) error {
+	resp, err := t.post(ctx, msg)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// Close terminates the server session (if any).
+func (t *HTTPTransport) Close() error {
+	sessionID := t.SessionID()
+	if sessionID == "" {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodDelete, t.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(req)
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("mcp: close session: %w", err)
+	}
+	_ = resp.Body.Close()
+	return nil
+}
+
+func (t *HTTPTransport) post(ctx context.Context, msg *Message) (*http.Response, error) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: marshal message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	t.setHeaders(req)
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("mcp: post %s: %w", msg.Method, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("mcp: %s: http %d: %s", msg.Method, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if id := resp.Header.Get("Mcp-Session-Id"); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+func (t *HTTPTransport) setHeaders(req *http.Request) {
+	for k, vs := range t.header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set("MCP-Protocol-Version", t.protocolVersion)
+	}
+}
+
+// readSSEResponse reads an SSE stream until the response matching id is received.
+// The data lines of an event are joined with "\n"; the last event may end the stream
+// without a blank line.
+func readSSEResponse(r io.Reader, id json.RawMessage) (*Message, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+
+	var data []string
+	// flush dispatches the buffered event; it returns the response matching id, if any.
+	flush := func() *Message {
+		if len(data) == 0 {
+			return nil
+		}
+		var msg Message
+		err := json.Unmarshal([]byte(strings.Join(data, "\n")), &msg)
+		data = data[:0]
+		if err != nil || !msg.IsResponse() || !bytes.Equal(msg.ID, id) {
+			return nil
+		}
+		return &msg
+	}
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			// Blank line: end of event.
+			if msg := flush(); msg != nil {
+				return msg, nil
+			}
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(v, " "))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("mcp: read event stream: %w", err)
+	}
+	if msg := flush(); msg != nil {
+		return msg, nil
+	}
+	return nil, fmt.Errorf("mcp: event stream ended without a response")
+}
@@ -50,6 +50,10 @@ type Memory[I any] struct {
 	// This preserves the same semantics as using insertion time, while avoiding
 	// overwrites when time resolution causes duplicate timestamps.
 	keyFactory KeyFactory
+
+	// addProcessor optionally transforms every item before it is stored by Add
+	// (e.g. PII redaction). A nil processor stores items unchanged.
+	addProcessor func(I) I
 }
 
 // memoryJSON is the private on-disk / wire representation for Memory.
@@ -106,6 +110,9 @@ func (m *Memory[I]) Add(item ...I) {
 		}
 	}
 	for _, i := range item {
+		if m.addProcessor != nil {
+			i = m.addProcessor(i)
+		}
 		// Use a collision-proof insertion key while preserving the semantics
 		// of "insertion time as the key".
 		m.items[m.keyFactory.NowKey()] = i
@@ -113,6 +120,17 @@ func (m *Memory[I]) Add(item ...I) {
 	m.unsafePurgeIfNeeded()
 }
 
+// SetAddProcessor installs a function applied to every item passed to Add before
+// it is stored. It is typically used to redact sensitive data so it never reaches
+// the memory (nor its JSON serialization). Passing nil removes the processor.
+//
+// Items already stored are not modified.
+func (m *Memory[I]) SetAddProcessor(fn func(I) I) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.addProcessor = fn
+}
+
 // GetItems returns a shallow copy of the internal items map.
 //
 // The returned map is safe for the caller to read and iterate over
@@ -0,0 +1,77 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"context"
+	"sync"
+)
+
+// Usage sample :
+// admin := client.Ollama() // textualopenai
+// models.RegisterEnsurePresent(models.ProviderOllama, admin.EnsurePresentFunc(nil))
+// model, err := models.ResolvePresent(ctx, models.ProviderOllama, "qwen3:32b") // pulled when missing
+
+// EnsurePresentFunc makes a model available before it is used (e.g. pulls a local Ollama model).
+type EnsurePresentFunc func(ctx context.Context, m Model) error
+
+var (
+	ensurePresentMu    sync.RWMutex
+	ensurePresentFuncs = map[ProviderName]EnsurePresentFunc{}
+)
+
+// RegisterEnsurePresent installs the EnsurePresentFunc of a provider (nil removes it).
+func RegisterEnsurePresent(provider ProviderName, f EnsurePresentFunc) {
+	ensurePresentMu.Lock()
+	defer ensurePresentMu.Unlock()
+	if f == nil {
+		delete(ensurePresentFuncs, provider)
+		return
+	}
+	ensurePresentFuncs[provider] = f
+}
+
+// EnsurePresent runs the EnsurePresentFunc registered for the provider of m.
+// It is a no-op when none is registered.
+func EnsurePresent(ctx context.Context, m Model) error {
+	ensurePresentMu.RLock()
+	f := ensurePresentFuncs[m.ProviderName]
+	ensurePresentMu.RUnlock()
+	if f == nil {
+		return nil
+	}
+	return f(ctx, m)
+}
+
+// ResolvePresent resolves (provider, id) like Resolve, then ensures the model is present.
+func ResolvePresent(ctx context.Context, providerName ProviderName, id ModelID) (Model, error) {
+	m, err := Resolve(providerName, id)
+	if err != nil {
+		return Model{}, err
+	}
+	if err := EnsurePresent(ctx, m); err != nil {
+		return Model{}, err
+	}
+	return m, nil
+}
+
+// ModelFromStringPresent parses a ModelString like ModelFromString, then ensures the model is present.
+func ModelFromStringPresent(ctx context.Context, s string) (Model, error) {
+	prv, mid, err := ModelString(s).Split()
+	if err != nil {
+		return Model{}, err
+	}
+	return ResolvePresent(ctx, prv, mid)
+}
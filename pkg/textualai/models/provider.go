@@ -32,6 +32,18 @@ type ProviderInfo struct {
 	// It MUST NOT have a trailing slash.
 	DefaultBaseURL string `json:"default_base_url"`
 
+	// BaseURLEnvVar is the environment variable name used to store the base URL
+	// (for providers without a global endpoint, e.g. Azure resources). Optional.
+	BaseURLEnvVar string `json:"base_url_env_var,omitempty"`
+
+	// APIKeyHeader is the header carrying the API key (empty: "Authorization: Bearer <key>").
+	APIKeyHeader string `json:"api_key_header,omitempty"`
+
+	// URLTemplate builds the endpoint URLs from {base}, {endpoint}, {model} and client
+	// variables (e.g. "{base}/openai/{endpoint}?api-version={api_version=2025-04-01-preview}").
+	// Empty: {base}/{endpoint}.
+	URLTemplate string `json:"url_template,omitempty"`
+
 	// APIKeyRequired indicates whether this provider requires an API key for typical use.
 	// This is a convenience signal used by samples / CLI; callers may override.
 	APIKeyRequired bool `json:"api_key_required"`
@@ -47,8 +59,27 @@ type ProviderInfo struct {
 	// SupportsInstructions defines if the provider natively supports instructions.
 	// If not the request engine may transform the instruction field to a System role input.
 	SupportsInstructions bool `json:"supports_instructions"`
+
+	// SupportsHostedTools indicates whether the provider runs hosted tools
+	// (web search, file search, code interpreter, image generation).
+	SupportsHostedTools bool `json:"supports_hosted_tools"`
+
+	// WireAPI is the request protocol spoken by the provider (WireAPIResponses when empty).
+	WireAPI WireAPI `json:"wire_api,omitempty"`
 }
 
+// WireAPI identifies the request protocol of a provider.
+type WireAPI string
+
+const (
+	// WireAPIResponses is the OpenAI Responses API (/responses), also exposed by OpenAI-compatible providers.
+	WireAPIResponses WireAPI = "responses"
+	// WireAPIMessages is the Anthropic Messages API (/messages).
+	WireAPIMessages WireAPI = "messages"
+	// WireAPIGenerateContent is the Google Gemini API (models/{model}:streamGenerateContent).
+	WireAPIGenerateContent WireAPI = "generate_content"
+)
+
 // ProviderInfo returns provider metadata if the provider is registered.
 func (p ProviderName) ProviderInfo() (ProviderInfo, bool) {
 	provider, ok := providers[p]
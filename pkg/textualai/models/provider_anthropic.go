@@ -0,0 +1,100 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// Anthropic model identifiers (Messages API aliases).
+//
+// NOTE: Aliases point to the latest snapshot; pin a snapshot for reproducible results.
+const (
+	ClaudeOpus45   ModelID = "claude-opus-4-5"
+	ClaudeOpus41   ModelID = "claude-opus-4-1"
+	ClaudeOpus4    ModelID = "claude-opus-4-0"
+	ClaudeSonnet45 ModelID = "claude-sonnet-4-5"
+	ClaudeSonnet4  ModelID = "claude-sonnet-4-0"
+	ClaudeHaiku45  ModelID = "claude-haiku-4-5"
+	Claude37Sonnet ModelID = "claude-3-7-sonnet-latest"
+	Claude35Haiku  ModelID = "claude-3-5-haiku-latest"
+)
+
+// AllAnthropicModels is a curated list of Anthropic Claude models.
+//
+// Models tagged TagThinking support extended thinking (ReasoningConfig is mapped to a thinking budget).
+var AllAnthropicModels = Models{
+	{
+		ID:          ClaudeOpus45,
+		Name:        "Claude Opus 4.5",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "Most capable Claude model for complex reasoning and agentic coding.",
+		Snapshots:   []string{"claude-opus-4-5-20251101"},
+	},
+	{
+		ID:          ClaudeOpus41,
+		Name:        "Claude Opus 4.1",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "Previous Opus generation for demanding reasoning tasks.",
+		Snapshots:   []string{"claude-opus-4-1-20250805"},
+	},
+	{
+		ID:          ClaudeOpus4,
+		Name:        "Claude Opus 4",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "First Claude 4 Opus model.",
+		Snapshots:   []string{"claude-opus-4-20250514"},
+	},
+	{
+		ID:          ClaudeSonnet45,
+		Name:        "Claude Sonnet 4.5",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "Balanced Claude model for agents and coding.",
+		Snapshots:   []string{"claude-sonnet-4-5-20250929"},
+	},
+	{
+		ID:          ClaudeSonnet4,
+		Name:        "Claude Sonnet 4",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "Previous Sonnet generation.",
+		Snapshots:   []string{"claude-sonnet-4-20250514"},
+	},
+	{
+		ID:          ClaudeHaiku45,
+		Name:        "Claude Haiku 4.5",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "Fastest Claude 4.5 model, with extended thinking.",
+		Snapshots:   []string{"claude-haiku-4-5-20251001"},
+	},
+	{
+		ID:          Claude37Sonnet,
+		Name:        "Claude Sonnet 3.7",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "First Claude model with extended thinking.",
+		Snapshots:   []string{"claude-3-7-sonnet-20250219"},
+		Deprecated:  true,
+	},
+	{
+		ID:          Claude35Haiku,
+		Name:        "Claude Haiku 3.5",
+		Flavor:      "instruct",
+		Tags:        []Tag{TagCloud, TagTools},
+		Description: "Fast and cost-efficient Claude 3.5 model.",
+		Snapshots:   []string{"claude-3-5-haiku-20241022"},
+	},
+}
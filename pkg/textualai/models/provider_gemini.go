@@ -0,0 +1,67 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// Google Gemini model identifiers (Gemini API, stable aliases).
+const (
+	Gemini25Pro       ModelID = "gemini-2.5-pro"
+	Gemini25Flash     ModelID = "gemini-2.5-flash"
+	Gemini25FlashLite ModelID = "gemini-2.5-flash-lite"
+	Gemini20Flash     ModelID = "gemini-2.0-flash"
+	Gemini20FlashLite ModelID = "gemini-2.0-flash-lite"
+)
+
+// AllGeminiModels is a curated list of Google Gemini models.
+//
+// Models tagged TagThinking support thinking (ReasoningConfig is mapped to a thinking budget).
+var AllGeminiModels = Models{
+	{
+		ID:          Gemini25Pro,
+		Name:        "Gemini 2.5 Pro",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "Most capable Gemini 2.5 model for complex reasoning and coding.",
+	},
+	{
+		ID:          Gemini25Flash,
+		Name:        "Gemini 2.5 Flash",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "Balanced price/performance Gemini 2.5 model with thinking.",
+	},
+	{
+		ID:          Gemini25FlashLite,
+		Name:        "Gemini 2.5 Flash-Lite",
+		Flavor:      "thinking",
+		Tags:        []Tag{TagCloud, TagThinking, TagTools, TagVision},
+		Description: "Fastest and most cost-efficient Gemini 2.5 model.",
+	},
+	{
+		ID:          Gemini20Flash,
+		Name:        "Gemini 2.0 Flash",
+		Flavor:      "instruct",
+		Tags:        []Tag{TagCloud, TagTools, TagVision},
+		Description: "Previous generation multimodal workhorse model.",
+		Snapshots:   []string{"gemini-2.0-flash-001"},
+	},
+	{
+		ID:          Gemini20FlashLite,
+		Name:        "Gemini 2.0 Flash-Lite",
+		Flavor:      "instruct",
+		Tags:        []Tag{TagCloud, TagTools, TagVision},
+		Description: "Previous generation low-latency model.",
+		Snapshots:   []string{"gemini-2.0-flash-lite-001"},
+	},
+}
@@ -1,9 +1,12 @@
 package models
 
 const (
-	ProviderOpenAI ProviderName = "openai"
-	ProviderOllama ProviderName = "ollama"
-	ProviderXAI    ProviderName = "xai"
+	ProviderOpenAI    ProviderName = "openai"
+	ProviderOllama    ProviderName = "ollama"
+	ProviderXAI       ProviderName = "xai"
+	ProviderAnthropic ProviderName = "anthropic"
+	ProviderGemini    ProviderName = "gemini"
+	ProviderAzure     ProviderName = "azure"
 )
 
 func init() {
@@ -38,6 +41,7 @@ var providers = Providers{
 			SupportsConversation:        true,
 			SupportsStrictFunctionTools: true,
 			SupportsInstructions:        true,
+			SupportsHostedTools:         true,
 		},
 		Models: AllOpenAIModels,
 	},
@@ -51,6 +55,7 @@ var providers = Providers{
 			SupportsConversation:        false,
 			SupportsStrictFunctionTools: false,
 			SupportsInstructions:        false, // Need to rely on system role input
+			SupportsHostedTools:         false,
 		},
 		Models: AllOllamaModels,
 	},
@@ -64,7 +69,56 @@ var providers = Providers{
 			SupportsConversation:        false,
 			SupportsStrictFunctionTools: false,
 			SupportsInstructions:        false, // Need to rely on system role input
+			SupportsHostedTools:         false,
 		},
 		Models: AllXAIModels,
 	},
+	ProviderAnthropic: Provider{
+		Info: ProviderInfo{
+			Name:                        ProviderAnthropic,
+			ApiKeyEnvVar:                "ANTHROPIC_API_KEY",
+			DisplayName:                 "Anthropic",
+			DefaultBaseURL:              "https://api.anthropic.com/v1",
+			APIKeyRequired:              true,
+			SupportsConversation:        false,
+			SupportsStrictFunctionTools: false,
+			SupportsInstructions:        true, // mapped to the system prompt
+			SupportsHostedTools:         false,
+			WireAPI:                     WireAPIMessages,
+		},
+		Models: AllAnthropicModels,
+	},
+	ProviderGemini: Provider{
+		Info: ProviderInfo{
+			Name:                        ProviderGemini,
+			ApiKeyEnvVar:                "GEMINI_API_KEY",
+			DisplayName:                 "Google Gemini",
+			DefaultBaseURL:              "https://generativelanguage.googleapis.com/v1beta",
+			APIKeyRequired:              true,
+			SupportsConversation:        false,
+			SupportsStrictFunctionTools: false,
+			SupportsInstructions:        true, // mapped to the system instruction
+			SupportsHostedTools:         false,
+			WireAPI:                     WireAPIGenerateContent,
+		},
+		Models: AllGeminiModels,
+	},
+	ProviderAzure: Provider{
+		Info: ProviderInfo{
+			Name:                        ProviderAzure,
+			ApiKeyEnvVar:                "AZURE_OPENAI_API_KEY",
+			DisplayName:                 "Azure OpenAI",
+			DefaultBaseURL:              "", // https://<resource>.openai.azure.com
+			BaseURLEnvVar:               "AZURE_OPENAI_ENDPOINT",
+			APIKeyRequired:              true,
+			SupportsConversation:        false,
+			SupportsStrictFunctionTools: true,
+			SupportsInstructions:        true,
+			SupportsHostedTools:         false,
+			APIKeyHeader:                "api-key",
+			URLTemplate:                 "{base}/openai/{endpoint}?api-version={api_version=2025-04-01-preview}",
+		},
+		// Model ids are deployment names: deploy the models under their OpenAI ids.
+		Models: AllOpenAIModels,
+	},
 }
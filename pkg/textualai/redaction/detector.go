@@ -0,0 +1,212 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redaction
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Category identifies the kind of sensitive data a Detector matches.
+// It is used to build placeholders (e.g. "[REDACTED_EMAIL_1]") and audit counts.
+type Category string
+
+func (c Category) String() string {
+	return string(c)
+}
+
+// Predefined categories.
+const (
+	CategoryEmail      Category = "EMAIL"
+	CategoryPhone      Category = "PHONE"
+	CategoryCreditCard Category = "CREDIT_CARD"
+	CategoryAPIKey     Category = "API_KEY"
+)
+
+// Detector finds sensitive spans in a text.
+//
+// Find returns the [start, end) byte offsets of every match, in ascending order and
+// without overlaps (the same contract as regexp.FindAllStringIndex).
+type Detector interface {
+	Category() Category
+	Find(text string) [][]int
+}
+
+// RegexDetector is a Detector backed by a regular expression.
+//
+// Validate is optional: when set, a match is only reported if Validate returns true.
+// It is used by the built-in credit card detector (Luhn checksum) and the phone
+// detector (digit count and shape) to reduce false positives.
+type RegexDetector struct {
+	Cat      Category
+	Pattern  *regexp.Regexp
+	Validate func(match string) bool
+}
+
+// NewRegexDetector compiles pattern and returns a RegexDetector for the given category.
+func NewRegexDetector(category Category, pattern string) (RegexDetector, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return RegexDetector{}, err
+	}
+	return RegexDetector{Cat: category, Pattern: re}, nil
+}
+
+func (d RegexDetector) Category() Category {
+	return d.Cat
+}
+
+func (d RegexDetector) Find(text string) [][]int {
+	if d.Pattern == nil || text == "" {
+		return nil
+	}
+	matches := d.Pattern.FindAllStringIndex(text, -1)
+	if d.Validate == nil {
+		return matches
+	}
+	filtered := matches[:0]
+	for _, m := range matches {
+		if d.Validate(text[m[0]:m[1]]) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+var (
+	// API keys: OpenAI / Anthropic style secret keys, xAI keys, AWS access key ids,
+	// GitHub tokens, Google API keys and generic bearer tokens.
+	apiKeyPattern = regexp.MustCompile(`\b(?:sk|pk|rk)-[A-Za-z0-9_\-]{16,}|\bxai-[A-Za-z0-9]{20,}|\bAKIA[0-9A-Z]{16}\b|\bgh[pousr]_[A-Za-z0-9]{36,}|\bAIza[0-9A-Za-z_\-]{35}|(?i:\bbearer\s+)[A-Za-z0-9\-._~+/]{20,}=*`)
+
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+
+	creditCardPattern = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{1,4}\)[\s.\-]?)?\d{1,4}(?:[\s.\-]?\d{1,4}){2,6}`)
+
+	// isoDatePattern excludes dates (YYYY-MM-DD) which have the shape of a phone number.
+	isoDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+// EmailDetector returns the built-in email address detector.
+func EmailDetector() Detector {
+	return RegexDetector{Cat: CategoryEmail, Pattern: emailPattern}
+}
+
+// PhoneDetector returns the built-in phone number detector.
+// Matches are kept when they contain between 8 and 15 digits (E.164 upper bound),
+// are not ISO dates and look like a phone number: an international prefix ("+33 6 12 34 56 78"),
+// an area code in parentheses ("(555) 123-4567") or at least three groups of two digits or more
+// with a single kind of separator ("06 12 34 56 78", "555-123-4567").
+// Plain digit runs (order numbers, timestamps) and version strings are not reported.
+func PhoneDetector() Detector {
+	return RegexDetector{Cat: CategoryPhone, Pattern: phonePattern, Validate: func(match string) bool {
+		n := countDigits(match)
+		return n >= 8 && n <= 15 && !isoDatePattern.MatchString(match) && phoneLike(match)
+	}}
+}
+
+// CreditCardDetector returns the built-in payment card number detector.
+// Matches must contain 13 to 19 digits and pass the Luhn checksum.
+func CreditCardDetector() Detector {
+	return RegexDetector{Cat: CategoryCreditCard, Pattern: creditCardPattern, Validate: func(match string) bool {
+		n := countDigits(match)
+		return n >= 13 && n <= 19 && luhnValid(match)
+	}}
+}
+
+// APIKeyDetector returns the built-in detector for API-key-looking strings.
+func APIKeyDetector() Detector {
+	return RegexDetector{Cat: CategoryAPIKey, Pattern: apiKeyPattern}
+}
+
+// DefaultDetectors returns the built-in detectors in the order they are applied.
+//
+// The order matters: API keys and emails are redacted before the numeric detectors
+// so digits embedded in keys are not reported as phone numbers, and credit cards are
+// checked before phone numbers because both are digit sequences.
+func DefaultDetectors() []Detector {
+	return []Detector{
+		APIKeyDetector(),
+		EmailDetector(),
+		CreditCardDetector(),
+		PhoneDetector(),
+	}
+}
+
+// phoneLike reports whether a digit sequence has the shape of a phone number.
+func phoneLike(s string) bool {
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "(") {
+		return true
+	}
+	var sep rune
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			continue
+		}
+		if sep != 0 && r != sep {
+			return false
+		}
+		sep = r
+	}
+	groups := strings.FieldsFunc(s, func(r rune) bool { return r == sep })
+	if sep == 0 || len(groups) < 3 {
+		return false
+	}
+	for _, g := range groups {
+		if len(g) < 2 {
+			return false
+		}
+	}
+	return true
+}
+
+func countDigits(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum.
+// Non-digit characters (spaces, dashes) are ignored.
+func luhnValid(s string) bool {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+	if digits == "" {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
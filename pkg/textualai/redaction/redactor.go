@@ -0,0 +1,340 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redaction removes personally identifiable information (PII) and secrets
+// from text before it leaves the process (cloud providers, memory files, logs).
+//
+// A Redactor replaces each detected span with a stable placeholder such as
+// "[REDACTED_EMAIL_1]" and keeps a private placeholder -> original mapping so
+// tool arguments and model replies can be re-hydrated locally.
+package redaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PlaceholderPrefix starts every placeholder produced by a Redactor.
+const PlaceholderPrefix = "[REDACTED_"
+
+// placeholderPattern matches placeholders produced by Redactor.placeholderLocked.
+var placeholderPattern = regexp.MustCompile(`\[REDACTED_[A-Z0-9_]+_\d+\]`)
+
+// Redactor detects and replaces sensitive data in text.
+//
+// The same original value is always mapped to the same placeholder for the lifetime
+// of the Redactor, so a conversation keeps consistent references ("[REDACTED_EMAIL_1]"
+// means the same address in every turn).
+//
+// Redactor is safe for concurrent use.
+type Redactor struct {
+	detectors []Detector
+
+	mu sync.Mutex
+
+	// byOriginal maps an original value to its placeholder.
+	byOriginal map[string]string
+
+	// byPlaceholder maps a placeholder back to the original value.
+	byPlaceholder map[string]string
+
+	// sequences tracks the last placeholder number per category.
+	sequences map[Category]int
+
+	// counts tracks how many spans were redacted per category (audit).
+	counts map[Category]int
+}
+
+// NewRedactor returns a Redactor using the provided detectors, applied in order.
+// When no detector is provided, DefaultDetectors() is used.
+func NewRedactor(detectors ...Detector) *Redactor {
+	if len(detectors) == 0 {
+		detectors = DefaultDetectors()
+	}
+	return &Redactor{
+		detectors:     detectors,
+		byOriginal:    make(map[string]string),
+		byPlaceholder: make(map[string]string),
+		sequences:     make(map[Category]int),
+		counts:        make(map[Category]int),
+	}
+}
+
+// AddDetector appends a custom detector. It runs after the existing ones.
+func (r *Redactor) AddDetector(d Detector) {
+	if r == nil || d == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.detectors = append(r.detectors, d)
+}
+
+// Redact returns text with every detected span replaced by a placeholder.
+func (r *Redactor) Redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range r.detectors {
+		matches := d.Find(text)
+		if len(matches) == 0 {
+			continue
+		}
+		// Never redact inside an existing placeholder.
+		protected := placeholderPattern.FindAllStringIndex(text, -1)
+
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			if m[0] < last || overlaps(m, protected) {
+				continue
+			}
+			b.WriteString(text[last:m[0]])
+			b.WriteString(r.placeholderLocked(d.Category(), text[m[0]:m[1]]))
+			r.counts[d.Category()]++
+			last = m[1]
+		}
+		b.WriteString(text[last:])
+		text = b.String()
+	}
+	return text
+}
+
+// Restore replaces every known placeholder in text with its original value.
+// Unknown placeholders are left untouched.
+func (r *Redactor) Restore(text string) string {
+	if r == nil || !strings.Contains(text, PlaceholderPrefix) {
+		return text
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return placeholderPattern.ReplaceAllStringFunc(text, func(p string) string {
+		if original, ok := r.byPlaceholder[p]; ok {
+			return original
+		}
+		return p
+	})
+}
+
+// RedactValue redacts the strings contained in a decoded JSON value
+// (string, []any, map[string]any). Structural keys such as "type", "role",
+// "call_id" or binary payloads ("image_url", "file_data", ...) are preserved.
+func (r *Redactor) RedactValue(v any) any {
+	return walkStrings(v, r.Redact, nil)
+}
+
+// RestoreValue is the inverse of RedactValue.
+func (r *Redactor) RestoreValue(v any) any {
+	return walkStrings(v, r.Restore, nil)
+}
+
+// RedactJSON redacts any JSON-serializable value and returns its redacted,
+// decoded form (string, []any, map[string]any, ...). It is the generic entry
+// point used for typed payloads such as request input items.
+// The values of the keys in preserved (e.g. signatures or MIME types of a
+// provider wire format) are kept as-is, like the structural keys.
+func (r *Redactor) RedactJSON(v any, preserved ...string) (any, error) {
+	if r == nil || v == nil {
+		return v, nil
+	}
+	if s, ok := v.(string); ok {
+		return r.Redact(s), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("redaction: marshal: %w", err)
+	}
+	var decoded any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return nil, fmt.Errorf("redaction: unmarshal: %w", err)
+	}
+	var keep map[string]bool
+	if len(preserved) > 0 {
+		keep = make(map[string]bool, len(preserved))
+		for _, k := range preserved {
+			keep[k] = true
+		}
+	}
+	return walkStrings(decoded, r.Redact, keep), nil
+}
+
+// RestoreJSON restores the placeholders contained in raw JSON and returns valid JSON.
+// Strings are restored after decoding so originals are re-escaped correctly.
+func (r *Redactor) RestoreJSON(raw json.RawMessage) json.RawMessage {
+	if r == nil || len(raw) == 0 || !strings.Contains(string(raw), PlaceholderPrefix) {
+		return raw
+	}
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return raw
+	}
+	b, err := json.Marshal(r.RestoreValue(decoded))
+	if err != nil {
+		return raw
+	}
+	return b
+}
+
+// Counts returns a snapshot of the number of redacted spans per category.
+func (r *Redactor) Counts() map[Category]int {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[Category]int, len(r.counts))
+	for k, v := range r.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// AuditReport returns a human-readable summary of Counts, one category per line,
+// sorted by category name.
+func (r *Redactor) AuditReport() string {
+	counts := r.Counts()
+	categories := make([]string, 0, len(counts))
+	for c := range counts {
+		categories = append(categories, string(c))
+	}
+	sort.Strings(categories)
+	var b strings.Builder
+	for _, c := range categories {
+		_, _ = fmt.Fprintf(&b, "%s: %d\n", c, counts[Category(c)])
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Mapping returns a copy of the placeholder -> original mapping.
+//
+// The mapping contains the sensitive values: never persist or send it remotely.
+func (r *Redactor) Mapping() map[string]string {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]string, len(r.byPlaceholder))
+	for k, v := range r.byPlaceholder {
+		out[k] = v
+	}
+	return out
+}
+
+// Reset forgets every mapping and audit count.
+func (r *Redactor) Reset() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byOriginal = make(map[string]string)
+	r.byPlaceholder = make(map[string]string)
+	r.sequences = make(map[Category]int)
+	r.counts = make(map[Category]int)
+}
+
+// placeholderLocked returns the placeholder for original, allocating one if needed.
+// The caller must hold r.mu.
+func (r *Redactor) placeholderLocked(c Category, original string) string {
+	if p, ok := r.byOriginal[original]; ok {
+		return p
+	}
+	r.sequences[c]++
+	p := fmt.Sprintf("%s%s_%d]", PlaceholderPrefix, placeholderCategory(c), r.sequences[c])
+	r.byOriginal[original] = p
+	r.byPlaceholder[p] = original
+	return p
+}
+
+// placeholderCategory normalizes a category so placeholders always match placeholderPattern.
+func placeholderCategory(c Category) string {
+	s := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, string(c))
+	if s == "" {
+		return "DATA"
+	}
+	return s
+}
+
+func overlaps(m []int, spans [][]int) bool {
+	for _, s := range spans {
+		if m[0] < s[1] && s[0] < m[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// preservedKeys are JSON object keys whose string values are structural or binary
+// and must never be rewritten. Wire specific keys are passed to RedactJSON.
+var preservedKeys = map[string]struct{}{
+	"type":              {},
+	"role":              {},
+	"id":                {},
+	"call_id":           {},
+	"item_id":           {},
+	"name":              {},
+	"status":            {},
+	"detail":            {},
+	"image_url":         {},
+	"file_id":           {},
+	"file_data":         {},
+	"file_url":          {},
+	"filename":          {},
+	"data":              {},
+	"format":            {},
+	"encrypted_content": {},
+}
+
+// walkStrings applies fn to the strings of v, except the values of preservedKeys and keep.
+func walkStrings(v any, fn func(string) string, keep map[string]bool) any {
+	switch t := v.(type) {
+	case string:
+		return fn(t)
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = walkStrings(e, fn, keep)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			if _, preserved := preservedKeys[k]; preserved || keep[k] {
+				out[k] = e
+				continue
+			}
+			out[k] = walkStrings(e, fn, keep)
+		}
+		return out
+	default:
+		return v
+	}
+}
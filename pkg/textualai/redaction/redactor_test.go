@@ -0,0 +1,112 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redaction
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRedactRestoreRoundTrip(t *testing.T) {
+	r := NewRedactor()
+	text := "Mail jane@example.com or call +1 (555) 123-4567, again jane@example.com."
+	redacted := r.Redact(text)
+	if strings.Contains(redacted, "jane@example.com") || strings.Contains(redacted, "123-4567") {
+		t.Fatalf("Redact left sensitive data: %q", redacted)
+	}
+	if want := "Mail [REDACTED_EMAIL_1] or call [REDACTED_PHONE_1], again [REDACTED_EMAIL_1]."; redacted != want {
+		t.Fatalf("Redact = %q, want %q", redacted, want)
+	}
+	if got := r.Restore(redacted); got != text {
+		t.Fatalf("Restore = %q, want %q", got, text)
+	}
+	if got := r.Counts(); got[CategoryEmail] != 2 || got[CategoryPhone] != 1 {
+		t.Fatalf("Counts = %v", got)
+	}
+	// Placeholders are stable across calls and never redacted again.
+	if got := r.Redact(redacted + " jane@example.com"); got != redacted+" [REDACTED_EMAIL_1]" {
+		t.Fatalf("second Redact = %q", got)
+	}
+}
+
+func TestRedactJSONRoundTrip(t *testing.T) {
+	r := NewRedactor()
+	in := map[string]any{
+		"role":    "user",
+		"call_id": "call_555-123-4567",
+		"content": []any{map[string]any{"type": "input_text", "text": "I am jane@example.com"}},
+	}
+	redacted, err := r.RedactJSON(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := json.Marshal(redacted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "jane@example.com") {
+		t.Fatalf("RedactJSON left sensitive data: %s", raw)
+	}
+	if !strings.Contains(string(raw), `"call_id":"call_555-123-4567"`) {
+		t.Fatalf("RedactJSON changed a preserved key: %s", raw)
+	}
+	var restored map[string]any
+	if err := json.Unmarshal(r.RestoreJSON(raw), &restored); err != nil {
+		t.Fatal(err)
+	}
+	text := restored["content"].([]any)[0].(map[string]any)["text"]
+	if text != "I am jane@example.com" {
+		t.Fatalf("RestoreJSON text = %v", text)
+	}
+}
+
+func TestRedactJSONPreservedKeys(t *testing.T) {
+	r := NewRedactor()
+	in := map[string]any{"signature": "jane@example.com", "text": "jane@example.com"}
+
+	// Wire specific keys are only preserved when the caller asks for it.
+	redacted, _ := r.RedactJSON(in)
+	if m := redacted.(map[string]any); m["signature"] == "jane@example.com" || m["text"] == "jane@example.com" {
+		t.Fatalf("RedactJSON = %v", m)
+	}
+	redacted, _ = r.RedactJSON(in, "signature")
+	if m := redacted.(map[string]any); m["signature"] != "jane@example.com" || m["text"] == "jane@example.com" {
+		t.Fatalf("RedactJSON(signature) = %v", m)
+	}
+}
+
+func TestStreamRestorerSplitPlaceholder(t *testing.T) {
+	r := NewRedactor()
+	redacted := r.Redact("contact jane@example.com please")
+	s := NewStreamRestorer(r)
+	var b strings.Builder
+	// Feed the redacted text in small deltas so placeholders are split across writes.
+	for i := 0; i < len(redacted); i += 3 {
+		b.WriteString(s.Write(redacted[i:min(i+3, len(redacted))]))
+	}
+	b.WriteString(s.Flush())
+	if got := b.String(); got != "contact jane@example.com please" {
+		t.Fatalf("streamed restore = %q", got)
+	}
+}
+
+func TestStreamRestorerFlushesUnknownBracket(t *testing.T) {
+	s := NewStreamRestorer(NewRedactor())
+	got := s.Write("array[") + s.Write("0] and [REDACTED_") + s.Flush()
+	if got != "array[0] and [REDACTED_" {
+		t.Fatalf("got %q", got)
+	}
+}
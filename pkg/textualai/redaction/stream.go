@@ -0,0 +1,85 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redaction
+
+import (
+	"strings"
+	"sync"
+)
+
+// maxPlaceholderLen bounds how much text a StreamRestorer holds back while waiting
+// for the end of a potential placeholder.
+const maxPlaceholderLen = 64
+
+// StreamRestorer re-hydrates placeholders in a stream of text deltas.
+//
+// Models stream their reply in small chunks, so a placeholder can be split across
+// deltas ("[REDAC" + "TED_EMAIL_1]"). StreamRestorer holds back the trailing part of
+// a chunk that may be the beginning of a placeholder until it can be resolved.
+//
+// StreamRestorer is safe for concurrent use.
+type StreamRestorer struct {
+	redactor *Redactor
+	mu       sync.Mutex
+	pending  string
+}
+
+// NewStreamRestorer returns a StreamRestorer bound to r.
+func NewStreamRestorer(r *Redactor) *StreamRestorer {
+	return &StreamRestorer{redactor: r}
+}
+
+// Write consumes a delta and returns the restored text that can be emitted now.
+func (s *StreamRestorer) Write(delta string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	text := s.pending + delta
+	s.pending = ""
+
+	if i := strings.LastIndexByte(text, '['); i >= 0 {
+		tail := text[i:]
+		if !strings.Contains(tail, "]") && len(tail) < maxPlaceholderLen && couldBePlaceholder(tail) {
+			s.pending = tail
+			text = text[:i]
+		}
+	}
+	return s.redactor.Restore(text)
+}
+
+// Flush returns any held-back text. Call it when the text part is complete.
+func (s *StreamRestorer) Flush() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	text := s.pending
+	s.pending = ""
+	return s.redactor.Restore(text)
+}
+
+// couldBePlaceholder reports whether tail is a prefix of a placeholder.
+func couldBePlaceholder(tail string) bool {
+	if len(tail) <= len(PlaceholderPrefix) {
+		return strings.HasPrefix(PlaceholderPrefix, tail)
+	}
+	if !strings.HasPrefix(tail, PlaceholderPrefix) {
+		return false
+	}
+	for _, r := range tail[len(PlaceholderPrefix):] {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
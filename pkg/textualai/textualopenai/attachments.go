@@ -0,0 +1,161 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Usage sample :
+// ref, err := textualopenai.NewAttachmentRef("diagram.png")
+// if err != nil { ... }
+// // The history stores the reference (path + sha256), not the base64 payload.
+// history.Add(textualopenai.InputItem{Role: "user", Content: []any{textualopenai.Text("Explain this diagram"), ref}})
+//
+// // References are re-inlined when the history is replayed.
+// req.Input = textualopenai.InlineAttachments(history.GetSortedItems())
+
+// AttachmentRefType is the type of the AttachmentRef pseudo content part.
+const AttachmentRefType = "attachment_ref"
+
+// AttachmentRef references a local image or file attached to a message.
+//
+// It is meant to be stored in memories instead of the inlined content part: it must be
+// converted with Inline (or InlineAttachments) before the input is sent to the provider.
+type AttachmentRef struct {
+	Type   string `json:"type"` // always "attachment_ref"
+	Kind   string `json:"kind"` // InputImageType or InputFileType
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Detail string `json:"detail,omitempty"` // images only
+}
+
+// attachmentRefKeys are the keys of AttachmentRef kept by redaction.
+var attachmentRefKeys = []string{"kind", "path", "sha256"}
+
+// NewAttachmentRef checks that path can be attached (MIME type, size) and returns its reference.
+// Images become input_image parts, other supported files input_file parts.
+func NewAttachmentRef(path string) (AttachmentRef, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return AttachmentRef{}, fmt.Errorf("textualopenai: %w", err)
+	}
+	data, err := readInlineFile(abs, max(MaxImageSize, MaxFileSize))
+	if err != nil {
+		return AttachmentRef{}, err
+	}
+
+	ref := AttachmentRef{Type: AttachmentRefType, Path: abs, SHA256: sha256Hex(data)}
+	var part ContentPart
+	if mimeType := detectMIMEType(data, "", abs); strings.HasPrefix(mimeType, "image/") {
+		ref.Kind = InputImageType
+		ref.Detail = DetailAuto
+		part = ImageFromBytes(data, mimeType)
+	} else {
+		ref.Kind = InputFileType
+		part = FileFromBytes(filepath.Base(abs), data, mimeType)
+	}
+	if err := part.Err(); err != nil {
+		return AttachmentRef{}, err
+	}
+	return ref, nil
+}
+
+// IsImage reports whether the attachment is sent as an input_image part.
+func (a AttachmentRef) IsImage() bool {
+	return a.Kind == InputImageType
+}
+
+// Inline reads the referenced file and returns the matching content part.
+// It fails when the file was modified since the reference was created.
+func (a AttachmentRef) Inline() (ContentPart, error) {
+	data, err := readInlineFile(a.Path, max(MaxImageSize, MaxFileSize))
+	if err != nil {
+		return ContentPart{}, err
+	}
+	if a.SHA256 != "" && sha256Hex(data) != a.SHA256 {
+		return ContentPart{}, fmt.Errorf("textualopenai: %s changed since it was attached", a.Path)
+	}
+	var part ContentPart
+	if a.IsImage() {
+		part = ImageFromBytes(data, detectMIMEType(data, "", a.Path))
+		if a.Detail != "" {
+			part.Detail = a.Detail
+		}
+	} else {
+		part = FileFromBytes(filepath.Base(a.Path), data, "")
+	}
+	return part, part.Err()
+}
+
+// InlineAttachments returns a copy of items where every AttachmentRef is replaced by its
+// inlined content part. References decoded from JSON (map[string]any) are supported.
+//
+// An attachment that cannot be inlined anymore (moved, modified) is replaced by a text part
+// describing the problem, so the rest of the conversation can still be replayed.
+func InlineAttachments(items []InputItem) []InputItem {
+	out := make([]InputItem, len(items))
+	for i, item := range items {
+		out[i] = item
+		parts, ok := item.Content.([]any)
+		if !ok {
+			continue
+		}
+		inlined := make([]any, len(parts))
+		for j, p := range parts {
+			inlined[j] = inlinePart(p)
+		}
+		out[i].Content = inlined
+	}
+	return out
+}
+
+func inlinePart(p any) any {
+	var ref AttachmentRef
+	switch v := p.(type) {
+	case AttachmentRef:
+		ref = v
+	case *AttachmentRef:
+		if v == nil {
+			return p
+		}
+		ref = *v
+	case map[string]any:
+		if typ, _ := v["type"].(string); typ != AttachmentRefType {
+			return p
+		}
+		ref.Type = AttachmentRefType
+		ref.Kind, _ = v["kind"].(string)
+		ref.Path, _ = v["path"].(string)
+		ref.SHA256, _ = v["sha256"].(string)
+		ref.Detail, _ = v["detail"].(string)
+	default:
+		return p
+	}
+	part, err := ref.Inline()
+	if err != nil {
+		return Text(fmt.Sprintf("[attachment %s unavailable: %v]", filepath.Base(ref.Path), err))
+	}
+	return part
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,271 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Usage sample :
+// // Gateway: a static key header + an OAuth2 token, over mTLS.
+// oauth := textualopenai.NewOAuth2ClientCredentials("https://sso.example.com/oauth2/token", clientID, secret, "llm.invoke")
+// client = client.
+// 	WithAuthenticator(textualopenai.ChainAuth(textualopenai.HeaderAuth("X-Gateway-Key", gatewayKey), oauth)).
+// 	WithTLSConfig(&tls.Config{Certificates: []tls.Certificate{cert}})
+//
+// // Azure OpenAI with Microsoft Entra ID instead of an API key.
+// client = client.WithAuthenticator(textualopenai.AzureADClientCredentials(tenantID, clientID, secret))
+
+// Authenticator authenticates outgoing requests (headers, query parameters, ...).
+// It is called for every request, including retries; req.Context() bounds any network call.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// Invalidator is implemented by Authenticators caching credentials: after a 401 response
+// the client calls Invalidate and retries the request once.
+type Invalidator interface {
+	Invalidate()
+}
+
+// TokenSource provides bearer tokens.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// AuthenticatorFunc adapts a function to Authenticator.
+type AuthenticatorFunc func(req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BearerAuth sets "Authorization: Bearer <token>".
+func BearerAuth(token string) Authenticator {
+	return HeaderAuth("Authorization", "Bearer "+token)
+}
+
+// HeaderAuth sets a header (e.g. "X-API-Key").
+func HeaderAuth(name, value string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set(name, value)
+		return nil
+	})
+}
+
+// QueryAuth sets a query parameter (e.g. gateways expecting "?key=...").
+func QueryAuth(name, value string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set(name, value)
+		req.URL.RawQuery = q.Encode()
+		return nil
+	})
+}
+
+// TokenAuth sets the bearer token provided by ts.
+func TokenAuth(ts TokenSource) Authenticator {
+	return tokenAuth{ts: ts}
+}
+
+type tokenAuth struct {
+	ts TokenSource
+}
+
+func (a tokenAuth) Authenticate(req *http.Request) error {
+	token, err := a.ts.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (a tokenAuth) Invalidate() {
+	if inv, ok := a.ts.(Invalidator); ok {
+		inv.Invalidate()
+	}
+}
+
+// ChainAuth applies several Authenticators in order.
+func ChainAuth(auths ...Authenticator) Authenticator {
+	return chainAuth(auths)
+}
+
+type chainAuth []Authenticator
+
+func (c chainAuth) Authenticate(req *http.Request) error {
+	for _, a := range c {
+		if a == nil {
+			continue
+		}
+		if err := a.Authenticate(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c chainAuth) Invalidate() {
+	for _, a := range c {
+		if inv, ok := a.(Invalidator); ok {
+			inv.Invalidate()
+		}
+	}
+}
+
+// AzureAPIKeyAuth sets the Azure OpenAI "api-key" header.
+// The "azure" provider does it by default with AZURE_OPENAI_API_KEY.
+func AzureAPIKeyAuth(apiKey string) Authenticator {
+	return HeaderAuth("api-key", apiKey)
+}
+
+// AzureADClientCredentials returns an OAuth2 client credentials flow for Azure OpenAI
+// with Microsoft Entra ID (the cognitiveservices scope).
+func AzureADClientCredentials(tenantID, clientID, clientSecret string) *OAuth2ClientCredentials {
+	tokenURL := "https://login.microsoftonline.com/" + url.PathEscape(tenantID) + "/oauth2/v2.0/token"
+	return NewOAuth2ClientCredentials(tokenURL, clientID, clientSecret, "https://cognitiveservices.azure.com/.default")
+}
+
+// OAuth2ClientCredentials is an OAuth2 client credentials flow (RFC 6749 section 4.4).
+// The access token is cached and refreshed before it expires. It is an Authenticator
+// (bearer token), a TokenSource and an Invalidator.
+type OAuth2ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// EndpointParams are additional token request parameters (e.g. "audience", "resource").
+	EndpointParams url.Values
+
+	// BasicAuth sends the client credentials with HTTP basic authentication
+	// instead of the request body.
+	BasicAuth bool
+
+	// Leeway refreshes the token before its expiry (30s when <= 0).
+	Leeway time.Duration
+
+	// HTTPClient sends the token requests (http.DefaultClient when nil).
+	HTTPClient *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time // zero: the token does not expire
+}
+
+// NewOAuth2ClientCredentials returns a client credentials flow.
+func NewOAuth2ClientCredentials(tokenURL, clientID, clientSecret string, scopes ...string) *OAuth2ClientCredentials {
+	return &OAuth2ClientCredentials{TokenURL: tokenURL, ClientID: clientID, ClientSecret: clientSecret, Scopes: scopes}
+}
+
+// Authenticate sets the bearer token.
+func (o *OAuth2ClientCredentials) Authenticate(req *http.Request) error {
+	token, err := o.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Invalidate drops the cached token.
+func (o *OAuth2ClientCredentials) Invalidate() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.token, o.expiry = "", time.Time{}
+}
+
+// Token returns the cached token, or fetches a new one when it is missing or about to expire.
+func (o *OAuth2ClientCredentials) Token(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	leeway := o.Leeway
+	if leeway <= 0 {
+		leeway = 30 * time.Second
+	}
+	if o.token != "" && (o.expiry.IsZero() || time.Now().Add(leeway).Before(o.expiry)) {
+		return o.token, nil
+	}
+
+	form := url.Values{}
+	for k, v := range o.EndpointParams {
+		form[k] = v
+	}
+	form.Set("grant_type", "client_credentials")
+	if len(o.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.Scopes, " "))
+	}
+	if !o.BasicAuth {
+		form.Set("client_id", o.ClientID)
+		form.Set("client_secret", o.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("textualopenai: oauth2: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if o.BasicAuth {
+		req.SetBasicAuth(url.QueryEscape(o.ClientID), url.QueryEscape(o.ClientSecret))
+	}
+
+	httpClient := o.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("textualopenai: oauth2: token request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("textualopenai: oauth2: read token response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("textualopenai: oauth2: token request failed: http %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var tok struct {
+		AccessToken string          `json:"access_token"`
+		TokenType   string          `json:"token_type"`
+		ExpiresIn   json.RawMessage `json:"expires_in"` // number (or string for some providers)
+	}
+	if err := json.Unmarshal(b, &tok); err != nil {
+		return "", fmt.Errorf("textualopenai: oauth2: decode token response: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", errors.New("textualopenai: oauth2: no access_token in token response")
+	}
+	o.token, o.expiry = tok.AccessToken, time.Time{}
+	var seconds json.Number
+	if err := json.Unmarshal([]byte(strings.Trim(string(tok.ExpiresIn), `"`)), &seconds); err == nil {
+		if n, err := seconds.Int64(); err == nil && n > 0 {
+			o.expiry = time.Now().Add(time.Duration(n) * time.Second)
+		}
+	}
+	return o.token, nil
+}
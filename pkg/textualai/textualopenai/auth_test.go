@@ -0,0 +1,99 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestOAuth2ClientCredentialsCachesAndRetriesAfter401(t *testing.T) {
+	var mu sync.Mutex
+	tokens := 0
+	var form []string
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		mu.Lock()
+		tokens++
+		n := tokens
+		form = append(form, r.PostForm.Encode())
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"tok-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	defer tokenSrv.Close()
+
+	var seen []string
+	apiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		mu.Lock()
+		seen = append(seen, auth)
+		mu.Unlock()
+		if auth == "Bearer tok-1" {
+			// The first token has been revoked.
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(sseEvents(resumableEvents...)))
+	}))
+	defer apiSrv.Close()
+
+	oauth := NewOAuth2ClientCredentials(tokenSrv.URL, "client", "secret", "llm.invoke")
+	c := testClient(t, apiSrv.URL, "openai:gpt-4.1").WithAuthenticator(oauth)
+	for i := 0; i < 2; i++ {
+		req := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+		req.Input = "hi"
+		if _, _, err := c.StreamAndTranscodeResponses(context.Background(), req); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	// One token for the first attempt, one after the 401, then the cached token.
+	if tokens != 2 {
+		t.Fatalf("token requests = %d, want 2", tokens)
+	}
+	want := []string{"Bearer tok-1", "Bearer tok-2", "Bearer tok-2"}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Fatalf("Authorization = %q, want %q", seen, want)
+	}
+	if form[0] != "client_id=client&client_secret=secret&grant_type=client_credentials&scope=llm.invoke" {
+		t.Fatalf("token request form = %s", form[0])
+	}
+}
+
+func TestOAuth2ClientCredentialsTokenError(t *testing.T) {
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer tokenSrv.Close()
+
+	called := false
+	apiSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer apiSrv.Close()
+
+	c := testClient(t, apiSrv.URL, "openai:gpt-4.1").WithAuthenticator(NewOAuth2ClientCredentials(tokenSrv.URL, "client", "bad"))
+	req := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+	req.Input = "hi"
+	_, _, err := c.StreamAndTranscodeResponses(context.Background(), req)
+	if err == nil || called {
+		t.Fatalf("err = %v, API called = %v", err, called)
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Usage sample :
+// req := textualopenai.NewResponsesRequest(ctx, model)
+// req.Background = true
+// req.Input = "Write a detailed report on ..."
+// // Network blips are survived: the stream is resumed after the last seen sequence number.
+// report, _, err := client.StreamAndTranscodeResumable(ctx, req, textualopenai.ResumeOptions{MaxAttempts: 5})
+//
+// // Or poll a background response started elsewhere.
+// res, err := client.WaitResponse(ctx, responseID, 2*time.Second)
+// if res.Status == textualopenai.ResponseStatusCompleted { fmt.Println(res.OutputText()) }
+// _, err = client.CancelResponse(ctx, responseID)
+
+// ResponseStatus is the status of a response.
+type ResponseStatus string
+
+const (
+	ResponseStatusQueued     ResponseStatus = "queued"
+	ResponseStatusInProgress ResponseStatus = "in_progress"
+	ResponseStatusCompleted  ResponseStatus = "completed"
+	ResponseStatusFailed     ResponseStatus = "failed"
+	ResponseStatusCancelled  ResponseStatus = "cancelled"
+	ResponseStatusIncomplete ResponseStatus = "incomplete"
+)
+
+// IsTerminal reports whether the response will not change anymore.
+func (s ResponseStatus) IsTerminal() bool {
+	switch s {
+	case ResponseStatusCompleted, ResponseStatusFailed, ResponseStatusCancelled, ResponseStatusIncomplete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Response is a response object, as returned by the retrieve and cancel endpoints.
+type Response struct {
+	ID                string            `json:"id"`
+	Object            string            `json:"object,omitempty"` // "response"
+	CreatedAt         int64             `json:"created_at,omitempty"`
+	Status            ResponseStatus    `json:"status,omitempty"`
+	Background        bool              `json:"background,omitempty"`
+	Model             string            `json:"model,omitempty"`
+	Output            []json.RawMessage `json:"output,omitempty"` // see DecodeOutputItem
+	Error             *ResponseError    `json:"error,omitempty"`
+	IncompleteDetails json.RawMessage   `json:"incomplete_details,omitempty"`
+	Usage             json.RawMessage   `json:"usage,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+}
+
+// ResponseError describes why a response failed.
+type ResponseError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("textualopenai: response failed: %s: %s", e.Code, e.Message)
+}
+
+// OutputText returns the concatenated output_text parts of the message items.
+func (r Response) OutputText() string {
+	var b strings.Builder
+	for _, raw := range r.Output {
+		var item ConversationItem
+		if err := json.Unmarshal(raw, &item); err != nil || item.Type != "message" {
+			continue
+		}
+		for _, c := range item.Content {
+			if c.Type == "output_text" {
+				b.WriteString(c.Text)
+			}
+		}
+	}
+	return b.String()
+}
+
+// RetrieveResponse fetches a stored response (GET /responses/{id}).
+func (c Client) RetrieveResponse(ctx context.Context, responseID string) (Response, error) {
+	var res Response
+	err := c.doJSON(ctx, http.MethodGet, "responses/"+url.PathEscape(responseID), nil, nil, &res)
+	return res, err
+}
+
+// CancelResponse cancels a background response (POST /responses/{id}/cancel).
+func (c Client) CancelResponse(ctx context.Context, responseID string) (Response, error) {
+	var res Response
+	err := c.doJSON(ctx, http.MethodPost, "responses/"+url.PathEscape(responseID)+"/cancel", nil, nil, &res)
+	return res, err
+}
+
+// WaitResponse polls a background response every interval (1s when <= 0) until its status is terminal.
+func (c Client) WaitResponse(ctx context.Context, responseID string, interval time.Duration) (Response, error) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		res, err := c.RetrieveResponse(ctx, responseID)
+		if err != nil {
+			return res, err
+		}
+		if res.Status.IsTerminal() {
+			return res, nil
+		}
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ResumeStream reopens the event stream of a background response, replaying the events
+// after the startingAfter sequence number (every event when negative). Callers must close resp.Body.
+func (c Client) ResumeStream(ctx context.Context, responseID string, startingAfter int) (*http.Response, error) {
+	query := url.Values{}
+	query.Set("stream", "true")
+	if startingAfter >= 0 {
+		query.Set("starting_after", strconv.Itoa(startingAfter))
+	}
+	endpoint, err := c.endpointURL("responses/"+url.PathEscape(responseID), query)
+	if err != nil {
+		return nil, err
+	}
+	// A resumed stream holds a concurrency slot like the original one (no input tokens).
+	release, err := c.acquireRate(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.openStream(ctx, http.MethodGet, endpoint, nil, c.setAuthorization)
+	c.observeRate(resp, err)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// ResumeOptions bounds the resumption of interrupted streams.
+type ResumeOptions struct {
+	MaxAttempts int           // consecutive resumption attempts without new events (3 when <= 0)
+	Backoff     time.Duration // delay before the first attempt, doubled after each failure (1s when <= 0)
+	MaxBackoff  time.Duration // upper bound of the delay (30s when <= 0)
+}
+
+// ErrStreamInterrupted is returned when a stream could not be resumed.
+var ErrStreamInterrupted = errors.New("textualopenai: stream interrupted")
+
+// StreamAndTranscodeResumable behaves like StreamAndTranscodeResponses, but when the stream ends
+// before a terminal event it is transparently resumed from the last seen sequence number.
+//
+// Resumption requires a stored background response: Background is set and Store must not be false.
+func (c Client) StreamAndTranscodeResumable(ctx context.Context, req *ResponsesRequest, opts ResumeOptions) (_ string, headerInfos HeaderInfos, _ error) {
+	if req == nil {
+		return "", HeaderInfos{}, errors.New("textualopenai: nil ResponsesRequest")
+	}
+	if req.Store != nil && !*req.Store {
+		return "", HeaderInfos{}, errors.New("textualopenai: background responses cannot be resumed with store=false")
+	}
+	req.Background = true
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 30 * time.Second
+	}
+
+	resp, err := c.Stream(req)
+	headerInfos = HeaderInfosFromHTTPResponse(resp)
+	if err != nil {
+		return "", headerInfos, err
+	}
+	defer headerInfos.setStats(req)
+	defer func() {
+		endCall(req, ErrStreamInterrupted) // no-op after the terminal event
+		req.RemoveListeners()
+		req.RemoveObservers()
+	}()
+
+	var b strings.Builder
+	attempts, backoff := 0, opts.Backoff
+	resumedAfter := -1
+	for {
+		err := transcodeStream(ctx, req, resp.Body, &b)
+		_ = resp.Body.Close()
+		if err != nil {
+			endCall(req, err)
+			if errors.Is(err, context.Canceled) {
+				return "", headerInfos, err
+			}
+			return b.String(), headerInfos, err
+		}
+		if req.Terminated() {
+			break
+		}
+
+		// The stream ended early: resume it.
+		responseID, seq := req.ResponseID(), req.LastSequenceNumber()
+		if responseID == "" {
+			return b.String(), headerInfos, fmt.Errorf("%w: no response id received", ErrStreamInterrupted)
+		}
+		if seq > resumedAfter {
+			// The stream made progress: only consecutive failures count.
+			attempts, backoff = 0, opts.Backoff
+		}
+		resumedAfter = seq
+		for {
+			attempts++
+			if attempts > opts.MaxAttempts {
+				return b.String(), headerInfos, fmt.Errorf("%w: %s after %d resumption attempts", ErrStreamInterrupted, responseID, opts.MaxAttempts)
+			}
+			select {
+			case <-ctx.Done():
+				return b.String(), headerInfos, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff = min(2*backoff, opts.MaxBackoff)
+			if resp, err = c.ResumeStream(ctx, responseID, seq); err == nil {
+				resp.Body = streamStatsOf(req).countBody(resp.Body)
+				break
+			}
+		}
+	}
+
+	if err := req.WaitFunctionCalls(ctx); err != nil {
+		return b.String(), headerInfos, err
+	}
+	return b.String(), headerInfos, nil
+}
+
+// ResponseID returns the id of the streamed response (empty before response.created).
+func (r *ResponsesRequest) ResponseID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.responseID
+}
+
+// LastSequenceNumber returns the sequence number of the last streamed event (-1 before the first one).
+func (r *ResponsesRequest) LastSequenceNumber() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.sequenceSeen {
+		return -1
+	}
+	return r.lastSequenceNumber
+}
+
+// Terminated reports whether a terminal event (completed, failed, incomplete or error) was streamed.
+func (r *ResponsesRequest) Terminated() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.terminated
+}
+
+// trackProgress records the response id and the sequence number of ev.
+// It returns false for events already received (replayed by a resumed stream).
+func (r *ResponsesRequest) trackProgress(ev StreamEvent) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Sequence numbers start at 0: events without one (native APIs) are never dropped.
+	if ev.SequenceNumber > 0 || ev.Type == ResponseCreated {
+		if r.sequenceSeen && ev.SequenceNumber <= r.lastSequenceNumber {
+			return false
+		}
+		r.lastSequenceNumber = ev.SequenceNumber
+		r.sequenceSeen = true
+	}
+	if r.responseID == "" {
+		if ev.ResponseID != "" {
+			r.responseID = ev.ResponseID
+		} else if len(ev.Response) > 0 {
+			var res struct {
+				ID string `json:"id"`
+			}
+			if json.Unmarshal(ev.Response, &res) == nil {
+				r.responseID = res.ID
+			}
+		}
+	}
+	if ev.IsTerminal() {
+		r.terminated = true
+	}
+	return true
+}
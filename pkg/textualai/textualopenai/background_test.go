@@ -0,0 +1,115 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+)
+
+// sseEvents renders events as a server-sent events body.
+func sseEvents(events ...string) string {
+	var b strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&b, "data: %s\n\n", e)
+	}
+	return b.String()
+}
+
+var resumableEvents = []string{
+	`{"type":"response.created","sequence_number":0,"response":{"id":"resp_1","status":"in_progress"}}`,
+	`{"type":"response.output_text.delta","sequence_number":1,"delta":"Hello"}`,
+	`{"type":"response.output_text.delta","sequence_number":2,"delta":" world"}`,
+	`{"type":"response.completed","sequence_number":3,"response":{"id":"resp_1","status":"completed"}}`,
+}
+
+func TestStreamAndTranscodeResumableDropsReplayedEvents(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("starting_after"))
+		n := len(calls)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch n {
+		case 1:
+			// Cut before the first delta.
+			_, _ = w.Write([]byte(sseEvents(resumableEvents[0])))
+		case 2:
+			// Cut again, replaying the already seen response.created (sequence number 0).
+			_, _ = w.Write([]byte(sseEvents(resumableEvents[:2]...)))
+		default:
+			// Replay everything: only the unseen events must be handled.
+			_, _ = w.Write([]byte(sseEvents(resumableEvents...)))
+		}
+	}))
+	defer srv.Close()
+
+	c := testClient(t, srv.URL, "openai:gpt-4.1")
+	req := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+	req.Input = "hi"
+	_ = req.AddListeners(func(e textual.JsonGenericCarrier[StreamEvent]) textual.StringCarrier {
+		return textual.StringCarrier{Index: e.Index, Value: e.Value.Delta}
+	}, OutputTextDelta)
+	text, _, err := c.StreamAndTranscodeResumable(context.Background(), req, ResumeOptions{MaxAttempts: 1, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Hello world" {
+		t.Fatalf("text = %q, want %q", text, "Hello world")
+	}
+	// MaxAttempts bounds consecutive failures only: each resume made progress.
+	want := []string{"POST /responses ", "GET /responses/resp_1 0", "GET /responses/resp_1 1"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestStreamAndTranscodeResumableGivesUp(t *testing.T) {
+	var mu sync.Mutex
+	resumes := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if r.Method == http.MethodGet {
+			resumes++
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/event-stream")
+		// Never reaches the terminal event nor makes progress after the first event.
+		_, _ = w.Write([]byte(sseEvents(resumableEvents[0])))
+	}))
+	defer srv.Close()
+
+	c := testClient(t, srv.URL, "openai:gpt-4.1")
+	req := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+	req.Input = "hi"
+	_, _, err := c.StreamAndTranscodeResumable(context.Background(), req, ResumeOptions{MaxAttempts: 2, Backoff: time.Millisecond})
+	if !errors.Is(err, ErrStreamInterrupted) {
+		t.Fatalf("err = %v, want ErrStreamInterrupted", err)
+	}
+	if resumes != 2 {
+		t.Fatalf("resumes = %d, want 2", resumes)
+	}
+}
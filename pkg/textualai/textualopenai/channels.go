@@ -0,0 +1,163 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+)
+
+// Usage sample :
+// req.Reasoning = &textualopenai.ReasoningConfig{Effort: textualopenai.ReasoningEffortMedium, Summary: textualopenai.ReasoningSummaryAuto}
+// transcript, _, err := client.StreamAndTranscodeChunks(ctx, req, func(c textualopenai.Chunk) {
+// 	switch c.Channel {
+// 	case textualopenai.ChannelReasoning:
+// 		renderThinking(c.Text)
+// 	case textualopenai.ChannelAnswer:
+// 		renderAnswer(c.Text)
+// 	}
+// })
+// history.Add(textualopenai.InputItem{Role: "assistant", Content: transcript.Answer}) // reasoning is not stored
+
+// Channel identifies the stream a text chunk belongs to.
+type Channel string
+
+const (
+	ChannelAnswer    Channel = "answer"
+	ChannelReasoning Channel = "reasoning"
+	ChannelRefusal   Channel = "refusal"
+)
+
+// Channel returns the channel of a text event ("" for non text events).
+func (s StreamEvent) Channel() Channel {
+	switch s.Type {
+	case OutputTextDelta, TextDone:
+		return ChannelAnswer
+	case ReasoningSummaryTextDelta, ReasoningSummaryTextDone, ReasoningTextDelta, ReasoningTextDone:
+		return ChannelReasoning
+	case RefusalDelta, RefusalDone:
+		return ChannelRefusal
+	default:
+		return ""
+	}
+}
+
+// Chunk is a text chunk tagged with its channel.
+type Chunk struct {
+	Channel Channel   `json:"channel,omitempty"`
+	Type    EventType `json:"type"`
+	Text    string    `json:"text,omitempty"`
+	Err     error     `json:"-"`
+}
+
+// Transcript is the text of a response, split by channel.
+type Transcript struct {
+	Answer    string
+	Reasoning string
+	Refusal   string
+}
+
+// ChunkTranscoder returns a Transcoder emitting tagged chunks.
+//
+// Listeners define the emitted text as with Transcoder. Text deltas without listener
+// (answer, reasoning and refusal) are emitted as is, so no listener is required.
+func (r *ResponsesRequest) ChunkTranscoder() textual.TranscoderFunc[textual.JsonGenericCarrier[StreamEvent], textual.JsonGenericCarrier[Chunk]] {
+	return func(ctx context.Context, in <-chan textual.JsonGenericCarrier[StreamEvent]) <-chan textual.JsonGenericCarrier[Chunk] {
+		return textual.AsyncEmitter(ctx, in, func(ctx context.Context, c textual.JsonGenericCarrier[StreamEvent], emit func(s textual.JsonGenericCarrier[Chunk])) {
+			r.handleEvent(ctx, c, func(ev StreamEvent, s textual.StringCarrier) {
+				if s.Value == "" && s.Error == nil {
+					return
+				}
+				emit(textual.JsonGenericCarrier[Chunk]{
+					Index: s.Index,
+					Value: Chunk{Channel: ev.Channel(), Type: ev.Type, Text: s.Value, Err: s.Error},
+					Error: s.Error,
+				})
+			}, channelListener)
+		})
+	}
+}
+
+// channelListener is the default listener of text events: *.done events carry
+// text already streamed by the deltas and are skipped.
+func channelListener(c textual.JsonGenericCarrier[StreamEvent]) (textual.StringCarrier, bool) {
+	switch c.Value.Type {
+	case OutputTextDelta, ReasoningSummaryTextDelta, ReasoningTextDelta, RefusalDelta:
+		// Refusals are tagged by their channel: they are not errors here.
+		return textual.StringCarrier{Index: c.Index, Value: c.Value.Delta}, true
+	default:
+		return textual.StringCarrier{}, false
+	}
+}
+
+// StreamAndTranscodeChunks streams a response, calls onChunk (optional) for every tagged chunk
+// and returns the text accumulated per channel.
+func (c Client) StreamAndTranscodeChunks(ctx context.Context, req *ResponsesRequest, onChunk func(Chunk)) (_ Transcript, headerInfos HeaderInfos, _ error) {
+	resp, err := c.Stream(req)
+	headerInfos = HeaderInfosFromHTTPResponse(resp)
+	if err != nil {
+		return Transcript{}, headerInfos, err
+	}
+	defer headerInfos.setStats(req)
+	defer func() {
+		endCall(req, ErrStreamInterrupted) // no-op after the terminal event
+		req.RemoveListeners()
+		req.RemoveObservers()
+		_ = resp.Body.Close()
+	}()
+
+	ioT := textual.NewIOReaderTranscoder[textual.JsonGenericCarrier[StreamEvent], textual.JsonGenericCarrier[Chunk]](req.ChunkTranscoder(), resp.Body)
+	ioT.SetSplitFunc(req.SplitFunc())
+	ioT.SetContext(ctx)
+	outCh := ioT.Start()
+
+	var answer, reasoning, refusal strings.Builder
+	transcript := func() Transcript {
+		return Transcript{Answer: answer.String(), Reasoning: reasoning.String(), Refusal: refusal.String()}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			endCall(req, ctx.Err())
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return Transcript{}, headerInfos, ctx.Err()
+			}
+			return transcript(), headerInfos, ctx.Err()
+
+		case item, ok := <-outCh:
+			if !ok {
+				if err := req.WaitFunctionCalls(ctx); err != nil {
+					return transcript(), headerInfos, err
+				}
+				return transcript(), headerInfos, nil
+			}
+			chunk := item.Value
+			switch chunk.Channel {
+			case ChannelReasoning:
+				reasoning.WriteString(chunk.Text)
+			case ChannelRefusal:
+				refusal.WriteString(chunk.Text)
+			default:
+				answer.WriteString(chunk.Text)
+			}
+			if onChunk != nil {
+				onChunk(chunk)
+			}
+		}
+	}
+}
@@ -17,6 +17,7 @@ package textualopenai
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -26,7 +27,7 @@ import (
 	"os"
 	"strings"
 
-	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/instrumentation"
 	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
 )
 
@@ -39,6 +40,14 @@ type Client struct {
 	baseURL        string
 	model          models.Model
 	apiKeyRequired bool
+
+	auth        Authenticator     // replaces the API key authentication when set
+	urlTemplate string            // see ExpandURLTemplate (empty = {base}/{endpoint})
+	urlVars     map[string]string // URL template variables
+	middlewares []Middleware      // see WithMiddleware
+
+	instrumentation instrumentation.Instrumentation // see WithInstrumentation
+	rateLimiter     *RateLimiter                    // see WithRateLimiter
 }
 
 func ClientFrom(baseURL string, model models.Model, ctx context.Context) (Client, error) {
@@ -54,6 +63,9 @@ func ClientFrom(baseURL string, model models.Model, ctx context.Context) (Client
 	client.model = model
 	client.apiKey = strings.TrimSpace(firstNonEmpty(providerInfo.ApiKeyEnvVar))
 	baseURL = strings.TrimSpace(baseURL)
+	if baseURL == "" {
+		baseURL = strings.TrimSpace(firstNonEmpty(providerInfo.BaseURLEnvVar))
+	}
 	if baseURL == "" {
 		baseURL = providerInfo.DefaultBaseURL
 	}
@@ -63,6 +75,7 @@ func ClientFrom(baseURL string, model models.Model, ctx context.Context) (Client
 	}
 	client.baseURL = validUrl.String()
 	client.apiKeyRequired = providerInfo.APIKeyRequired
+	client.urlTemplate = providerInfo.URLTemplate
 	return client, nil
 }
 
@@ -71,6 +84,47 @@ func (c Client) WithApiKey(apiKey string) Client {
 	return c
 }
 
+// WithAuthenticator authenticates the requests with a (nil restores the API key authentication).
+// Provider headers set by HeaderSetter requests (e.g. anthropic-version) are kept.
+func (c Client) WithAuthenticator(a Authenticator) Client {
+	c.auth = a
+	return c
+}
+
+// WithHTTPClient sends the requests with h (proxies, custom transports, ...).
+// Keep h.Timeout to 0: streams are bounded by their context.
+func (c Client) WithHTTPClient(h *http.Client) Client {
+	if h != nil {
+		c.httpClient = h
+	}
+	return c
+}
+
+// WithTLSConfig sends the requests with a transport using cfg (e.g. client certificates for mTLS gateways).
+func (c Client) WithTLSConfig(cfg *tls.Config) Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg
+	c.httpClient = &http.Client{Transport: transport}
+	return c
+}
+
+// WithURLTemplate sets the URL template (empty keeps the current one, e.g. the provider default)
+// and merges vars into the template variables. See ExpandURLTemplate.
+func (c Client) WithURLTemplate(pattern string, vars map[string]string) Client {
+	if strings.TrimSpace(pattern) != "" {
+		c.urlTemplate = strings.TrimSpace(pattern)
+	}
+	merged := make(map[string]string, len(c.urlVars)+len(vars))
+	for k, v := range c.urlVars {
+		merged[k] = v
+	}
+	for k, v := range vars {
+		merged[k] = v
+	}
+	c.urlVars = merged
+	return c
+}
+
 func (c Client) Model() models.Model {
 	return c.model
 }
@@ -98,28 +152,98 @@ func (c Client) Stream(r Requestable) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
+	if endpoint, err = c.expandEndpoint(endpoint); err != nil {
+		return nil, err
+	}
 	bodyBytes, err := json.Marshal(r)
 	if err != nil {
 		return nil, fmt.Errorf("textualopenai: marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
+	setHeaders := c.setAuthorization
+	if hs, ok := r.(HeaderSetter); ok {
+		setHeaders = func(h http.Header) { hs.SetHeaders(h, c.apiKey) }
+	}
+	ctx := r.Context()
+	release, err := c.acquireRate(ctx, bodyBytes)
 	if err != nil {
-		return nil, fmt.Errorf("textualopenai: create request: %w", err)
+		return nil, err
+	}
+	call := c.beginCall(r, endpoint)
+	if call != nil {
+		ctx = call.ctx
+	}
+	ctx, stats := beginStreamStats(ctx, r)
+	resp, err := c.openStream(ctx, http.MethodPost, endpoint, bodyBytes, setHeaders)
+	c.observeRate(resp, err)
+	if err != nil {
+		release()
+		call.end(err)
+		return nil, err
 	}
+	resp.Body = &releasingBody{ReadCloser: stats.countBody(resp.Body), release: release}
+	return resp, nil
+}
 
-	// Authorization is optional for OpenAI-compatible providers (e.g. Ollama).
-	// When apiKeyRequired=true, ensureConfig() guarantees apiKey is present.
-	if strings.TrimSpace(c.apiKey) != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+// setAuthorization sets the API key header: the provider APIKeyHeader (e.g. Azure "api-key")
+// or the bearer token. Authorization is optional for OpenAI-compatible providers (e.g. Ollama),
+// and skipped when an Authenticator is set.
+func (c Client) setAuthorization(h http.Header) {
+	if c.auth != nil || strings.TrimSpace(c.apiKey) == "" {
+		return
+	}
+	if header := c.model.ProviderInfo().APIKeyHeader; header != "" {
+		h.Set(header, c.apiKey)
+		return
 	}
+	h.Set("Authorization", "Bearer "+c.apiKey)
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "text/event-stream")
+// do sends a request: setHeaders sets the headers, then the middlewares and the
+// Authenticator (if any) run. A 401 response to an Authenticator caching its
+// credentials (see Invalidator) is retried once with fresh credentials.
+func (c Client) do(ctx context.Context, method, endpoint string, body []byte, setHeaders func(h http.Header)) (*http.Response, error) {
+	handler := c.handler()
+	for attempt := 0; ; attempt++ {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+		if err != nil {
+			return nil, fmt.Errorf("textualopenai: create request: %w", err)
+		}
+		setHeaders(req.Header)
 
-	resp, err := c.httpClient.Do(req)
+		resp, err := handler(req)
+		if err != nil {
+			var authErr *authError
+			if errors.As(err, &authErr) {
+				return nil, fmt.Errorf("textualopenai: authenticate: %w", authErr.err)
+			}
+			return nil, fmt.Errorf("textualopenai: http request: %w", err)
+		}
+		if inv, ok := c.auth.(Invalidator); ok && resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			_ = resp.Body.Close()
+			inv.Invalidate()
+			continue
+		}
+		return resp, nil
+	}
+}
+
+// openStream sends a request accepting an SSE stream and returns the raw HTTP response.
+// body is optional; setHeaders sets the authentication headers. Callers must close resp.Body.
+func (c Client) openStream(ctx context.Context, method, endpoint string, body []byte, setHeaders func(h http.Header)) (*http.Response, error) {
+	resp, err := c.do(ctx, method, endpoint, body, func(h http.Header) {
+		setHeaders(h)
+		if body != nil {
+			h.Set("Content-Type", "application/json")
+		}
+		h.Set("Accept", "text/event-stream")
+	})
 	if err != nil {
-		return nil, fmt.Errorf("textualopenai: http request: %w", err)
+		return nil, err
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
@@ -129,46 +253,146 @@ func (c Client) Stream(r Requestable) (*http.Response, error) {
 		if msg == "" {
 			msg = resp.Status
 		}
-		return nil, fmt.Errorf("textualopenai: responses stream failed: http %d: %s", resp.StatusCode, msg)
+		return nil, &StreamError{StatusCode: resp.StatusCode, Message: msg, Header: resp.Header}
 	}
 
 	return resp, nil
 }
 
-func (c Client) StreamAndTranscodeResponses(ctx context.Context, req *ResponsesRequest) (string, HeaderInfos, error) {
+// StreamError is the error returned when a stream is refused (non-2xx response).
+type StreamError struct {
+	StatusCode int
+	Message    string      // response body or status
+	Header     http.Header // response headers (Retry-After, rate limits, ...)
+}
+
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("textualopenai: responses stream failed: http %d: %s", e.StatusCode, e.Message)
+}
+
+// APIError is the error returned for a non-2xx response of a JSON endpoint.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string // response body or status
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("textualopenai: %s %s failed: http %d: %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// doJSON sends a non-streaming JSON request to path (relative to the base URL) and decodes
+// the response body into out (optional). in (optional) is sent as the JSON body.
+func (c Client) doJSON(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	resp, err := c.send(ctx, method, path, query, in)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("textualopenai: read response: %w", err)
+	}
+	if out == nil || len(b) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("textualopenai: decode response: %w", err)
+	}
+	return nil
+}
+
+// send sends a JSON request to path (relative to the base URL) and returns the response
+// when its status is 2xx (an *APIError otherwise). Callers must close resp.Body.
+func (c Client) send(ctx context.Context, method, path string, query url.Values, in any) (*http.Response, error) {
+	endpoint, err := c.endpointURL(path, query)
+	if err != nil {
+		return nil, err
+	}
+	var body []byte
+	if in != nil {
+		if body, err = json.Marshal(in); err != nil {
+			return nil, fmt.Errorf("textualopenai: marshal request: %w", err)
+		}
+	}
+	resp, err := c.do(ctx, method, endpoint, body, func(h http.Header) {
+		c.setAuthorization(h)
+		if in != nil {
+			h.Set("Content-Type", "application/json")
+		}
+		h.Set("Accept", "application/json")
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		msg := strings.TrimSpace(string(b))
+		if msg == "" {
+			msg = resp.Status
+		}
+		return nil, &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: msg}
+	}
+	return resp, nil
+}
+
+// StreamAndTranscodeResponses streams a response and returns the transcoded text.
+// The returned HeaderInfos carry the StreamStats of the stream.
+func (c Client) StreamAndTranscodeResponses(ctx context.Context, req StreamingRequest) (string, HeaderInfos, error) {
 	resp, err := c.Stream(req)
-	headerInfos := HeaderInfosFromHTTPResponse(resp)
 	if err != nil {
-		return "", headerInfos, err
+		return "", HeaderInfosFromHTTPResponse(resp), err
 	}
+	return transcodeResponse(ctx, req, resp)
+}
+
+// transcodeResponse transcodes an opened stream (see StreamAndTranscodeResponses) and closes its body.
+func transcodeResponse(ctx context.Context, req StreamingRequest, resp *http.Response) (_ string, headerInfos HeaderInfos, _ error) {
+	headerInfos = HeaderInfosFromHTTPResponse(resp)
+	defer headerInfos.setStats(req)
 	defer func() {
+		// No-op when the terminal event (or an error) already ended the call span.
+		endCall(req, ErrStreamInterrupted)
 		req.RemoveListeners()
 		req.RemoveObservers()
 		_ = resp.Body.Close()
 	}()
 
-	// Apply the transcoder func to the body split by SSE event.
-	ioT := textual.NewIOReaderTranscoder[textual.JsonGenericCarrier[StreamEvent], textual.StringCarrier](req.Transcoder(), resp.Body)
-	ioT.SetSplitFunc(req.SplitFunc())
-	ioT.SetContext(ctx)
-	outCh := ioT.Start()
+	var b strings.Builder
+	if err := transcodeStream(ctx, req, resp.Body, &b); err != nil {
+		endCall(req, err)
+		if errors.Is(err, context.Canceled) {
+			return "", headerInfos, err
+		}
+		return b.String(), headerInfos, err
+	}
+	// Function handlers run concurrently with the stream: collect them all
+	// so FunctionCallOutputs is complete when we return.
+	if err := req.WaitFunctionCalls(ctx); err != nil {
+		return b.String(), headerInfos, err
+	}
+	return b.String(), headerInfos, nil
+}
+
+// transcodeStream transcodes body with the request and accumulates the emitted values
+// into b until the body is consumed or ctx is done.
+func transcodeStream(ctx context.Context, req StreamingRequest, body io.Reader, b *strings.Builder) error {
+	outCh := req.TranscodeStream(ctx, body)
 
 	// To accumulate the values, we Consume the response channel
-	var b strings.Builder
 	for {
 		select {
 		case <-ctx.Done():
-			if errors.Is(ctx.Err(), context.Canceled) {
-				return "", headerInfos, ctx.Err()
-			}
-			return b.String(), headerInfos, ctx.Err()
+			return ctx.Err()
 
 		case item, ok := <-outCh:
-			b.WriteString(item.Value)
 			if !ok {
-				// Return the accumulated string
-				return b.String(), headerInfos, nil // stream finished normally
+				return nil // stream finished normally
 			}
+			b.WriteString(item.Value)
 		}
 	}
 }
@@ -0,0 +1,337 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Usage sample :
+// req.Input = []textualopenai.InputItem{
+// 	textualopenai.UserMessage(
+// 		textualopenai.Text("What is on this receipt? Check the totals against the contract."),
+// 		textualopenai.ImageFromFile("receipt.jpg").WithDetail(textualopenai.DetailHigh),
+// 		textualopenai.FileFromPath("contract.pdf"),
+// 	),
+// }
+// // Builders never fail: errors (unreadable file, unsupported MIME type, size limits)
+// // are reported by req.Validate(), like images sent to a model without vision support.
+
+// Content part types.
+const (
+	InputTextType  = "input_text"
+	InputImageType = "input_image"
+	InputFileType  = "input_file"
+	InputAudioType = "input_audio"
+)
+
+// Image detail levels.
+const (
+	DetailLow  = "low"
+	DetailHigh = "high"
+	DetailAuto = "auto"
+)
+
+// Size limits applied to inlined content (bytes before base64 encoding).
+var (
+	MaxImageSize = 20 << 20
+	MaxFileSize  = 32 << 20
+	MaxAudioSize = 25 << 20
+)
+
+// SupportedImageMIMETypes lists the accepted image MIME types.
+var SupportedImageMIMETypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+	"image/gif":  true,
+}
+
+// SupportedFileMIMETypes lists the accepted input_file MIME types.
+var SupportedFileMIMETypes = map[string]bool{
+	"application/pdf":  true,
+	"application/json": true,
+	"text/plain":       true,
+	"text/markdown":    true,
+	"text/csv":         true,
+	"text/html":        true,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
+}
+
+// SupportedAudioFormats maps audio MIME types to input_audio formats.
+var SupportedAudioFormats = map[string]string{
+	"audio/wav":   "wav",
+	"audio/x-wav": "wav",
+	"audio/wave":  "wav",
+	"audio/mpeg":  "mp3",
+	"audio/mp3":   "mp3",
+}
+
+// ContentPart is a typed multimodal content part of an input message.
+type ContentPart struct {
+	Type string `json:"type"`
+
+	// input_text
+	Text string `json:"text,omitempty"`
+
+	// input_image: ImageURL is a URL or a base64 data URL.
+	ImageURL string `json:"image_url,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+
+	// input_image / input_file: uploaded file id.
+	FileID string `json:"file_id,omitempty"`
+
+	// input_file: FileData is a base64 data URL.
+	Filename string `json:"filename,omitempty"`
+	FileData string `json:"file_data,omitempty"`
+	FileURL  string `json:"file_url,omitempty"`
+
+	// input_audio
+	InputAudio *InputAudio `json:"input_audio,omitempty"`
+
+	err error // deferred builder error, reported by Validate
+}
+
+// InputAudio is the payload of an input_audio part.
+type InputAudio struct {
+	Data   string `json:"data"`   // base64
+	Format string `json:"format"` // "wav" or "mp3"
+}
+
+// Err returns the error met while building the part (nil if the part is valid).
+func (p ContentPart) Err() error {
+	return p.err
+}
+
+// WithDetail sets the detail level of an image part.
+func (p ContentPart) WithDetail(detail string) ContentPart {
+	p.Detail = detail
+	return p
+}
+
+// Message returns an input message made of parts.
+func Message(role string, parts ...ContentPart) InputItem {
+	return InputItem{Role: role, Content: parts}
+}
+
+// UserMessage returns a "user" input message made of parts.
+func UserMessage(parts ...ContentPart) InputItem {
+	return Message("user", parts...)
+}
+
+// Text returns an input_text part.
+func Text(text string) ContentPart {
+	return ContentPart{Type: InputTextType, Text: text}
+}
+
+// ImageFromURL returns an input_image part referencing a remote image.
+func ImageFromURL(url string) ContentPart {
+	return ContentPart{Type: InputImageType, ImageURL: url, Detail: DetailAuto}
+}
+
+// ImageFromFileID returns an input_image part referencing an uploaded file.
+func ImageFromFileID(fileID string) ContentPart {
+	return ContentPart{Type: InputImageType, FileID: fileID, Detail: DetailAuto}
+}
+
+// ImageFromBytes returns an input_image part inlining data as a base64 data URL.
+// An empty mimeType is detected from the content.
+func ImageFromBytes(data []byte, mimeType string) ContentPart {
+	part := ContentPart{Type: InputImageType, Detail: DetailAuto}
+	mimeType = detectMIMEType(data, mimeType, "")
+	if err := checkInline("image", data, mimeType, MaxImageSize, SupportedImageMIMETypes[mimeType]); err != nil {
+		part.err = err
+		return part
+	}
+	part.ImageURL = dataURL(mimeType, data)
+	return part
+}
+
+// ImageFromFile returns an input_image part inlining a local image.
+func ImageFromFile(path string) ContentPart {
+	data, err := readInlineFile(path, MaxImageSize)
+	if err != nil {
+		return ContentPart{Type: InputImageType, err: err}
+	}
+	return ImageFromBytes(data, detectMIMEType(data, "", path))
+}
+
+// FileFromID returns an input_file part referencing an uploaded file.
+func FileFromID(fileID string) ContentPart {
+	return ContentPart{Type: InputFileType, FileID: fileID}
+}
+
+// FileFromURL returns an input_file part referencing a remote file.
+func FileFromURL(url string) ContentPart {
+	return ContentPart{Type: InputFileType, FileURL: url}
+}
+
+// FileFromBytes returns an input_file part inlining data as a base64 data URL.
+// An empty mimeType is detected from the filename extension, then from the content.
+func FileFromBytes(filename string, data []byte, mimeType string) ContentPart {
+	part := ContentPart{Type: InputFileType, Filename: filename}
+	mimeType = detectMIMEType(data, mimeType, filename)
+	if err := checkInline("file", data, mimeType, MaxFileSize, SupportedFileMIMETypes[mimeType]); err != nil {
+		part.err = err
+		return part
+	}
+	part.FileData = dataURL(mimeType, data)
+	return part
+}
+
+// FileFromPath returns an input_file part inlining a local file.
+func FileFromPath(path string) ContentPart {
+	data, err := readInlineFile(path, MaxFileSize)
+	if err != nil {
+		return ContentPart{Type: InputFileType, Filename: filepath.Base(path), err: err}
+	}
+	return FileFromBytes(filepath.Base(path), data, "")
+}
+
+// AudioFromBytes returns an input_audio part. format is "wav" or "mp3" (detected when empty).
+func AudioFromBytes(data []byte, format string) ContentPart {
+	part := ContentPart{Type: InputAudioType}
+	if format == "" {
+		format = SupportedAudioFormats[detectMIMEType(data, "", "")]
+	}
+	if format != "wav" && format != "mp3" {
+		part.err = fmt.Errorf("textualopenai: unsupported audio format %q (wav or mp3)", format)
+		return part
+	}
+	if len(data) > MaxAudioSize {
+		part.err = fmt.Errorf("textualopenai: audio is %d bytes, the limit is %d bytes", len(data), MaxAudioSize)
+		return part
+	}
+	part.InputAudio = &InputAudio{Data: base64.StdEncoding.EncodeToString(data), Format: format}
+	return part
+}
+
+// AudioFromFile returns an input_audio part inlining a local audio file.
+func AudioFromFile(path string) ContentPart {
+	data, err := readInlineFile(path, MaxAudioSize)
+	if err != nil {
+		return ContentPart{Type: InputAudioType, err: err}
+	}
+	return AudioFromBytes(data, SupportedAudioFormats[detectMIMEType(data, "", path)])
+}
+
+// ─────────────────────────────────────────────────────────────
+// Validation
+// ─────────────────────────────────────────────────────────────
+
+// validateContentParts reports the first invalid part of the request input and rejects
+// images when the model is known not to support vision.
+func (r *ResponsesRequest) validateContentParts() error {
+	// Models without metadata (custom ids) are not gated.
+	known := r.model.Flavor != "" || len(r.model.Tags) > 0
+	for _, part := range inputContentParts(r.Input) {
+		if part.err != nil {
+			return part.err
+		}
+		if part.Type == InputImageType && known && !r.model.SupportsVision() {
+			return fmt.Errorf("textualopenai: model %s does not support image inputs", r.model.ID)
+		}
+	}
+	return nil
+}
+
+// errAttachmentNotInlined is reported when an AttachmentRef reaches the request input.
+var errAttachmentNotInlined = errors.New("textualopenai: attachment references must be inlined before sending (see InlineAttachments)")
+
+// inputContentParts collects the typed content parts of an input.
+func inputContentParts(input any) []ContentPart {
+	var parts []ContentPart
+	var walk func(v any)
+	walk = func(v any) {
+		switch t := v.(type) {
+		case ContentPart:
+			parts = append(parts, t)
+		case AttachmentRef, *AttachmentRef:
+			parts = append(parts, ContentPart{Type: AttachmentRefType, err: errAttachmentNotInlined})
+		case []ContentPart:
+			parts = append(parts, t...)
+		case InputItem:
+			walk(t.Content)
+		case *InputItem:
+			if t != nil {
+				walk(t.Content)
+			}
+		case []InputItem:
+			for _, item := range t {
+				walk(item.Content)
+			}
+		case []any:
+			for _, e := range t {
+				walk(e)
+			}
+		}
+	}
+	walk(input)
+	return parts
+}
+
+func readInlineFile(path string, limit int) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("textualopenai: %w", err)
+	}
+	if info.Size() > int64(limit) {
+		return nil, fmt.Errorf("textualopenai: %s is %d bytes, the limit is %d bytes", path, info.Size(), limit)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("textualopenai: %w", err)
+	}
+	return data, nil
+}
+
+// detectMIMEType returns mimeType when set, else the type of the filename extension,
+// else the sniffed content type. Parameters (charset, ...) are dropped.
+func detectMIMEType(data []byte, mimeType, filename string) string {
+	if mimeType == "" && filename != "" {
+		mimeType = mime.TypeByExtension(strings.ToLower(filepath.Ext(filename)))
+	}
+	if mimeType == "" && len(data) > 0 {
+		mimeType = http.DetectContentType(data)
+	}
+	if mt, _, err := mime.ParseMediaType(mimeType); err == nil {
+		return mt
+	}
+	return mimeType
+}
+
+func checkInline(kind string, data []byte, mimeType string, limit int, supported bool) error {
+	if len(data) == 0 {
+		return fmt.Errorf("textualopenai: empty %s", kind)
+	}
+	if !supported {
+		return fmt.Errorf("textualopenai: unsupported %s MIME type %q", kind, mimeType)
+	}
+	if len(data) > limit {
+		return fmt.Errorf("textualopenai: %s is %d bytes, the limit is %d bytes", kind, len(data), limit)
+	}
+	return nil
+}
+
+func dataURL(mimeType string, data []byte) string {
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/memories"
+)
+
+// Usage sample :
+// history := memories.NewMemory[textualopenai.InputItem](memories.V4UUID(), 0, 0, 0)
+// sync, err := textualopenai.CreateConversationSync(ctx, client.Conversations(), history, nil)
+// if err != nil { ... }
+//
+// // Each turn: the server persists the turn, the local memory mirrors it.
+// req.Conversation = sync.ConversationID
+// req.Input = prompt
+// answer, _, err := client.StreamAndTranscodeResponses(ctx, req)
+// sync.Record(textualopenai.InputItem{Role: "user", Content: prompt}, textualopenai.InputItem{Role: "assistant", Content: answer})
+//
+// // Later, possibly in another process: rebuild the local memory from the server.
+// sync = textualopenai.NewConversationSync(client.Conversations(), conversationID, history)
+// err = sync.Pull(ctx)
+
+// ConversationSync keeps a local memory in sync with a server side conversation,
+// so callers can switch between local and server side persistence.
+//
+// The remote conversation is the source of truth: Pull replaces the local items
+// with the remote messages, Push appends items to both sides.
+type ConversationSync struct {
+	Conversations  Conversations
+	ConversationID string
+	Memory         *memories.Memory[InputItem]
+}
+
+// maxConversationItems is the maximum number of items per create or add items call.
+const maxConversationItems = 20
+
+// NewConversationSync binds memory to an existing conversation.
+func NewConversationSync(conversations Conversations, conversationID string, memory *memories.Memory[InputItem]) *ConversationSync {
+	return &ConversationSync{Conversations: conversations, ConversationID: conversationID, Memory: memory}
+}
+
+// CreateConversationSync creates a conversation seeded with the items of memory and binds them.
+// The API accepts 20 items per call: the conversation is created with the oldest ones and the
+// others are added by batches. When adding a batch fails, the sync is returned along with the
+// error, bound to the partially seeded conversation.
+func CreateConversationSync(ctx context.Context, conversations Conversations, memory *memories.Memory[InputItem], metadata map[string]string) (*ConversationSync, error) {
+	if memory == nil {
+		return nil, errors.New("textualopenai: nil memory")
+	}
+	local := memory.GetSortedItems()
+	first := local[:min(maxConversationItems, len(local))]
+	items := make([]any, len(first))
+	for i, item := range first {
+		items[i] = item
+	}
+	conv, err := conversations.Create(ctx, metadata, items...)
+	if err != nil {
+		return nil, err
+	}
+	s := NewConversationSync(conversations, conv.ID, memory)
+	for start := len(first); start < len(local); start += maxConversationItems {
+		batch := local[start:min(start+maxConversationItems, len(local))]
+		remote := make([]any, len(batch))
+		for i, item := range batch {
+			remote[i] = item
+		}
+		if _, err := conversations.AddItems(ctx, conv.ID, remote...); err != nil {
+			return s, fmt.Errorf("textualopenai: conversation %s seeded with %d of %d items: %w", conv.ID, start, len(local), err)
+		}
+	}
+	return s, nil
+}
+
+// Pull replaces the local items with the messages of the remote conversation.
+// Non message items (tool calls, reasoning, ...) are not mirrored.
+func (s *ConversationSync) Pull(ctx context.Context) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+	remote, err := s.Conversations.AllItems(ctx, s.ConversationID)
+	if err != nil {
+		return err
+	}
+	var keys memories.KeyFactory
+	s.Memory.Rewrite(func(memories.TimedMap[InputItem]) memories.TimedMap[InputItem] {
+		items := make(memories.TimedMap[InputItem], len(remote))
+		for _, ri := range remote {
+			if item, ok := ri.InputItem(); ok {
+				items[keys.NowKey()] = item
+			}
+		}
+		return items
+	})
+	return nil
+}
+
+// Push appends items to the remote conversation (by batches of 20), then to the local memory.
+// Use it for items that are not produced by a response bound to the conversation.
+func (s *ConversationSync) Push(ctx context.Context, items ...InputItem) error {
+	if err := s.check(); err != nil {
+		return err
+	}
+	for start := 0; start < len(items); start += maxConversationItems {
+		batch := items[start:min(start+maxConversationItems, len(items))]
+		remote := make([]any, len(batch))
+		for i, item := range batch {
+			remote[i] = item
+		}
+		if _, err := s.Conversations.AddItems(ctx, s.ConversationID, remote...); err != nil {
+			return err
+		}
+		s.Memory.Add(batch...)
+	}
+	return nil
+}
+
+// Record appends items to the local memory only. Use it for the input and output of
+// a response created with Conversation set: the server already stored them.
+func (s *ConversationSync) Record(items ...InputItem) {
+	if s.Memory != nil {
+		s.Memory.Add(items...)
+	}
+}
+
+func (s *ConversationSync) check() error {
+	if s == nil || s.Memory == nil {
+		return errors.New("textualopenai: nil memory")
+	}
+	if s.ConversationID == "" {
+		return errors.New("textualopenai: missing conversation id")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/memories"
+)
+
+func TestCreateConversationSyncSeedsEveryItem(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	var contents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Items []InputItem `json:"items"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		calls = append(calls, fmt.Sprintf("%s %s %d", r.Method, r.URL.Path, len(body.Items)))
+		for _, item := range body.Items {
+			contents = append(contents, fmt.Sprint(item.Content))
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"conv_1","object":"conversation","data":[]}`))
+	}))
+	defer srv.Close()
+
+	memory := memories.NewMemory[InputItem](memories.V4UUID(), 0, 0, 0)
+	for i := 0; i < 45; i++ {
+		memory.Add(InputItem{Role: "user", Content: fmt.Sprintf("m%02d", i)})
+		time.Sleep(time.Microsecond) // distinct, ordered keys
+	}
+
+	c := testClient(t, srv.URL, "openai:gpt-4.1")
+	s, err := CreateConversationSync(context.Background(), c.Conversations(), memory, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ConversationID != "conv_1" {
+		t.Fatalf("ConversationID = %q", s.ConversationID)
+	}
+	want := []string{"POST /conversations 20", "POST /conversations/conv_1/items 20", "POST /conversations/conv_1/items 5"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Fatalf("calls = %q, want %q", calls, want)
+	}
+	if len(contents) != 45 || contents[0] != "m00" || contents[44] != "m44" {
+		t.Fatalf("items sent out of order or missing: %v", contents)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Usage sample :
+// conversations := client.Conversations()
+// conv, err := conversations.Create(ctx, map[string]string{"topic": "demo"},
+// 	textualopenai.InputItem{Role: "user", Content: "Hello!"})
+// if err != nil { ... }
+//
+// req := textualopenai.NewResponsesRequest(ctx, model)
+// req.Conversation = conv.ID // the server appends the input and the output to the conversation
+// req.Input = "Tell me a joke"
+// ...
+// items, err := conversations.AllItems(ctx, conv.ID)
+
+// ErrConversationsUnsupported is returned when the provider has no Conversations API.
+var ErrConversationsUnsupported = errors.New("textualopenai: the provider does not support the Conversations API")
+
+// Conversation is a server side conversation.
+type Conversation struct {
+	ID        string            `json:"id"`
+	Object    string            `json:"object,omitempty"` // "conversation"
+	CreatedAt int64             `json:"created_at,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// ConversationItem is an item of a conversation (message, function call, reasoning, ...).
+type ConversationItem struct {
+	Type    string                    `json:"type"`
+	ID      string                    `json:"id,omitempty"`
+	Status  string                    `json:"status,omitempty"`
+	Role    string                    `json:"role,omitempty"`
+	Content []ConversationItemContent `json:"content,omitempty"`
+
+	// Raw is the item as received, for the fields not mapped above.
+	Raw json.RawMessage `json:"-"`
+}
+
+// ConversationItemContent is a content part of a message item.
+type ConversationItemContent struct {
+	Type string `json:"type"` // "input_text", "output_text", "input_image", ...
+	Text string `json:"text,omitempty"`
+}
+
+// UnmarshalJSON decodes the item and keeps its raw form.
+func (i *ConversationItem) UnmarshalJSON(data []byte) error {
+	type alias ConversationItem
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*i = ConversationItem(a)
+	i.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// Text returns the concatenated text parts of a message item.
+func (i ConversationItem) Text() string {
+	var b strings.Builder
+	for _, c := range i.Content {
+		b.WriteString(c.Text)
+	}
+	return b.String()
+}
+
+// InputItem converts a message item to an InputItem (ok is false for other item types).
+// Text-only messages get a string content; other parts are dropped.
+func (i ConversationItem) InputItem() (item InputItem, ok bool) {
+	if i.Type != "message" || i.Role == "" {
+		return InputItem{}, false
+	}
+	return InputItem{Role: i.Role, Content: i.Text()}, true
+}
+
+// ConversationItemList is a page of conversation items.
+type ConversationItemList struct {
+	Data    []ConversationItem `json:"data"`
+	FirstID string             `json:"first_id,omitempty"`
+	LastID  string             `json:"last_id,omitempty"`
+	HasMore bool               `json:"has_more"`
+}
+
+// ListItemsOptions paginates ListItems.
+type ListItemsOptions struct {
+	After   string   // item id to list after
+	Limit   int      // 1-100 (0 = provider default)
+	Order   string   // "asc" or "desc" (provider default: "desc")
+	Include []string // e.g. IncludeReasoningEncryptedContent
+}
+
+// Conversations is the Conversations API sub-client.
+type Conversations struct {
+	client Client
+}
+
+// Conversations returns the Conversations API sub-client.
+func (c Client) Conversations() Conversations {
+	return Conversations{client: c}
+}
+
+func (cv Conversations) check() error {
+	if !cv.client.model.ProviderInfo().SupportsConversation {
+		return ErrConversationsUnsupported
+	}
+	return nil
+}
+
+// Create creates a conversation with optional metadata and initial items (up to 20).
+func (cv Conversations) Create(ctx context.Context, metadata map[string]string, items ...any) (Conversation, error) {
+	if err := cv.check(); err != nil {
+		return Conversation{}, err
+	}
+	body := struct {
+		Metadata map[string]string `json:"metadata,omitempty"`
+		Items    []any             `json:"items,omitempty"`
+	}{Metadata: metadata, Items: items}
+	var conv Conversation
+	err := cv.client.doJSON(ctx, http.MethodPost, "conversations", nil, body, &conv)
+	return conv, err
+}
+
+// Get retrieves a conversation.
+func (cv Conversations) Get(ctx context.Context, conversationID string) (Conversation, error) {
+	if err := cv.check(); err != nil {
+		return Conversation{}, err
+	}
+	var conv Conversation
+	err := cv.client.doJSON(ctx, http.MethodGet, "conversations/"+url.PathEscape(conversationID), nil, nil, &conv)
+	return conv, err
+}
+
+// Update replaces the metadata of a conversation.
+func (cv Conversations) Update(ctx context.Context, conversationID string, metadata map[string]string) (Conversation, error) {
+	if err := cv.check(); err != nil {
+		return Conversation{}, err
+	}
+	body := struct {
+		Metadata map[string]string `json:"metadata"`
+	}{Metadata: metadata}
+	var conv Conversation
+	err := cv.client.doJSON(ctx, http.MethodPost, "conversations/"+url.PathEscape(conversationID), nil, body, &conv)
+	return conv, err
+}
+
+// Delete deletes a conversation (its items are not deleted).
+func (cv Conversations) Delete(ctx context.Context, conversationID string) error {
+	if err := cv.check(); err != nil {
+		return err
+	}
+	return cv.client.doJSON(ctx, http.MethodDelete, "conversations/"+url.PathEscape(conversationID), nil, nil, nil)
+}
+
+// ListItems lists a page of items of a conversation.
+func (cv Conversations) ListItems(ctx context.Context, conversationID string, opts ListItemsOptions) (ConversationItemList, error) {
+	if err := cv.check(); err != nil {
+		return ConversationItemList{}, err
+	}
+	query := url.Values{}
+	if opts.After != "" {
+		query.Set("after", opts.After)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Order != "" {
+		query.Set("order", opts.Order)
+	}
+	for _, inc := range opts.Include {
+		query.Add("include", inc)
+	}
+	var list ConversationItemList
+	err := cv.client.doJSON(ctx, http.MethodGet, "conversations/"+url.PathEscape(conversationID)+"/items", query, nil, &list)
+	return list, err
+}
+
+// AllItems lists every item of a conversation, in chronological order.
+func (cv Conversations) AllItems(ctx context.Context, conversationID string) ([]ConversationItem, error) {
+	var items []ConversationItem
+	opts := ListItemsOptions{Limit: 100, Order: "asc"}
+	for {
+		page, err := cv.ListItems(ctx, conversationID, opts)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, page.Data...)
+		if !page.HasMore || page.LastID == "" || page.LastID == opts.After {
+			return items, nil
+		}
+		opts.After = page.LastID
+	}
+}
+
+// AddItems appends items (up to 20) to a conversation and returns the created items.
+func (cv Conversations) AddItems(ctx context.Context, conversationID string, items ...any) ([]ConversationItem, error) {
+	if err := cv.check(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	body := struct {
+		Items []any `json:"items"`
+	}{Items: items}
+	var list ConversationItemList
+	if err := cv.client.doJSON(ctx, http.MethodPost, "conversations/"+url.PathEscape(conversationID)+"/items", nil, body, &list); err != nil {
+		return nil, err
+	}
+	return list.Data, nil
+}
+
+// GetItem retrieves an item of a conversation.
+func (cv Conversations) GetItem(ctx context.Context, conversationID, itemID string) (ConversationItem, error) {
+	if err := cv.check(); err != nil {
+		return ConversationItem{}, err
+	}
+	var item ConversationItem
+	path := fmt.Sprintf("conversations/%s/items/%s", url.PathEscape(conversationID), url.PathEscape(itemID))
+	err := cv.client.doJSON(ctx, http.MethodGet, path, nil, nil, &item)
+	return item, err
+}
+
+// DeleteItem deletes an item of a conversation.
+func (cv Conversations) DeleteItem(ctx context.Context, conversationID, itemID string) error {
+	if err := cv.check(); err != nil {
+		return err
+	}
+	path := fmt.Sprintf("conversations/%s/items/%s", url.PathEscape(conversationID), url.PathEscape(itemID))
+	return cv.client.doJSON(ctx, http.MethodDelete, path, nil, nil, nil)
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Usage sample :
+// _ = req.RegisterCustomTool("run_sql", "Run a read-only SQL query on the analytics database",
+// 	textualopenai.LarkGrammar(`start: "SELECT " /[^;]+/`),
+// 	func(ctx context.Context, input string) (string, error) {
+// 		// input is the raw text produced by the model: SELECT ...
+// 		return runQuery(ctx, input)
+// 	},
+// )
+//
+// _ = req.RegisterCustomTool("set_date", "Set the report date", textualopenai.RegexGrammar(`\d{4}-\d{2}-\d{2}`), setDate)
+//
+// Outputs are collected as "custom_tool_call_output" items with FunctionCallOutputs.
+
+// CustomTool defines a "custom" (freeform) tool for the Responses API:
+// the model produces raw text instead of JSON arguments, optionally constrained by a grammar.
+type CustomTool struct {
+	Type        string            `json:"type"` // always "custom"
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Format      *CustomToolFormat `json:"format,omitempty"`
+}
+
+// Custom tool format types and grammar syntaxes.
+const (
+	CustomToolFormatText    = "text"
+	CustomToolFormatGrammar = "grammar"
+
+	GrammarSyntaxLark  = "lark"
+	GrammarSyntaxRegex = "regex"
+)
+
+// CustomToolFormat constrains the input of a custom tool.
+// A nil format (or TextFormat) allows unconstrained text.
+type CustomToolFormat struct {
+	Type       string `json:"type"`                 // "text" or "grammar"
+	Syntax     string `json:"syntax,omitempty"`     // "lark" or "regex" (grammar only)
+	Definition string `json:"definition,omitempty"` // grammar definition (grammar only)
+}
+
+// TextFormat returns an unconstrained text format.
+func TextFormat() *CustomToolFormat {
+	return &CustomToolFormat{Type: CustomToolFormatText}
+}
+
+// LarkGrammar returns a grammar format using the Lark syntax.
+func LarkGrammar(definition string) *CustomToolFormat {
+	return &CustomToolFormat{Type: CustomToolFormatGrammar, Syntax: GrammarSyntaxLark, Definition: definition}
+}
+
+// RegexGrammar returns a grammar format using a regular expression.
+// Inputs are also checked locally (with Go regexp syntax) before the handler is invoked:
+// RegisterCustomTool fails when the definition does not compile (see WithoutArgumentsValidation).
+func RegexGrammar(definition string) *CustomToolFormat {
+	return &CustomToolFormat{Type: CustomToolFormatGrammar, Syntax: GrammarSyntaxRegex, Definition: definition}
+}
+
+// CustomFunction is the handler of a custom tool: it receives the raw text produced by the model
+// and returns the text sent back in the custom_tool_call_output item.
+type CustomFunction func(ctx context.Context, input string) (string, error)
+
+// RegisterCustomTool registers a custom tool on the request ToolRegistry.
+//
+// Function tool options apply (timeout, output limit, approval, groups). Approvers receive
+// the input as a JSON string in FunctionCall.Arguments.
+func (r *ResponsesRequest) RegisterCustomTool(name, description string, format *CustomToolFormat, fn CustomFunction, opts ...FunctionToolOption) error {
+	if r == nil {
+		return errors.New("textualopenai: nil ResponsesRequest")
+	}
+	if err := r.ToolRegistry().RegisterCustomTool(name, description, format, fn, opts...); err != nil {
+		return err
+	}
+	r.syncTool(name)
+	return nil
+}
+
+// RegisterCustomTool registers a custom tool. The name is prefixed with the registry namespace (if any).
+func (t *ToolRegistry) RegisterCustomTool(name, description string, format *CustomToolFormat, fn CustomFunction, opts ...FunctionToolOption) error {
+	if t == nil || t.store == nil {
+		return errors.New("textualopenai: nil ToolRegistry")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("textualopenai: custom tool name is required")
+	}
+	if fn == nil {
+		return errors.New("textualopenai: custom tool handler is required")
+	}
+
+	tool := CustomTool{
+		Type:        "custom",
+		Name:        t.prefix + name,
+		Description: description,
+		Format:      format,
+	}
+
+	options := applyFunctionToolOptions(opts)
+	var grammar *regexp.Regexp
+	if format != nil && format.Type == CustomToolFormatGrammar && format.Syntax == GrammarSyntaxRegex && !options.SkipValidation {
+		// The provider regex dialect is close to RE2. Definitions using provider-only features
+		// must be registered WithoutArgumentsValidation: the input is then only constrained provider side.
+		var err error
+		grammar, err = regexp.Compile(`^(?:` + format.Definition + `)$`)
+		if err != nil {
+			return fmt.Errorf("textualopenai: custom tool %s: invalid regex grammar (register it WithoutArgumentsValidation to only validate provider side): %w", tool.Name, err)
+		}
+	}
+
+	return t.insert(registeredFunctionTool{
+		Tool:    FunctionTool{Type: "custom", Name: tool.Name, Description: description},
+		Custom:  &tool,
+		Grammar: grammar,
+		Handler: customJSONFunction(fn),
+		Options: options,
+	})
+}
+
+// CustomTools returns the custom tool definitions enabled for this request, sorted by name.
+func (r *ResponsesRequest) CustomTools() []CustomTool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var defs []CustomTool
+	for _, reg := range r.toolRegistry.snapshot() {
+		if reg.Custom != nil && r.toolEnabledLocked(reg) {
+			defs = append(defs, *reg.Custom)
+		}
+	}
+	return defs
+}
+
+// customJSONFunction adapts a CustomFunction to the JSONFunction used by the delegate:
+// the input and the output are carried as JSON strings.
+func customJSONFunction(fn CustomFunction) JSONFunction {
+	return func(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+		var input string
+		if err := json.Unmarshal(args, &input); err != nil {
+			return nil, fmt.Errorf("%w: custom tool input must be a JSON string: %v", ErrInvalidFunctionArguments, err)
+		}
+		out, err := fn(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(out)
+	}
+}
+
+// validateArguments checks the call arguments against the tool JSON Schema (function tools)
+// or against the regex grammar (custom tools).
+func (reg registeredFunctionTool) validateArguments(args json.RawMessage) error {
+	if reg.Custom == nil {
+		return reg.Schema.Validate(args)
+	}
+	if reg.Grammar == nil {
+		return nil
+	}
+	var input string
+	if err := json.Unmarshal(args, &input); err != nil {
+		return fmt.Errorf("input is not a string: %v", err)
+	}
+	if !reg.Grammar.MatchString(input) {
+		return fmt.Errorf("input %q does not match the grammar %q", input, reg.Custom.Format.Definition)
+	}
+	return nil
+}
+
+// captureCustomToolCallInputDoneAndExecute finalizes a custom tool call and schedules its handler.
+func (r *ResponsesRequest) captureCustomToolCallInputDoneAndExecute(ctx context.Context, ev StreamEvent) {
+	itemID := strings.TrimSpace(ev.ItemID)
+	input := ev.Input
+	name := ""
+	callID := ""
+
+	r.mu.Lock()
+	r.ensureFunctionDelegateLocked()
+	if st, ok := r.functionCalls[itemID]; ok && st != nil {
+		callID = st.CallID
+		name = st.Name
+		if input == "" {
+			input = st.Args.String()
+		}
+		st.Done = true
+	}
+	var reg registeredFunctionTool
+	if registered, ok := r.toolRegistry.lookup(name); ok && registered.Custom != nil && r.toolEnabledLocked(registered) {
+		reg = registered
+	}
+	observer := r.functionCallObserver
+	red := r.redactor
+	r.mu.Unlock()
+
+	// The input is a free string: it is carried as a JSON string up to the handler.
+	args, _ := json.Marshal(input)
+	call := FunctionCall{
+		ItemID:      itemID,
+		CallID:      callID,
+		Name:        name,
+		Arguments:   restoreArguments(red, args),
+		OutputIndex: ev.OutputIndex,
+	}
+
+	if reg.Handler == nil {
+		if observer != nil {
+			err := fmt.Errorf("textualopenai: no handler registered for custom tool: %s", name)
+			if name == "" {
+				err = errors.New("textualopenai: custom tool call missing name")
+			}
+			observer(ctx, call, nil, err)
+		}
+		return
+	}
+	r.scheduleFunctionCall(ctx, call, reg, observer)
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// Usage sample :
+// // Stateless tool loop: nothing is stored server side, the reasoning travels encrypted.
+// req := textualopenai.NewResponsesRequest(ctx, model)
+// req.UseEncryptedReasoning() // store=false + include reasoning.encrypted_content
+// req.Input = "Plan my trip to Lyon"
+// for {
+// 	... register tools, stream ...
+// 	if len(req.FunctionCallOutputs()) == 0 {
+// 		break
+// 	}
+// 	next := textualopenai.NewResponsesRequest(ctx, model)
+// 	next.ContinueFrom(req) // replays the turn: reasoning, messages, tool calls and tool outputs
+// 	req = next
+// }
+
+// IncludeReasoningEncryptedContent requests the encrypted reasoning of reasoning items.
+const IncludeReasoningEncryptedContent = "reasoning.encrypted_content"
+
+// ReasoningItemType is the type of reasoning output items.
+const ReasoningItemType = "reasoning"
+
+// ReasoningItem is a reasoning output item. It can be sent back as an input item:
+// EncryptedContent lets the provider restore the chain of thought without server side state.
+type ReasoningItem struct {
+	Type             string                 `json:"type"` // always "reasoning"
+	ID               string                 `json:"id,omitempty"`
+	Summary          []ReasoningSummaryPart `json:"summary"` // required (possibly empty) in input items
+	EncryptedContent string                 `json:"encrypted_content,omitempty"`
+}
+
+// ReasoningSummaryPart is a part of a reasoning summary.
+type ReasoningSummaryPart struct {
+	Type string `json:"type"` // "summary_text"
+	Text string `json:"text"`
+}
+
+// capturedOutputItem is an output item kept for the continuation of a stateless conversation.
+type capturedOutputItem struct {
+	OutputIndex int
+	Type        string
+	Raw         json.RawMessage
+}
+
+// UseEncryptedReasoning configures the request for stateless multi-turn conversations:
+// the response is not stored (store=false) and reasoning items carry their encrypted content,
+// so they are re-injected in the next turn (see ContinueFrom).
+func (r *ResponsesRequest) UseEncryptedReasoning() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	store := false
+	r.Store = &store
+	if !slices.Contains(r.Include, IncludeReasoningEncryptedContent) {
+		r.Include = append(r.Include, IncludeReasoningEncryptedContent)
+	}
+}
+
+// ReasoningItems returns the reasoning items of the response, in output order.
+func (r *ResponsesRequest) ReasoningItems() []ReasoningItem {
+	var items []ReasoningItem
+	for _, c := range r.capturedOutputItems() {
+		if c.Type != ReasoningItemType {
+			continue
+		}
+		var item ReasoningItem
+		if err := json.Unmarshal(c.Raw, &item); err != nil {
+			continue
+		}
+		if item.Summary == nil {
+			item.Summary = []ReasoningSummaryPart{}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// ContinuationItems returns the items to append to the input of the next turn of a stateless
+// conversation: the reasoning, message and tool call items of the response (as received, in
+// output order) followed by the tool outputs. Call WaitFunctionCalls first.
+// Reasoning items without encrypted content are dropped: they refer to server side state
+// (see UseEncryptedReasoning).
+//
+// With server side state, previous_response_id makes this unnecessary.
+func (r *ResponsesRequest) ContinuationItems() []any {
+	var items []any
+	for _, c := range r.capturedOutputItems() {
+		if c.Type == ReasoningItemType {
+			var item ReasoningItem
+			if json.Unmarshal(c.Raw, &item) != nil || item.EncryptedContent == "" {
+				continue
+			}
+		}
+		items = append(items, c.Raw)
+	}
+	for _, out := range r.FunctionCallOutputs() {
+		items = append(items, out)
+	}
+	return items
+}
+
+// ContinueFrom makes r the next turn of prev, once prev is streamed (call prev.WaitFunctionCalls first).
+//
+// When prev is not stored server side (store=false, set by UseEncryptedReasoning and by the
+// Messages, Gemini and Ollama chat requests), the turn is replayed: the input of r is prefixed
+// with the input of prev and its ContinuationItems, re-injecting the encrypted reasoning, and r
+// uses encrypted reasoning as well when prev did. Otherwise r continues prev server side:
+// previous_response_id is set and the input is prefixed with the tool outputs of prev.
+func (r *ResponsesRequest) ContinueFrom(prev *ResponsesRequest) {
+	if r == nil || prev == nil || r == prev {
+		return
+	}
+	prev.mu.Lock()
+	stateless := prev.Store != nil && !*prev.Store
+	encrypted := stateless && slices.Contains(prev.Include, IncludeReasoningEncryptedContent)
+	prev.mu.Unlock()
+
+	var items []any
+	if stateless {
+		if encrypted {
+			r.UseEncryptedReasoning()
+		}
+		items = append(inputList(prev.Input), prev.ContinuationItems()...)
+	} else {
+		r.PreviousResponseID = prev.ResponseID()
+		for _, out := range prev.FunctionCallOutputs() {
+			items = append(items, out)
+		}
+	}
+	if items = append(items, inputList(r.Input)...); len(items) > 0 {
+		r.Input = items
+	}
+}
+
+// inputList returns an Input value as a list of input items.
+func inputList(input any) []any {
+	switch v := input.(type) {
+	case nil:
+		return nil
+	case string:
+		return []any{InputItem{Role: "user", Content: v}}
+	case json.RawMessage:
+		var items []any
+		if json.Unmarshal(v, &items) == nil {
+			return items
+		}
+		return []any{v}
+	}
+	// Typed lists ([]InputItem, []any, ...).
+	if rv := reflect.ValueOf(input); rv.Kind() == reflect.Slice {
+		items := make([]any, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		return items
+	}
+	return []any{input}
+}
+
+func (r *ResponsesRequest) capturedOutputItems() []capturedOutputItem {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := make([]capturedOutputItem, len(r.outputItems))
+	copy(out, r.outputItems)
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].OutputIndex < out[j].OutputIndex
+	})
+	return out
+}
+
+// captureOutputItem keeps the finalized reasoning, message and tool call items (built-in delegate).
+func (r *ResponsesRequest) captureOutputItem(ev StreamEvent) {
+	if ev.Type != OutputItemDone || len(ev.Item) == 0 {
+		return
+	}
+	var item outputItemEnvelope
+	if err := json.Unmarshal(ev.Item, &item); err != nil {
+		return
+	}
+	switch item.Type {
+	case ReasoningItemType, "message", "function_call", "custom_tool_call":
+	default:
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outputItems = append(r.outputItems, capturedOutputItem{
+		OutputIndex: ev.OutputIndex,
+		Type:        item.Type,
+		Raw:         slices.Clone(ev.Item),
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// toolTurnEvents is a turn made of a reasoning item, a message and a call to "add".
+func toolTurnEvents(encryptedContent string) []string {
+	reasoning := `{"type":"reasoning","id":"rs_1","summary":[]}`
+	if encryptedContent != "" {
+		reasoning = fmt.Sprintf(`{"type":"reasoning","id":"rs_1","summary":[],"encrypted_content":%q}`, encryptedContent)
+	}
+	call := `{"type":"function_call","id":"fc_1","call_id":"call_1","name":"add","arguments":"{\"a\":1,\"b\":2}"}`
+	return []string{
+		`{"type":"response.created","sequence_number":0,"response":{"id":"resp_1","status":"in_progress"}}`,
+		`{"type":"response.output_item.done","sequence_number":1,"output_index":0,"item":` + reasoning + `}`,
+		`{"type":"response.output_item.done","sequence_number":2,"output_index":1,"item":{"type":"message","id":"msg_1","role":"assistant","content":[{"type":"output_text","text":"Adding."}]}}`,
+		`{"type":"response.output_item.added","sequence_number":3,"output_index":2,"item":` + call + `}`,
+		`{"type":"response.function_call_arguments.done","sequence_number":4,"output_index":2,"item_id":"fc_1","arguments":"{\"a\":1,\"b\":2}"}`,
+		`{"type":"response.output_item.done","sequence_number":5,"output_index":2,"item":` + call + `}`,
+		`{"type":"response.completed","sequence_number":6,"response":{"id":"resp_1","status":"completed"}}`,
+	}
+}
+
+// streamToolTurn streams toolTurnEvents with prev, which calls "add".
+func streamToolTurn(t *testing.T, prev *ResponsesRequest, encryptedContent string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(sseEvents(toolTurnEvents(encryptedContent)...)))
+	}))
+	defer srv.Close()
+
+	err := prev.RegisterFunctionTool("add", "Add two integers", map[string]any{"type": "object"},
+		func(_ context.Context, args json.RawMessage) (json.RawMessage, error) {
+			var in struct{ A, B int }
+			if err := json.Unmarshal(args, &in); err != nil {
+				return nil, err
+			}
+			return json.Marshal(in.A + in.B)
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := testClient(t, srv.URL, "openai:o4-mini")
+	if _, _, err := c.StreamAndTranscodeResponses(context.Background(), prev); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// describeInput summarizes input items as "role:content" or their type.
+func describeInput(t *testing.T, input any) string {
+	t.Helper()
+	b, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []map[string]any
+	if err := json.Unmarshal(b, &items); err != nil {
+		t.Fatalf("input %s: %v", b, err)
+	}
+	var parts []string
+	for _, item := range items {
+		switch {
+		case item["type"] == "function_call_output":
+			parts = append(parts, fmt.Sprintf("output:%v", item["output"]))
+		case item["encrypted_content"] != nil:
+			parts = append(parts, fmt.Sprintf("reasoning:%v", item["encrypted_content"]))
+		case item["type"] != nil:
+			parts = append(parts, fmt.Sprint(item["type"]))
+		default:
+			parts = append(parts, fmt.Sprintf("%v:%v", item["role"], item["content"]))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestContinueFromStateful(t *testing.T) {
+	model := testModel(t, "openai:o4-mini")
+	prev := NewResponsesRequest(context.Background(), model)
+	prev.Input = "hi"
+	streamToolTurn(t, prev, "")
+
+	next := NewResponsesRequest(context.Background(), model)
+	next.Input = "next"
+	next.ContinueFrom(prev)
+	if next.PreviousResponseID != "resp_1" {
+		t.Fatalf("previous_response_id = %q", next.PreviousResponseID)
+	}
+	if got, want := describeInput(t, next.Input), "output:3 user:next"; got != want {
+		t.Fatalf("input = %q, want %q", got, want)
+	}
+	if next.Store != nil {
+		t.Fatal("store changed")
+	}
+}
+
+func TestContinueFromStateless(t *testing.T) {
+	model := testModel(t, "openai:o4-mini")
+	tests := []struct {
+		name             string
+		encrypted        bool
+		encryptedContent string
+		want             string
+	}{
+		{"encrypted reasoning", true, "enc", "user:hi reasoning:enc message function_call output:3 user:next"},
+		// Without encrypted content the reasoning refers to server side state that does not exist.
+		{"plain reasoning", false, "", "user:hi message function_call output:3 user:next"},
+	}
+	for _, tt := range tests {
+		prev := NewResponsesRequest(context.Background(), model)
+		prev.Input = "hi"
+		if tt.encrypted {
+			prev.UseEncryptedReasoning()
+		} else {
+			store := false
+			prev.Store = &store
+		}
+		streamToolTurn(t, prev, tt.encryptedContent)
+
+		next := NewResponsesRequest(context.Background(), model)
+		next.Input = "next"
+		next.ContinueFrom(prev)
+		if next.PreviousResponseID != "" {
+			t.Errorf("%s: previous_response_id = %q", tt.name, next.PreviousResponseID)
+		}
+		if got := describeInput(t, next.Input); got != tt.want {
+			t.Errorf("%s: input = %q, want %q", tt.name, got, tt.want)
+		}
+		// Encrypted reasoning carries over to the next turn.
+		if encrypted := next.Store != nil && !*next.Store && len(next.Include) > 0; encrypted != tt.encrypted {
+			t.Errorf("%s: include = %v, store = %v", tt.name, next.Include, next.Store)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 // StringCarrierFrom converts a JsonGenericCarrier containing a StreamEvent into a StringCarrier.
 // It maps the Index and Error fields directly and sets the Value field.
+// Only the answer text is returned: reasoning events carry no value.
 func StringCarrierFrom(c textual.JsonGenericCarrier[StreamEvent]) textual.StringCarrier {
 	s := textual.StringCarrier{
 		Index: c.Index,
@@ -38,13 +39,18 @@ func StringCarrierFrom(c textual.JsonGenericCarrier[StreamEvent]) textual.String
 	//  - Message: Error or informational message
 
 	switch ev.Type {
-	case OutputTextDelta, ReasoningSummaryTextDelta:
+	case OutputTextDelta:
 		s.Value = ev.Delta
 
 	case TextDone:
 		// Do not emit the full text again: streaming clients already received the deltas.
 		s.Value = ""
 
+	case ReasoningSummaryTextDelta, ReasoningSummaryTextDone, ReasoningTextDelta, ReasoningTextDone,
+		ReasoningSummaryPartAdded, ReasoningSummaryPartDone:
+		// Reasoning is not part of the answer (see ChunkTranscoder).
+		s.Value = ""
+
 	case RefusalDelta:
 		s.Value = ev.Delta
 		s = s.WithError(fmt.Errorf("\neventType: %s refusal: %s", ev.Type, ev.Delta))
@@ -0,0 +1,154 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrFunctionCallDenied is reported to the FunctionCallObserver (wrapped) when a call
+// requiring approval is denied (explicitly, by timeout, or because no approver is set).
+var ErrFunctionCallDenied = errors.New("textualopenai: function call denied")
+
+// ApprovalVerdict is the outcome of an approval request.
+type ApprovalVerdict string
+
+const (
+	// ApprovalApproved executes the call with the model arguments.
+	ApprovalApproved ApprovalVerdict = "approved"
+	// ApprovalDenied does not execute the call: the model receives the reason.
+	ApprovalDenied ApprovalVerdict = "denied"
+	// ApprovalEdited executes the call with the arguments provided by the approver.
+	ApprovalEdited ApprovalVerdict = "edited"
+)
+
+// ApprovalDecision is returned by a FunctionCallApprover.
+type ApprovalDecision struct {
+	Verdict ApprovalVerdict `json:"verdict"`
+
+	// Reason explains a denial to the model (optional for approvals).
+	Reason string `json:"reason,omitempty"`
+
+	// Arguments replaces the model arguments when Verdict is ApprovalEdited.
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// Approve returns an approval decision.
+func Approve() ApprovalDecision {
+	return ApprovalDecision{Verdict: ApprovalApproved}
+}
+
+// Deny returns a denial decision with a reason sent back to the model.
+func Deny(reason string) ApprovalDecision {
+	return ApprovalDecision{Verdict: ApprovalDenied, Reason: reason}
+}
+
+// ApproveWithArguments returns an approval decision replacing the call arguments.
+func ApproveWithArguments(args json.RawMessage) ApprovalDecision {
+	return ApprovalDecision{Verdict: ApprovalEdited, Arguments: args}
+}
+
+// FunctionCallApprover decides whether a function call requiring approval may be executed.
+//
+// It is called on the function call worker pool, possibly concurrently for parallel calls.
+// The context is cancelled when the approval timeout expires.
+type FunctionCallApprover func(ctx context.Context, call FunctionCall) (ApprovalDecision, error)
+
+// AsyncFunctionCallApprover is the asynchronous form of FunctionCallApprover: the decision is
+// delivered on the returned channel (e.g. by a UI or a chat-ops bot).
+type AsyncFunctionCallApprover func(ctx context.Context, call FunctionCall) <-chan ApprovalDecision
+
+// ApproverFromAsync adapts an AsyncFunctionCallApprover to a FunctionCallApprover.
+func ApproverFromAsync(f AsyncFunctionCallApprover) FunctionCallApprover {
+	return func(ctx context.Context, call FunctionCall) (ApprovalDecision, error) {
+		select {
+		case d, ok := <-f(ctx, call):
+			if !ok {
+				return ApprovalDecision{}, errors.New("textualopenai: approval channel closed without decision")
+			}
+			return d, nil
+		case <-ctx.Done():
+			return ApprovalDecision{}, ctx.Err()
+		}
+	}
+}
+
+// WithRequiresApproval marks a tool as sensitive: each call must be approved by the
+// FunctionCallApprover before the handler is invoked.
+func WithRequiresApproval() FunctionToolOption {
+	return func(o *FunctionToolOptions) {
+		o.RequiresApproval = true
+	}
+}
+
+// SetFunctionCallApprover sets the approval callback used for tools registered with
+// WithRequiresApproval. Without an approver, such calls are denied.
+func (r *ResponsesRequest) SetFunctionCallApprover(f FunctionCallApprover) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.functionCallApprover = f
+}
+
+// SetFunctionCallApprovalTimeout bounds the time an approval may take.
+// When it expires the call is denied. d <= 0 waits indefinitely (until the stream context is done).
+func (r *ResponsesRequest) SetFunctionCallApprovalTimeout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.functionCallApprovalTimeout = d
+}
+
+// approveCall asks approver for a decision within timeout (if > 0).
+// It returns the arguments to execute the call with, or an ErrFunctionCallDenied error.
+func approveCall(ctx context.Context, call FunctionCall, approver FunctionCallApprover, timeout time.Duration) (json.RawMessage, error) {
+	if approver == nil {
+		return nil, fmt.Errorf("%w: %s requires approval and no approver is configured", ErrFunctionCallDenied, call.Name)
+	}
+
+	approvalCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		approvalCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	decision, err := approver(approvalCtx, call)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: approval timed out after %s", ErrFunctionCallDenied, timeout)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrFunctionCallDenied, err)
+	}
+
+	switch decision.Verdict {
+	case ApprovalApproved:
+		return call.Arguments, nil
+	case ApprovalEdited:
+		if len(decision.Arguments) == 0 || !json.Valid(decision.Arguments) {
+			return nil, fmt.Errorf("%w: edited arguments are not valid JSON", ErrFunctionCallDenied)
+		}
+		return decision.Arguments, nil
+	default:
+		reason := strings.TrimSpace(decision.Reason)
+		if reason == "" {
+			reason = "denied by the user"
+		}
+		return nil, fmt.Errorf("%w: %s", ErrFunctionCallDenied, reason)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+)
+
+// DefaultFunctionCallConcurrency is the default size of the function call worker pool.
+const DefaultFunctionCallConcurrency = 4
+
+// SetFunctionCallConcurrency sets the maximum number of function handlers executed concurrently.
+//
+// Handlers are dispatched as soon as their arguments are complete, while the stream keeps
+// being consumed. When ParallelToolCalls is explicitly set to false, handlers are executed
+// one at a time regardless of this setting. n <= 0 restores DefaultFunctionCallConcurrency.
+func (r *ResponsesRequest) SetFunctionCallConcurrency(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.functionCallConcurrency = n
+	// The pool is re-created with the new size on the next dispatch.
+	r.functionCallSem = nil
+}
+
+// FunctionCallConcurrency returns the effective size of the function call worker pool.
+func (r *ResponsesRequest) FunctionCallConcurrency() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.functionCallConcurrencyLocked()
+}
+
+func (r *ResponsesRequest) functionCallConcurrencyLocked() int {
+	if r.ParallelToolCalls != nil && !*r.ParallelToolCalls {
+		return 1
+	}
+	if r.functionCallConcurrency <= 0 {
+		return DefaultFunctionCallConcurrency
+	}
+	return r.functionCallConcurrency
+}
+
+// scheduleFunctionCall executes a finalized function call on the bounded worker pool.
+func (r *ResponsesRequest) scheduleFunctionCall(ctx context.Context, call FunctionCall, reg registeredFunctionTool, observer FunctionCallObserver) {
+	r.mu.Lock()
+	if r.functionCallSem == nil {
+		r.functionCallSem = make(chan struct{}, r.functionCallConcurrencyLocked())
+	}
+	sem := r.functionCallSem
+	r.functionCallWG.Add(1)
+	r.mu.Unlock()
+
+	go func() {
+		defer r.functionCallWG.Done()
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		case <-ctx.Done():
+			if observer != nil {
+				observer(ctx, call, nil, ctx.Err())
+			}
+			return
+		}
+		r.executeFunctionCall(ctx, call, reg, observer)
+	}()
+}
+
+// WaitFunctionCalls blocks until every dispatched function handler has completed,
+// or ctx is done. Call it before building the follow-up request from FunctionCallOutputs.
+//
+// Client.StreamAndTranscodeResponses calls it automatically once the stream is fully consumed.
+func (r *ResponsesRequest) WaitFunctionCalls(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.functionCallWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// functionCallEvents streams n calls to "slow"; call i sleeps (n-i)*step, so handlers finish
+// in the reverse order of the calls.
+func functionCallEvents(n int, step time.Duration) string {
+	var events []string
+	for i := range n {
+		args, _ := json.Marshal(fmt.Sprintf(`{"i":%d,"ms":%d}`, i, (time.Duration(n-i) * step).Milliseconds()))
+		events = append(events,
+			fmt.Sprintf(`{"type":"response.output_item.added","output_index":%d,"item":{"type":"function_call","id":"fc_%d","call_id":"call_%d","name":"slow","arguments":""}}`, i, i, i),
+			fmt.Sprintf(`{"type":"response.function_call_arguments.done","output_index":%d,"item_id":"fc_%d","arguments":%s}`, i, i, args),
+		)
+	}
+	return sseEvents(events...)
+}
+
+// slowTool registers "slow" on req and returns the maximum number of concurrent handlers
+// and the order in which they completed.
+func slowTool(t *testing.T, req *ResponsesRequest) (maxActive func() int32, completed func() []int) {
+	t.Helper()
+	var active, highest atomic.Int32
+	var mu sync.Mutex
+	var order []int
+	err := RegisterFunctionToolTyped(req, "slow", "Sleeps", nil, func(ctx context.Context, in struct{ I, MS int }) (map[string]int, error) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			h := highest.Load()
+			if n <= h || highest.CompareAndSwap(h, n) {
+				break
+			}
+		}
+		select {
+		case <-time.After(time.Duration(in.MS) * time.Millisecond):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		mu.Lock()
+		order = append(order, in.I)
+		mu.Unlock()
+		return map[string]int{"i": in.I}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return highest.Load, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), order...)
+	}
+}
+
+// runFunctionCalls streams the calls and waits for their handlers.
+func runFunctionCalls(t *testing.T, req *ResponsesRequest, n int, step time.Duration) {
+	t.Helper()
+	for range req.TranscodeStream(context.Background(), strings.NewReader(functionCallEvents(n, step))) {
+	}
+	if err := req.WaitFunctionCalls(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// outputIndexes returns the "i" of each output, in FunctionCallOutputs order.
+func outputIndexes(t *testing.T, req *ResponsesRequest) []int {
+	t.Helper()
+	var got []int
+	for _, out := range req.FunctionCallOutputs() {
+		var v struct{ I int }
+		if err := json.Unmarshal([]byte(out.Output), &v); err != nil {
+			t.Fatalf("output %q: %v", out.Output, err)
+		}
+		if out.CallID != fmt.Sprintf("call_%d", v.I) {
+			t.Fatalf("output %q for %s", out.Output, out.CallID)
+		}
+		got = append(got, v.I)
+	}
+	return got
+}
+
+func TestFunctionCallWorkerPool(t *testing.T) {
+	req := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+	req.SetFunctionCallConcurrency(2)
+	maxActive, completed := slowTool(t, req)
+	runFunctionCalls(t, req, 5, 15*time.Millisecond)
+
+	if n := maxActive(); n != 2 {
+		t.Fatalf("%d concurrent handlers, want 2", n)
+	}
+	// Handlers complete out of order, outputs follow the calls.
+	if got := completed(); fmt.Sprint(got) == "[0 1 2 3 4]" {
+		t.Fatalf("handlers completed in call order %v: the test does not exercise reordering", got)
+	}
+	if got := outputIndexes(t, req); fmt.Sprint(got) != "[0 1 2 3 4]" {
+		t.Fatalf("outputs = %v, want call order", got)
+	}
+}
+
+func TestFunctionCallsSerializedWithoutParallelToolCalls(t *testing.T) {
+	req := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+	req.ParallelToolCalls = BoolPtr(false)
+	req.SetFunctionCallConcurrency(4)
+	if n := req.FunctionCallConcurrency(); n != 1 {
+		t.Fatalf("FunctionCallConcurrency = %d, want 1", n)
+	}
+	maxActive, _ := slowTool(t, req)
+	runFunctionCalls(t, req, 3, 5*time.Millisecond)
+
+	if n := maxActive(); n != 1 {
+		t.Fatalf("%d concurrent handlers, want 1", n)
+	}
+	if got := outputIndexes(t, req); fmt.Sprint(got) != "[0 1 2]" {
+		t.Fatalf("outputs = %v", got)
+	}
+}
+
+func TestWaitFunctionCalls(t *testing.T) {
+	req := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+	release := make(chan struct{})
+	err := req.RegisterFunctionTool("slow", "Blocks until released", nil, func(context.Context, json.RawMessage) (json.RawMessage, error) {
+		<-release
+		return json.RawMessage(`{"i":0}`), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range req.TranscodeStream(context.Background(), strings.NewReader(functionCallEvents(1, 0))) {
+	}
+
+	// The stream is consumed while the handler runs.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := req.WaitFunctionCalls(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitFunctionCalls = %v, want DeadlineExceeded", err)
+	}
+	if n := len(req.FunctionCallOutputs()); n != 0 {
+		t.Fatalf("%d outputs before completion", n)
+	}
+
+	close(release)
+	if err := req.WaitFunctionCalls(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := outputIndexes(t, req); fmt.Sprint(got) != "[0]" {
+		t.Fatalf("outputs = %v", got)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/instrumentation"
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/redaction"
+)
+
+// Errors reported to the FunctionCallObserver (wrapped) when a safeguard is triggered.
+// The model receives the error message in the function_call_output item.
+var (
+	ErrInvalidFunctionArguments = errors.New("textualopenai: invalid function arguments")
+	ErrFunctionTimeout          = errors.New("textualopenai: function call timed out")
+	ErrFunctionPanic            = errors.New("textualopenai: function handler panicked")
+	ErrFunctionOutputTooLarge   = errors.New("textualopenai: function output too large")
+)
+
+// FunctionToolOptions holds per-tool execution safeguards.
+// Zero values fall back to the request-level settings.
+type FunctionToolOptions struct {
+	// Timeout bounds the handler execution (derived context). It overrides SetFunctionCallTimeout.
+	Timeout time.Duration
+
+	// MaxOutputBytes bounds the size of the handler output. It overrides SetFunctionOutputLimit.
+	MaxOutputBytes int
+
+	// SkipValidation disables JSON Schema validation of the arguments for this tool.
+	SkipValidation bool
+
+	// RequiresApproval requires a FunctionCallApprover decision before each call.
+	RequiresApproval bool
+
+	// Groups lists the tool groups (see WithToolGroups).
+	Groups []string
+}
+
+// FunctionToolOption configures FunctionToolOptions at registration time.
+type FunctionToolOption func(*FunctionToolOptions)
+
+// WithToolTimeout bounds the execution time of a tool handler.
+func WithToolTimeout(d time.Duration) FunctionToolOption {
+	return func(o *FunctionToolOptions) {
+		o.Timeout = d
+	}
+}
+
+// WithMaxOutputBytes bounds the size of a tool handler output.
+func WithMaxOutputBytes(n int) FunctionToolOption {
+	return func(o *FunctionToolOptions) {
+		o.MaxOutputBytes = n
+	}
+}
+
+// WithoutArgumentsValidation disables JSON Schema validation of the tool arguments.
+func WithoutArgumentsValidation() FunctionToolOption {
+	return func(o *FunctionToolOptions) {
+		o.SkipValidation = true
+	}
+}
+
+// SetFunctionCallTimeout sets the default timeout applied to every function handler.
+// d <= 0 disables the global timeout (per-tool timeouts still apply).
+func (r *ResponsesRequest) SetFunctionCallTimeout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.functionCallTimeout = d
+}
+
+// SetFunctionOutputLimit sets the default maximum size (in bytes) of a function handler output.
+// Larger outputs are replaced by an error the model can react to. n <= 0 disables the limit.
+func (r *ResponsesRequest) SetFunctionOutputLimit(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.functionOutputLimit = n
+}
+
+// SetFunctionArgumentsValidation enables (default) or disables the JSON Schema validation of
+// function call arguments against the registered tool parameters.
+func (r *ResponsesRequest) SetFunctionArgumentsValidation(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.functionValidationDisabled = !enabled
+}
+
+// ToolCallOptions holds the settings a tool handler runs with outside of a response
+// (see ToolRegistry.Call). Within a response they come from the request setters.
+type ToolCallOptions struct {
+	// Approver decides on tools registered WithRequiresApproval (denied when nil).
+	Approver        FunctionCallApprover
+	ApprovalTimeout time.Duration
+
+	// Timeout and MaxOutputBytes are the defaults of the tools without per-tool values.
+	Timeout        time.Duration
+	MaxOutputBytes int
+
+	// SkipValidation disables the JSON Schema validation of the arguments.
+	SkipValidation bool
+
+	// Redactor restores the placeholders of the arguments and redacts the output.
+	Redactor *redaction.Redactor
+
+	// Instrumentation traces the call as an execute_tool span.
+	Instrumentation instrumentation.Instrumentation
+}
+
+// toolCallOptions returns the request-level safeguards of the function calls.
+func (r *ResponsesRequest) toolCallOptions() ToolCallOptions {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return ToolCallOptions{
+		Approver:        r.functionCallApprover,
+		ApprovalTimeout: r.functionCallApprovalTimeout,
+		Timeout:         r.functionCallTimeout,
+		MaxOutputBytes:  r.functionOutputLimit,
+		SkipValidation:  r.functionValidationDisabled,
+	}
+}
+
+// invokeFunctionHandler runs a handler with the request safeguards (see invokeTool).
+func (r *ResponsesRequest) invokeFunctionHandler(ctx context.Context, call *FunctionCall, reg registeredFunctionTool) (json.RawMessage, error) {
+	return invokeTool(ctx, call, reg, r.toolCallOptions())
+}
+
+// invokeTool runs a handler with the configured safeguards:
+//  1. arguments are validated against the tool JSON Schema,
+//  2. sensitive tools are approved (or denied, or edited) by the approver,
+//  3. the handler runs with a derived context bounded by the per-tool or default timeout,
+//  4. panics are recovered and converted into errors,
+//  5. the output size is checked against the per-tool or default limit.
+//
+// The call is updated in place when the approver edits the arguments.
+// opts.Redactor and opts.Instrumentation are left to the caller.
+//
+// Handlers must honor their context. A handler ignoring it cannot block the worker pool:
+// on timeout the call returns immediately, but the handler goroutine is abandoned and
+// leaks until the handler returns.
+func invokeTool(ctx context.Context, call *FunctionCall, reg registeredFunctionTool, opts ToolCallOptions) (json.RawMessage, error) {
+	timeout := opts.Timeout
+	limit := opts.MaxOutputBytes
+	validate := !opts.SkipValidation && !reg.Options.SkipValidation
+
+	if reg.Options.Timeout > 0 {
+		timeout = reg.Options.Timeout
+	}
+	if reg.Options.MaxOutputBytes > 0 {
+		limit = reg.Options.MaxOutputBytes
+	}
+
+	if validate {
+		if err := reg.validateArguments(call.Arguments); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidFunctionArguments, err.Error())
+		}
+	}
+
+	if reg.Options.RequiresApproval {
+		args, err := approveCall(ctx, *call, opts.Approver, opts.ApprovalTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if string(args) != string(call.Arguments) {
+			call.Arguments = args
+			// Edited arguments must satisfy the schema as well.
+			if validate {
+				if err := reg.validateArguments(call.Arguments); err != nil {
+					return nil, fmt.Errorf("%w: %s", ErrInvalidFunctionArguments, err.Error())
+				}
+			}
+		}
+	}
+
+	callCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		out json.RawMessage
+		err error
+	}
+	args := call.Arguments
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- result{err: fmt.Errorf("%w: %v\n%s", ErrFunctionPanic, p, debug.Stack())}
+			}
+		}()
+		out, err := reg.Handler(callCtx, args)
+		done <- result{out: out, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-callCtx.Done():
+		if errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil, fmt.Errorf("%w: %s exceeded %s", ErrFunctionTimeout, call.Name, timeout)
+		}
+		return nil, callCtx.Err()
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	if limit > 0 && len(res.out) > limit {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrFunctionOutputTooLarge, len(res.out), limit)
+	}
+	if len(res.out) > 0 && !json.Valid(res.out) {
+		return nil, fmt.Errorf("textualopenai: function %s returned invalid JSON", call.Name)
+	}
+	return res.out, nil
+}
+
+// functionErrorOutput encodes err as the JSON output sent back to the model.
+//
+// Argument validation errors carry a hint so the model can fix its call and retry.
+// Denials are structured ({"status":"denied","reason":...}) so the model does not retry blindly.
+// Panic stack traces are kept for the observer only and never sent to the model.
+func functionErrorOutput(err error) string {
+	payload := map[string]any{"error": err.Error()}
+	switch {
+	case errors.Is(err, ErrFunctionCallDenied):
+		reason := strings.TrimPrefix(err.Error(), ErrFunctionCallDenied.Error()+": ")
+		payload = map[string]any{"status": "denied", "reason": reason}
+	case errors.Is(err, ErrInvalidFunctionArguments):
+		payload["hint"] = "The arguments do not match the tool parameters JSON schema (or grammar). Fix them and call the tool again."
+	case errors.Is(err, ErrFunctionPanic):
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+		payload["error"] = msg
+	}
+	b, _ := json.Marshal(payload)
+	return string(b)
+}
@@ -0,0 +1,677 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// Usage sample :
+// model, _ := models.ModelFromString("gemini:gemini-2.5-pro")
+// client, _ := textualopenai.ClientFrom("", model, ctx) // GEMINI_API_KEY
+// req := textualopenai.NewGenerateContentRequest(ctx, model)
+// req.Instructions = "Answer in one sentence."
+// req.Input = "What's the weather in Paris?"
+// req.Reasoning = &textualopenai.ReasoningConfig{Effort: textualopenai.ReasoningEffortLow}
+// _ = req.RegisterFunctionTool("get_weather", "...", schema, getWeather) // same tool delegate
+// _ = req.AddListeners(textualopenai.StringCarrierFrom, textualopenai.OutputTextDelta)
+// text, _, err := client.StreamAndTranscodeResponses(ctx, req)
+//
+// // The Gemini API is stateless: continue a tool loop by replaying the turn (thought signatures included).
+// next := textualopenai.NewGenerateContentRequest(ctx, model)
+// next.Input = append([]any{textualopenai.InputItem{Role: "user", Content: "What's the weather in Paris?"}}, req.ContinuationItems()...)
+
+// geminiThinkingBudgets maps reasoning efforts to thinking budgets (tokens).
+var geminiThinkingBudgets = map[ReasoningEffort]int{
+	ReasoningEffortMinimal: 512,
+	ReasoningEffortLow:     1024,
+	ReasoningEffortMedium:  8192,
+	ReasoningEffortHigh:    24576,
+}
+
+// GenerateContentRequest is a streaming request to the Google Gemini API
+// (models/{model}:streamGenerateContent).
+//
+// It embeds a ResponsesRequest: Input, Instructions, sampling, Reasoning, listeners, observers
+// and the function tool delegate are shared. Gemini chunks are translated into the StreamEvent
+// types (output_text, function_call_arguments, reasoning_text, output_item, ...), so listeners
+// written for the Responses API work unchanged.
+//
+// Gemini streams complete function calls (args objects, not deltas): each call is emitted as a
+// single function_call_arguments delta immediately followed by its done event.
+type GenerateContentRequest struct {
+	*ResponsesRequest
+
+	// StopSequences stops the generation on custom sequences.
+	StopSequences []string
+
+	// TopK only samples from the K most likely tokens.
+	TopK *int
+
+	// SafetySettings overrides the default safety thresholds.
+	SafetySettings []GeminiSafetySetting
+
+	stream geminiStream
+}
+
+// GeminiSafetySetting is a safety threshold for a harm category
+// (e.g. HARM_CATEGORY_HARASSMENT / BLOCK_ONLY_HIGH).
+type GeminiSafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+// NewGenerateContentRequest returns a streaming Gemini API request.
+func NewGenerateContentRequest(ctx context.Context, model models.Model) *GenerateContentRequest {
+	r := NewResponsesRequest(ctx, model)
+	// Nothing is stored server side: follow-up turns replay the conversation (see ContinueFrom).
+	r.Store = BoolPtr(false)
+	return &GenerateContentRequest{ResponsesRequest: r}
+}
+
+func (g *GenerateContentRequest) URL(baseURL string) (string, error) {
+	if strings.TrimSpace(baseURL) == "" {
+		return "", errors.New("textualopenai: missing Gemini base URL")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("textualopenai: invalid base URL: %w", err)
+	}
+	model := strings.TrimPrefix(string(g.Model), "models/")
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/models/" + model + ":streamGenerateContent"
+	u.RawQuery = url.Values{"alt": []string{"sse"}}.Encode()
+	return u.String(), nil
+}
+
+// SetHeaders sets the Gemini API key header (see HeaderSetter).
+func (g *GenerateContentRequest) SetHeaders(h http.Header, apiKey string) {
+	if strings.TrimSpace(apiKey) != "" {
+		h.Set("x-goog-api-key", apiKey)
+	}
+}
+
+func (g *GenerateContentRequest) Validate() error {
+	if !g.Stream {
+		return errors.New("textualopenai: streaming must be enabled")
+	}
+	if g.Input == nil {
+		return errors.New("textualopenai: input is required")
+	}
+	if strings.TrimSpace(g.PreviousResponseID) != "" || g.conversationProvided() || g.Background || g.Prompt != nil {
+		return errors.New("textualopenai: the Gemini API is stateless (previous_response_id, conversation, background and prompt are not supported)")
+	}
+	if len(g.CustomTools()) > 0 {
+		return errors.New("textualopenai: custom tools are not supported by the Gemini API")
+	}
+	if err := g.validateHostedTools(); err != nil {
+		return err
+	}
+	if err := g.validateContentParts(); err != nil {
+		return err
+	}
+	_, _, err := geminiContentsFromInput(g.Input)
+	return err
+}
+
+// MarshalJSON serializes the generateContent body.
+//
+// Instructions and system/developer input items become the system instruction; the input is
+// redacted when a Redactor is attached. Reasoning (or Thinking) sets a thinking budget and
+// requests the thought summaries.
+func (g *GenerateContentRequest) MarshalJSON() ([]byte, error) {
+	system, contents, err := geminiContentsFromInput(g.Input)
+	if err != nil {
+		return nil, err
+	}
+	if s := strings.TrimSpace(g.Instructions); s != "" {
+		system = append([]string{s}, system...)
+	}
+
+	body := geminiBody{
+		Contents:       contents,
+		Tools:          g.geminiTools(),
+		ToolConfig:     g.geminiToolConfig(),
+		SafetySettings: g.SafetySettings,
+		GenerationConfig: &geminiGenerationConfig{
+			Temperature:     g.Temperature,
+			TopP:            g.TopP,
+			TopK:            g.TopK,
+			MaxOutputTokens: g.MaxOutputTokens,
+			StopSequences:   g.StopSequences,
+		},
+	}
+	if len(system) > 0 {
+		body.SystemInstruction = &GeminiContent{Parts: []GeminiPart{{Text: strings.Join(system, "\n\n")}}}
+	}
+	if budget := g.thinkingBudget(); budget > 0 {
+		body.GenerationConfig.ThinkingConfig = &geminiThinkingConfig{ThinkingBudget: budget, IncludeThoughts: true}
+	}
+
+	if red := g.Redactor(); red != nil {
+		redacted, err := red.RedactJSON(contents, geminiPreservedKeys...)
+		if err != nil {
+			return nil, fmt.Errorf("textualopenai: redact input: %w", err)
+		}
+		body.Contents = redacted
+	}
+	return json.Marshal(body)
+}
+
+// thinkingBudget returns the thinking budget (0 leaves the model default).
+func (g *GenerateContentRequest) thinkingBudget() int {
+	cfg := g.Reasoning
+	if cfg == nil && g.Thinking {
+		cfg = &ReasoningConfig{Effort: ReasoningEffortMedium}
+	}
+	if cfg == nil {
+		return 0
+	}
+	if budget, ok := geminiThinkingBudgets[cfg.Effort]; ok {
+		return budget
+	}
+	return geminiThinkingBudgets[ReasoningEffortMedium]
+}
+
+// geminiTools converts the function tools to Gemini function declarations.
+// Schemas are sent as JSON Schema (parametersJsonSchema), strict is not supported.
+func (g *GenerateContentRequest) geminiTools() []geminiTool {
+	var decls []geminiFunctionDeclaration
+	for _, ft := range g.effectiveFunctionTools() {
+		decls = append(decls, geminiFunctionDeclaration{Name: ft.Name, Description: ft.Description, Parameters: ft.Parameters})
+	}
+	if len(decls) == 0 {
+		return nil
+	}
+	return []geminiTool{{FunctionDeclarations: decls}}
+}
+
+// geminiToolConfig maps ToolChoice to the function calling mode.
+func (g *GenerateContentRequest) geminiToolConfig() *geminiToolConfig {
+	var cfg geminiFunctionCallingConfig
+	switch v := g.ToolChoice.(type) {
+	case string:
+		switch v {
+		case "auto":
+			cfg.Mode = "AUTO"
+		case "required":
+			cfg.Mode = "ANY"
+		case "none":
+			cfg.Mode = "NONE"
+		}
+	case map[string]any:
+		if name, _ := v["name"].(string); name != "" {
+			cfg.Mode, cfg.AllowedFunctionNames = "ANY", []string{name}
+		}
+	}
+	if cfg.Mode == "" {
+		return nil
+	}
+	return &geminiToolConfig{FunctionCallingConfig: cfg}
+}
+
+// TranscodeStream applies Transcoder to body (see StreamingRequest).
+func (g *GenerateContentRequest) TranscodeStream(ctx context.Context, body io.Reader) <-chan textual.StringCarrier {
+	g.stream.reset()
+	return transcodeTranslated(ctx, g.ResponsesRequest, body, g.Transcoder())
+}
+
+// Transcoder translates the Gemini chunks into StreamEvents (see translatingTranscoder).
+func (g *GenerateContentRequest) Transcoder() textual.TranscoderFunc[textual.JsonGenericCarrier[GeminiChunk], textual.StringCarrier] {
+	return translatingTranscoder(g.ResponsesRequest, func(chunk GeminiChunk) []StreamEvent {
+		return g.stream.translate(string(g.Model), chunk)
+	})
+}
+
+// ─────────────────────────────────────────────────────────────
+// Request body
+// ─────────────────────────────────────────────────────────────
+
+type geminiBody struct {
+	Contents          any                     `json:"contents"`
+	SystemInstruction *GeminiContent          `json:"systemInstruction,omitempty"`
+	Tools             []geminiTool            `json:"tools,omitempty"`
+	ToolConfig        *geminiToolConfig       `json:"toolConfig,omitempty"`
+	SafetySettings    []GeminiSafetySetting   `json:"safetySettings,omitempty"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parametersJsonSchema,omitempty"`
+}
+
+type geminiToolConfig struct {
+	FunctionCallingConfig geminiFunctionCallingConfig `json:"functionCallingConfig"`
+}
+
+type geminiFunctionCallingConfig struct {
+	Mode                 string   `json:"mode"` // AUTO, ANY or NONE
+	AllowedFunctionNames []string `json:"allowedFunctionNames,omitempty"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature     *float64              `json:"temperature,omitempty"`
+	TopP            *float64              `json:"topP,omitempty"`
+	TopK            *int                  `json:"topK,omitempty"`
+	MaxOutputTokens int                   `json:"maxOutputTokens,omitempty"`
+	StopSequences   []string              `json:"stopSequences,omitempty"`
+	ThinkingConfig  *geminiThinkingConfig `json:"thinkingConfig,omitempty"`
+}
+
+type geminiThinkingConfig struct {
+	ThinkingBudget  int  `json:"thinkingBudget"`
+	IncludeThoughts bool `json:"includeThoughts"`
+}
+
+// geminiPreservedKeys are the keys of the Gemini wire format kept by redaction.
+var geminiPreservedKeys = []string{"thoughtSignature", "mimeType", "fileUri"}
+
+// GeminiContent is a turn of a Gemini conversation.
+type GeminiContent struct {
+	Role  string       `json:"role,omitempty"` // "user" or "model"
+	Parts []GeminiPart `json:"parts"`
+}
+
+// GeminiPart is a part of a Gemini content (exactly one of the data fields is set).
+type GeminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	Thought          bool                    `json:"thought,omitempty"` // Text is a thought summary
+	ThoughtSignature string                  `json:"thoughtSignature,omitempty"`
+	InlineData       *GeminiBlob             `json:"inlineData,omitempty"`
+	FileData         *GeminiFileData         `json:"fileData,omitempty"`
+	FunctionCall     *GeminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *GeminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+// GeminiBlob is inlined binary data.
+type GeminiBlob struct {
+	MimeType string `json:"mimeType"`
+	Data     string `json:"data"` // base64
+}
+
+// GeminiFileData references an uploaded file or a URL.
+type GeminiFileData struct {
+	MimeType string `json:"mimeType,omitempty"`
+	FileURI  string `json:"fileUri"`
+}
+
+// GeminiFunctionCall is a complete function call (Args is a JSON object, never a delta).
+type GeminiFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+// GeminiFunctionResponse is the result of a function call.
+type GeminiFunctionResponse struct {
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+// geminiContentsFromInput converts the request input (string, InputItems, content parts,
+// tool calls, tool outputs and reasoning items) to the system instruction and the contents.
+// Consecutive items of the same role are merged into a single content.
+//
+// Function responses are named after their call (Gemini matches them by name), and the
+// encrypted content of reasoning items is sent back as the thought signature of the next
+// model part.
+func geminiContentsFromInput(input any) (system []string, contents []GeminiContent, err error) {
+	callNames := map[string]string{}
+	pendingSignature := ""
+	add := func(role string, parts ...GeminiPart) {
+		if len(parts) == 0 {
+			return
+		}
+		if role == "model" && pendingSignature != "" && parts[0].ThoughtSignature == "" {
+			parts[0].ThoughtSignature, pendingSignature = pendingSignature, ""
+		}
+		if n := len(contents); n > 0 && contents[n-1].Role == role {
+			contents[n-1].Parts = append(contents[n-1].Parts, parts...)
+			return
+		}
+		contents = append(contents, GeminiContent{Role: role, Parts: parts})
+	}
+	err = walkInput(input, inputVisitor{
+		api: "Gemini",
+		message: func(role string, content any) error {
+			parts, err := geminiParts(content)
+			if err != nil {
+				return err
+			}
+			switch role {
+			case "system", "developer":
+				for _, p := range parts {
+					if p.Text != "" {
+						system = append(system, p.Text)
+					}
+				}
+			case "assistant", "model":
+				add("model", parts...)
+			default:
+				add("user", parts...)
+			}
+			return nil
+		},
+		functionCall: func(callID, name string, args json.RawMessage) error {
+			callNames[callID] = name
+			add("model", GeminiPart{FunctionCall: &GeminiFunctionCall{Name: name, Args: args}})
+			return nil
+		},
+		functionOutput: func(callID, output string) error {
+			name, ok := callNames[callID]
+			if !ok {
+				return fmt.Errorf("textualopenai: no function call %q precedes its output (the Gemini API needs the function name)", callID)
+			}
+			add("user", GeminiPart{FunctionResponse: &GeminiFunctionResponse{Name: name, Response: geminiFunctionResponse(output)}})
+			return nil
+		},
+		reasoning: func(item ReasoningItem) error {
+			if item.EncryptedContent != "" {
+				pendingSignature = item.EncryptedContent
+			}
+			return nil
+		},
+	})
+	return system, contents, err
+}
+
+// geminiFunctionResponse wraps a function output in the object expected by the Gemini API:
+// JSON objects are sent as-is, other outputs as {"output": ...}.
+func geminiFunctionResponse(output string) map[string]any {
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(output), &obj); err == nil && obj != nil {
+		return obj
+	}
+	var v any = output
+	if err := json.Unmarshal([]byte(output), &v); err != nil {
+		v = output
+	}
+	return map[string]any{"output": v}
+}
+
+// geminiParts converts a message content to Gemini parts.
+func geminiParts(content any) ([]GeminiPart, error) {
+	var parts []ContentPart
+	switch v := content.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return []GeminiPart{{Text: v}}, nil
+	case ContentPart:
+		parts = []ContentPart{v}
+	case []ContentPart:
+		parts = v
+	case []any:
+		for _, e := range v {
+			switch p := e.(type) {
+			case ContentPart:
+				parts = append(parts, p)
+			case string:
+				parts = append(parts, Text(p))
+			default:
+				var part ContentPart
+				if err := remarshal(p, &part); err != nil {
+					return nil, fmt.Errorf("textualopenai: unsupported content part %T: %w", e, err)
+				}
+				parts = append(parts, part)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("textualopenai: unsupported message content %T", content)
+	}
+
+	out := make([]GeminiPart, 0, len(parts))
+	for _, p := range parts {
+		part, ok, err := geminiPart(p)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out = append(out, part)
+		}
+	}
+	return out, nil
+}
+
+// geminiPart converts a content part to a text, inline data or file data part.
+func geminiPart(p ContentPart) (GeminiPart, bool, error) {
+	switch p.Type {
+	case InputTextType, "output_text", "text":
+		return GeminiPart{Text: p.Text}, p.Text != "", nil
+
+	case InputImageType:
+		part, err := geminiDataPart(p.ImageURL, p.FileID)
+		return part, true, err
+
+	case InputFileType:
+		ref := p.FileData
+		if ref == "" {
+			ref = p.FileURL
+		}
+		part, err := geminiDataPart(ref, p.FileID)
+		return part, true, err
+
+	case InputAudioType:
+		if p.InputAudio == nil || p.InputAudio.Data == "" {
+			return GeminiPart{}, false, errors.New("textualopenai: input_audio part has no data")
+		}
+		return GeminiPart{InlineData: &GeminiBlob{MimeType: "audio/" + p.InputAudio.Format, Data: p.InputAudio.Data}}, true, nil
+
+	default:
+		return GeminiPart{}, false, fmt.Errorf("textualopenai: content part type %q is not supported by the Gemini API", p.Type)
+	}
+}
+
+// geminiDataPart converts a data URL, a URL or a file URI (FileID) to a Gemini part.
+func geminiDataPart(ref, fileID string) (GeminiPart, error) {
+	if fileID != "" {
+		return GeminiPart{FileData: &GeminiFileData{FileURI: fileID}}, nil
+	}
+	if mediaType, data, ok := strings.Cut(strings.TrimPrefix(ref, "data:"), ";base64,"); ok && strings.HasPrefix(ref, "data:") {
+		return GeminiPart{InlineData: &GeminiBlob{MimeType: mediaType, Data: data}}, nil
+	}
+	if ref != "" {
+		mimeType := ""
+		if u, err := url.Parse(ref); err == nil {
+			mimeType, _, _ = strings.Cut(mime.TypeByExtension(path.Ext(u.Path)), ";")
+		}
+		return GeminiPart{FileData: &GeminiFileData{MimeType: mimeType, FileURI: ref}}, nil
+	}
+	return GeminiPart{}, errors.New("textualopenai: content part has no data, URL or file id")
+}
+
+// ─────────────────────────────────────────────────────────────
+// Stream translation
+// ─────────────────────────────────────────────────────────────
+
+// GeminiChunk is a streamGenerateContent chunk (a GenerateContentResponse).
+type GeminiChunk struct {
+	Candidates     []GeminiCandidate     `json:"candidates,omitempty"`
+	PromptFeedback *GeminiPromptFeedback `json:"promptFeedback,omitempty"`
+	UsageMetadata  json.RawMessage       `json:"usageMetadata,omitempty"`
+	ModelVersion   string                `json:"modelVersion,omitempty"`
+	ResponseID     string                `json:"responseId,omitempty"`
+	Error          *GeminiError          `json:"error,omitempty"`
+}
+
+// GeminiCandidate is a generated candidate (only the first one is translated).
+type GeminiCandidate struct {
+	Content      GeminiContent `json:"content"`
+	FinishReason string        `json:"finishReason,omitempty"` // STOP, MAX_TOKENS, SAFETY, ...
+	Index        int           `json:"index"`
+}
+
+// GeminiPromptFeedback reports why a prompt was blocked.
+type GeminiPromptFeedback struct {
+	BlockReason string `json:"blockReason,omitempty"`
+}
+
+// GeminiError is the payload of an error chunk.
+type GeminiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Status  string `json:"status"`
+}
+
+// geminiStream holds the state of the translation of a stream.
+type geminiStream struct {
+	mu sync.Mutex
+	translatedStream
+	started          bool
+	pendingSignature string
+	finishReason     string
+	usage            json.RawMessage
+}
+
+// reset prepares the translation of a new stream.
+func (s *geminiStream) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.translatedStream.reset()
+	s.started, s.pendingSignature, s.finishReason, s.usage = false, "", "", nil
+}
+
+// translate converts a Gemini chunk to zero or more StreamEvents.
+//
+// Text and thought parts are streamed as deltas of message and reasoning items. Thought
+// signatures become the encrypted content of reasoning items; a signature carried by a
+// function call part is emitted as a reasoning item right before the call.
+func (s *geminiStream) translate(model string, chunk GeminiChunk) []StreamEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response := func(status ResponseStatus) json.RawMessage {
+		return s.response(model, status, map[string]any{"finish_reason": s.finishReason, "usage": s.usage})
+	}
+	// openItem gives a pending signature to the reasoning item it opens.
+	openItem := func(typ string) *translatedItem {
+		it := s.openItem(typ)
+		if typ == ReasoningItemType && it.signature == "" {
+			it.signature, s.pendingSignature = s.pendingSignature, ""
+		}
+		return it
+	}
+	// flushSignature emits a pending signature as a reasoning item preceding the next item.
+	flushSignature := func() {
+		if s.pendingSignature == "" {
+			return
+		}
+		openItem(ReasoningItemType)
+		s.closeOpen()
+	}
+
+	if chunk.Error != nil {
+		s.push(StreamEvent{Type: Error, Code: chunk.Error.Status, Message: chunk.Error.Message})
+		return s.flush()
+	}
+	if !s.started {
+		s.started = true
+		s.responseID = chunk.ResponseID
+		if s.responseID == "" {
+			s.responseID = "gemini"
+		}
+		s.push(StreamEvent{Type: ResponseCreated, Response: response(ResponseStatusInProgress)})
+	}
+	if len(chunk.UsageMetadata) > 0 {
+		s.usage = chunk.UsageMetadata
+	}
+	if len(chunk.Candidates) == 0 {
+		if chunk.PromptFeedback != nil && chunk.PromptFeedback.BlockReason != "" {
+			s.push(StreamEvent{Type: Error, Code: "prompt_blocked", Message: "prompt blocked: " + chunk.PromptFeedback.BlockReason})
+		}
+		return s.flush()
+	}
+
+	candidate := chunk.Candidates[0]
+	for _, part := range candidate.Content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			if part.ThoughtSignature != "" {
+				if s.open != nil && s.open.Type == ReasoningItemType {
+					s.open.signature = part.ThoughtSignature
+				} else {
+					s.pendingSignature = part.ThoughtSignature
+				}
+			}
+			s.closeOpen()
+			flushSignature()
+			s.functionCall(part.FunctionCall.ID, part.FunctionCall.Name, part.FunctionCall.Args)
+
+		case part.Thought:
+			it := openItem(ReasoningItemType)
+			if part.ThoughtSignature != "" {
+				it.signature = part.ThoughtSignature
+			}
+			if part.Text != "" {
+				it.content.WriteString(part.Text)
+				s.push(StreamEvent{Type: ReasoningTextDelta, OutputIndex: it.OutputIndex, ItemID: it.ItemID, Delta: part.Text})
+			}
+
+		default:
+			// A signature on an answer part belongs to the preceding thoughts;
+			// once the answer has started it is optional and dropped.
+			if part.ThoughtSignature != "" && (s.open == nil || s.open.Type != "message") {
+				if s.open != nil {
+					s.open.signature = part.ThoughtSignature
+				} else {
+					s.pendingSignature = part.ThoughtSignature
+				}
+			}
+			if part.Text == "" {
+				continue
+			}
+			if s.open == nil || s.open.Type != "message" {
+				s.closeOpen()
+				flushSignature()
+			}
+			it := openItem("message")
+			it.content.WriteString(part.Text)
+			s.push(StreamEvent{Type: OutputTextDelta, OutputIndex: it.OutputIndex, ItemID: it.ItemID, Delta: part.Text})
+		}
+	}
+
+	if candidate.FinishReason != "" {
+		s.closeOpen()
+		s.pendingSignature = ""
+		s.finishReason = candidate.FinishReason
+		if candidate.FinishReason == "STOP" {
+			s.push(StreamEvent{Type: ResponseCompleted, Response: response(ResponseStatusCompleted)})
+		} else {
+			s.push(StreamEvent{Type: ResponseIncomplete, Response: response(ResponseStatusIncomplete)})
+		}
+	}
+	return s.flush()
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+)
+
+var geminiChunks = []string{
+	`{"responseId":"r1","candidates":[{"content":{"role":"model","parts":[{"text":"Thinking about Paris.","thought":true}]},"index":0}]}`,
+	`{"responseId":"r1","candidates":[{"content":{"role":"model","parts":[{"text":"Hello"}]},"index":0}]}`,
+	`{"responseId":"r1","candidates":[{"content":{"role":"model","parts":[{"text":" world"}]},"index":0}]}`,
+	`{"responseId":"r1","candidates":[{"content":{"role":"model","parts":[{"functionCall":{"name":"get_weather","args":{"city":"Paris"}},"thoughtSignature":"sig"}]},"index":0}]}`,
+	`{"responseId":"r1","candidates":[{"content":{"role":"model","parts":[]},"finishReason":"STOP","index":0}],"usageMetadata":{"promptTokenCount":12,"candidatesTokenCount":8,"thoughtsTokenCount":4,"totalTokenCount":24}}`,
+}
+
+func TestGenerateContentRequestStreamTranslation(t *testing.T) {
+	var gotPath, gotAlt, gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAlt, gotKey = r.URL.Path, r.URL.Query().Get("alt"), r.Header.Get("x-goog-api-key")
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(sseEvents(geminiChunks...)))
+	}))
+	defer srv.Close()
+
+	model := "gemini:gemini-2.5-pro"
+	c := testClient(t, srv.URL, model)
+	req := NewGenerateContentRequest(context.Background(), testModel(t, model))
+	req.Input = "What's the weather in Paris?"
+
+	var mu sync.Mutex
+	var reasoning, arguments, signature string
+	var completed json.RawMessage
+	_ = req.AddObservers(func(e textual.JsonGenericCarrier[StreamEvent]) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch e.Value.Type {
+		case ReasoningTextDone:
+			reasoning = e.Value.Text
+		case FunctionCallArgumentsDone:
+			arguments = e.Value.Arguments
+		case OutputItemDone:
+			var it ReasoningItem
+			if json.Unmarshal(e.Value.Item, &it) == nil && it.Type == ReasoningItemType && it.EncryptedContent != "" {
+				signature = it.EncryptedContent
+			}
+		case ResponseCompleted:
+			completed = e.Value.Response
+		}
+	}, AllEvent)
+	_ = req.AddListeners(StringCarrierFrom, OutputTextDelta)
+
+	text, _, err := c.StreamAndTranscodeResponses(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/models/gemini-2.5-pro:streamGenerateContent" || gotAlt != "sse" || gotKey != "test-key" {
+		t.Fatalf("request = %s?alt=%s (key %q)", gotPath, gotAlt, gotKey)
+	}
+	if text != "Hello world" {
+		t.Fatalf("text = %q", text)
+	}
+	if reasoning != "Thinking about Paris." {
+		t.Fatalf("reasoning = %q", reasoning)
+	}
+	if arguments != `{"city":"Paris"}` {
+		t.Fatalf("arguments = %q", arguments)
+	}
+	// The signature of the function call part is kept for the next turn.
+	if signature != "sig" {
+		t.Fatalf("thought signature = %q", signature)
+	}
+	var res struct {
+		ID    string          `json:"id"`
+		Usage json.RawMessage `json:"usage"`
+	}
+	if err := json.Unmarshal(completed, &res); err != nil {
+		t.Fatal(err)
+	}
+	u, ok := ParseUsage(res.Usage)
+	if res.ID != "r1" || !ok || u.InputTokens != 12 || u.OutputTokens != 12 || u.ReasoningTokens != 4 {
+		t.Fatalf("response = %s, usage = %+v", completed, u)
+	}
+}
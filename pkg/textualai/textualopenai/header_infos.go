@@ -44,6 +44,9 @@ type HeaderInfos struct {
 	RateLimitTokensLimit     int       `json:"ratelimit_tokens_limit,omitempty"`     // x-ratelimit-limit-tokens
 	RateLimitTokensRemaining int       `json:"ratelimit_tokens_remaining,omitempty"` // x-ratelimit-remaining-tokens
 	RateLimitTokensReset     time.Time `json:"ratelimit_tokens_reset,omitempty"`     // x-ratelimit-reset-tokens
+
+	// Stats measures the stream (set by the Client.StreamAndTranscode* helpers).
+	Stats *StreamStats `json:"stream_stats,omitempty"`
 }
 
 // HeaderInfosFromHTTPResponse extracts OpenAI-specific header information
@@ -61,14 +64,25 @@ func HeaderInfosFromHTTPResponse(resp *http.Response) HeaderInfos {
 		APIVersion:     h.Get("openai-version"),
 		RequestID:      h.Get("x-request-id"),
 
-		RateLimitRequestsLimit:     parseInt(h.Get("x-ratelimit-limit-requests")),
-		RateLimitRequestsRemaining: parseInt(h.Get("x-ratelimit-remaining-requests")),
-		RateLimitRequestsReset:     parseResetTime(h.Get("x-ratelimit-reset-requests")),
+		// Anthropic uses its own rate limit headers.
+		RateLimitRequestsLimit:     parseInt(firstHeader(h, "x-ratelimit-limit-requests", "anthropic-ratelimit-requests-limit")),
+		RateLimitRequestsRemaining: parseInt(firstHeader(h, "x-ratelimit-remaining-requests", "anthropic-ratelimit-requests-remaining")),
+		RateLimitRequestsReset:     parseResetTime(firstHeader(h, "x-ratelimit-reset-requests", "anthropic-ratelimit-requests-reset")),
+
+		RateLimitTokensLimit:     parseInt(firstHeader(h, "x-ratelimit-limit-tokens", "anthropic-ratelimit-tokens-limit")),
+		RateLimitTokensRemaining: parseInt(firstHeader(h, "x-ratelimit-remaining-tokens", "anthropic-ratelimit-tokens-remaining")),
+		RateLimitTokensReset:     parseResetTime(firstHeader(h, "x-ratelimit-reset-tokens", "anthropic-ratelimit-tokens-reset")),
+	}
+}
 
-		RateLimitTokensLimit:     parseInt(h.Get("x-ratelimit-limit-tokens")),
-		RateLimitTokensRemaining: parseInt(h.Get("x-ratelimit-remaining-tokens")),
-		RateLimitTokensReset:     parseResetTime(h.Get("x-ratelimit-reset-tokens")),
+// firstHeader returns the value of the first present header.
+func firstHeader(h http.Header, names ...string) string {
+	for _, name := range names {
+		if v := h.Get(name); v != "" {
+			return v
+		}
 	}
+	return ""
 }
 
 // ToString returns a human-readable representation of HeaderInfos,
@@ -123,9 +137,21 @@ func (h HeaderInfos) ToString() string {
 	writeInt("RateLimit Tokens Remaining", h.RateLimitTokensRemaining)
 	writeTime("RateLimit Tokens Reset", h.RateLimitTokensReset)
 
+	if h.Stats != nil {
+		b.WriteString(h.Stats.ToString())
+	}
+
 	return strings.TrimRight(b.String(), "\n")
 }
 
+// setStats sets Stats from the stream stats of r.
+func (h *HeaderInfos) setStats(r Requestable) {
+	if st := streamStatsOf(r); st != nil {
+		stats := st.snapshot()
+		h.Stats = &stats
+	}
+}
+
 // ToJSON returns a JSON encoding of HeaderInfos.
 // ProcessingTime is emitted in milliseconds.
 func (h HeaderInfos) ToJSON() ([]byte, error) {
@@ -169,7 +195,8 @@ func parseMillisecondsDuration(value string) time.Duration {
 }
 
 // parseResetTime parses rate limit reset headers.
-// Some APIs provide UNIX timestamps; if absent or invalid, returns zero time.
+// Some APIs provide UNIX timestamps, OpenAI a delay ("6m0s", "20ms") and
+// Anthropic an RFC 3339 time; if absent or invalid, returns zero time.
 func parseResetTime(value string) time.Time {
 	value = strings.TrimSpace(value)
 	if value == "" {
@@ -180,6 +207,12 @@ func parseResetTime(value string) time.Time {
 	if ts, err := strconv.ParseInt(value, 10, 64); err == nil {
 		return time.Unix(ts, 0)
 	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(d)
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
 
 	return time.Time{}
 }
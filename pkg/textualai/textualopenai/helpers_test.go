@@ -0,0 +1,42 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// testModel resolves a "provider:model" string.
+func testModel(t *testing.T, s string) models.Model {
+	t.Helper()
+	m, err := models.ModelFromString(s)
+	if err != nil {
+		t.Fatalf("ModelFromString(%q): %v", s, err)
+	}
+	return m
+}
+
+// testClient returns a client of model talking to baseURL (usually an httptest server).
+func testClient(t *testing.T, baseURL string, model string) Client {
+	t.Helper()
+	c, err := ClientFrom(baseURL, testModel(t, model), context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.WithApiKey("test-key")
+}
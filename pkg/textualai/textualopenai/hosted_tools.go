@@ -0,0 +1,359 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// Usage sample :
+// ws := textualopenai.NewWebSearchTool()
+// ws.UserLocation = &textualopenai.UserLocation{Type: "approximate", Country: "FR", City: "Paris"}
+// ws.Filters = &textualopenai.WebSearchFilters{AllowedDomains: []string{"legifrance.gouv.fr"}}
+//
+// fs := textualopenai.NewFileSearchTool("vs_123")
+// fs.MaxNumResults = 5
+//
+// req.Tools = append(req.Tools, ws, fs, textualopenai.NewCodeInterpreterTool(textualopenai.AutoContainer()))
+// req.Include = append(req.Include, textualopenai.IncludeWebSearchSources, textualopenai.IncludeFileSearchResults)
+//
+// _ = req.AddObservers(func(e textual.JsonGenericCarrier[textualopenai.StreamEvent]) {
+// 	switch e.Value.Type {
+// 	case textualopenai.OutputItemDone:
+// 		item, _ := textualopenai.DecodeOutputItem(e.Value.Item) // *WebSearchCall, *FileSearchCall, ...
+// 	case textualopenai.OutputTextAnnotationAdded:
+// 		a, _ := e.Value.DecodeAnnotation() // URL / file citations
+// 	}
+// }, textualopenai.OutputItemDone, textualopenai.OutputTextAnnotationAdded)
+
+// ErrHostedToolUnsupported is returned by Validate when a hosted tool is sent to a provider
+// that does not run hosted tools.
+var ErrHostedToolUnsupported = errors.New("textualopenai: hosted tool not supported by the provider")
+
+// Hosted tool types.
+const (
+	WebSearchToolType       = "web_search"
+	FileSearchToolType      = "file_search"
+	CodeInterpreterToolType = "code_interpreter"
+	ImageGenerationToolType = "image_generation"
+)
+
+// Include values exposing hosted tool results in the output items.
+const (
+	IncludeWebSearchSources       = "web_search_call.action.sources"
+	IncludeFileSearchResults      = "file_search_call.results"
+	IncludeCodeInterpreterOutputs = "code_interpreter_call.outputs"
+)
+
+// ─────────────────────────────────────────────────────────────
+// Tool configurations
+// ─────────────────────────────────────────────────────────────
+
+// WebSearchTool lets the model search the web.
+type WebSearchTool struct {
+	Type              string            `json:"type"` // always "web_search"
+	UserLocation      *UserLocation     `json:"user_location,omitempty"`
+	Filters           *WebSearchFilters `json:"filters,omitempty"`
+	SearchContextSize string            `json:"search_context_size,omitempty"` // "low", "medium" or "high"
+}
+
+// UserLocation approximates the user location to refine search results.
+type UserLocation struct {
+	Type     string `json:"type"`              // "approximate"
+	Country  string `json:"country,omitempty"` // ISO 3166-1 alpha-2
+	Region   string `json:"region,omitempty"`
+	City     string `json:"city,omitempty"`
+	Timezone string `json:"timezone,omitempty"` // IANA timezone
+}
+
+// WebSearchFilters restricts the searched domains.
+type WebSearchFilters struct {
+	AllowedDomains []string `json:"allowed_domains,omitempty"`
+}
+
+// NewWebSearchTool returns a web search tool with the provider defaults.
+func NewWebSearchTool() WebSearchTool {
+	return WebSearchTool{Type: WebSearchToolType}
+}
+
+// FileSearchTool lets the model search the files of vector stores.
+type FileSearchTool struct {
+	Type           string                    `json:"type"` // always "file_search"
+	VectorStoreIDs []string                  `json:"vector_store_ids"`
+	MaxNumResults  int                       `json:"max_num_results,omitempty"`
+	RankingOptions *FileSearchRankingOptions `json:"ranking_options,omitempty"`
+	// Filters is a comparison or compound attribute filter (kept generic).
+	Filters any `json:"filters,omitempty"`
+}
+
+// FileSearchRankingOptions tunes the ranking of file search results.
+type FileSearchRankingOptions struct {
+	Ranker         string   `json:"ranker,omitempty"` // e.g. "auto"
+	ScoreThreshold *float64 `json:"score_threshold,omitempty"`
+}
+
+// NewFileSearchTool returns a file search tool over vectorStoreIDs.
+func NewFileSearchTool(vectorStoreIDs ...string) FileSearchTool {
+	return FileSearchTool{Type: FileSearchToolType, VectorStoreIDs: vectorStoreIDs}
+}
+
+// CodeInterpreterTool lets the model run Python code in a sandboxed container.
+type CodeInterpreterTool struct {
+	Type string `json:"type"` // always "code_interpreter"
+	// Container is a container id (string) or a CodeInterpreterContainer.
+	Container any `json:"container"`
+}
+
+// CodeInterpreterContainer configures an automatically created container.
+type CodeInterpreterContainer struct {
+	Type        string   `json:"type"` // "auto"
+	FileIDs     []string `json:"file_ids,omitempty"`
+	MemoryLimit string   `json:"memory_limit,omitempty"` // e.g. "1g", "4g"
+}
+
+// AutoContainer returns a container created on demand, with access to fileIDs.
+func AutoContainer(fileIDs ...string) CodeInterpreterContainer {
+	return CodeInterpreterContainer{Type: "auto", FileIDs: fileIDs}
+}
+
+// NewCodeInterpreterTool returns a code interpreter tool. container is a container id
+// or a CodeInterpreterContainer (see AutoContainer).
+func NewCodeInterpreterTool(container any) CodeInterpreterTool {
+	return CodeInterpreterTool{Type: CodeInterpreterToolType, Container: container}
+}
+
+// ImageGenerationTool lets the model generate or edit images.
+type ImageGenerationTool struct {
+	Type              string `json:"type"` // always "image_generation"
+	Model             string `json:"model,omitempty"`
+	Size              string `json:"size,omitempty"`          // e.g. "1024x1024", "auto"
+	Quality           string `json:"quality,omitempty"`       // "low", "medium", "high", "auto"
+	OutputFormat      string `json:"output_format,omitempty"` // "png", "jpeg", "webp"
+	OutputCompression *int   `json:"output_compression,omitempty"`
+	Background        string `json:"background,omitempty"` // "transparent", "opaque", "auto"
+	Moderation        string `json:"moderation,omitempty"`
+	InputFidelity     string `json:"input_fidelity,omitempty"`
+	PartialImages     int    `json:"partial_images,omitempty"` // streamed partial images (0-3)
+}
+
+// NewImageGenerationTool returns an image generation tool with the provider defaults.
+func NewImageGenerationTool() ImageGenerationTool {
+	return ImageGenerationTool{Type: ImageGenerationToolType}
+}
+
+// hostedToolType returns the type of a hosted tool definition ("" for function/custom tools).
+func hostedToolType(t any) string {
+	switch v := t.(type) {
+	case WebSearchTool, *WebSearchTool:
+		return WebSearchToolType
+	case FileSearchTool, *FileSearchTool:
+		return FileSearchToolType
+	case CodeInterpreterTool, *CodeInterpreterTool:
+		return CodeInterpreterToolType
+	case ImageGenerationTool, *ImageGenerationTool:
+		return ImageGenerationToolType
+	case map[string]any:
+		typ, _ := v["type"].(string)
+		switch typ {
+		case WebSearchToolType, "web_search_preview", FileSearchToolType, CodeInterpreterToolType, ImageGenerationToolType:
+			return typ
+		}
+	}
+	return ""
+}
+
+// validateHostedTools fails fast when hosted tools are sent to a provider that cannot run them.
+// Requests whose provider is unknown are not checked.
+func (r *ResponsesRequest) validateHostedTools() error {
+	info, ok := r.model.ProviderName.ProviderInfo()
+	if !ok || info.SupportsHostedTools {
+		return nil
+	}
+	for _, t := range r.Tools {
+		if typ := hostedToolType(t); typ != "" {
+			return fmt.Errorf("%w: %s (%s)", ErrHostedToolUnsupported, typ, info.DisplayName)
+		}
+	}
+	return nil
+}
+
+// ─────────────────────────────────────────────────────────────
+// Result items
+// ─────────────────────────────────────────────────────────────
+
+// WebSearchCall is a "web_search_call" output item.
+type WebSearchCall struct {
+	ID     string           `json:"id"`
+	Type   string           `json:"type"`
+	Status string           `json:"status"`
+	Action *WebSearchAction `json:"action,omitempty"`
+}
+
+// WebSearchAction describes the action performed by a web search call.
+type WebSearchAction struct {
+	Type    string            `json:"type"` // "search", "open_page", "find"
+	Query   string            `json:"query,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	Pattern string            `json:"pattern,omitempty"`
+	Sources []WebSearchSource `json:"sources,omitempty"` // requires IncludeWebSearchSources
+}
+
+// WebSearchSource is a source consulted by a web search.
+type WebSearchSource struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// FileSearchCall is a "file_search_call" output item.
+type FileSearchCall struct {
+	ID      string             `json:"id"`
+	Type    string             `json:"type"`
+	Status  string             `json:"status"`
+	Queries []string           `json:"queries,omitempty"`
+	Results []FileSearchResult `json:"results,omitempty"` // requires IncludeFileSearchResults
+}
+
+// FileSearchResult is a chunk retrieved by a file search.
+type FileSearchResult struct {
+	FileID     string         `json:"file_id"`
+	Filename   string         `json:"filename,omitempty"`
+	Score      float64        `json:"score,omitempty"`
+	Text       string         `json:"text,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// CodeInterpreterCall is a "code_interpreter_call" output item.
+type CodeInterpreterCall struct {
+	ID          string                  `json:"id"`
+	Type        string                  `json:"type"`
+	Status      string                  `json:"status"`
+	Code        string                  `json:"code,omitempty"`
+	ContainerID string                  `json:"container_id,omitempty"`
+	Outputs     []CodeInterpreterOutput `json:"outputs,omitempty"` // requires IncludeCodeInterpreterOutputs
+}
+
+// CodeInterpreterOutput is a "logs" or an "image" output of the interpreter.
+type CodeInterpreterOutput struct {
+	Type string `json:"type"`
+	Logs string `json:"logs,omitempty"`
+	URL  string `json:"url,omitempty"`
+}
+
+// ImageGenerationCall is an "image_generation_call" output item.
+type ImageGenerationCall struct {
+	ID            string `json:"id"`
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	Result        string `json:"result,omitempty"` // base64 encoded image
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+	OutputFormat  string `json:"output_format,omitempty"`
+}
+
+// Image decodes the generated image.
+func (c *ImageGenerationCall) Image() ([]byte, error) {
+	if c.Result == "" {
+		return nil, errors.New("textualopenai: image generation call has no result")
+	}
+	return base64.StdEncoding.DecodeString(c.Result)
+}
+
+// DecodeOutputItem decodes a hosted tool or reasoning output item (StreamEvent.Item) into
+// *WebSearchCall, *FileSearchCall, *CodeInterpreterCall, *ImageGenerationCall or *ReasoningItem.
+// Other item types are returned as map[string]any.
+func DecodeOutputItem(raw json.RawMessage) (any, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &head); err != nil {
+		return nil, fmt.Errorf("textualopenai: decode output item: %w", err)
+	}
+	var item any
+	switch head.Type {
+	case "web_search_call":
+		item = &WebSearchCall{}
+	case "file_search_call":
+		item = &FileSearchCall{}
+	case "code_interpreter_call":
+		item = &CodeInterpreterCall{}
+	case "image_generation_call":
+		item = &ImageGenerationCall{}
+	case ReasoningItemType:
+		item = &ReasoningItem{}
+	default:
+		m := map[string]any{}
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, fmt.Errorf("textualopenai: decode output item: %w", err)
+		}
+		return m, nil
+	}
+	if err := json.Unmarshal(raw, item); err != nil {
+		return nil, fmt.Errorf("textualopenai: decode %s item: %w", head.Type, err)
+	}
+	return item, nil
+}
+
+// ─────────────────────────────────────────────────────────────
+// Annotations
+// ─────────────────────────────────────────────────────────────
+
+// Annotation types.
+const (
+	AnnotationURLCitation           = "url_citation"
+	AnnotationFileCitation          = "file_citation"
+	AnnotationContainerFileCitation = "container_file_citation"
+	AnnotationFilePath              = "file_path"
+)
+
+// Annotation is an output text annotation: URL citations (web search), file citations
+// (file search) and container files (code interpreter).
+type Annotation struct {
+	Type string `json:"type"`
+
+	// url_citation / container_file_citation: cited text range.
+	StartIndex int `json:"start_index,omitempty"`
+	EndIndex   int `json:"end_index,omitempty"`
+
+	// url_citation
+	URL   string `json:"url,omitempty"`
+	Title string `json:"title,omitempty"`
+
+	// file_citation / container_file_citation / file_path
+	FileID      string `json:"file_id,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+	ContainerID string `json:"container_id,omitempty"`
+	Index       int    `json:"index,omitempty"`
+}
+
+// DecodeAnnotation decodes the annotation of an OutputTextAnnotationAdded event.
+func (s StreamEvent) DecodeAnnotation() (Annotation, error) {
+	var a Annotation
+	if len(s.Annotation) == 0 {
+		return a, errors.New("textualopenai: event has no annotation")
+	}
+	if err := json.Unmarshal(s.Annotation, &a); err != nil {
+		return a, fmt.Errorf("textualopenai: decode annotation: %w", err)
+	}
+	return a, nil
+}
+
+// SupportsHostedTools reports whether the provider of model runs hosted tools.
+func SupportsHostedTools(model models.Model) bool {
+	return model.ProviderInfo().SupportsHostedTools
+}
@@ -0,0 +1,366 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/instrumentation"
+)
+
+// Usage sample :
+// mem := instrumentation.NewInMemory() // or an OpenTelemetry adapter
+// client = client.WithInstrumentation(mem)
+// _, _, err := client.StreamAndTranscodeResponses(ctx, req)
+// // spans: "chat gpt-4.1" (client) > "execute_tool get_weather" (internal)
+// // histograms: gen_ai.client.operation.duration, gen_ai.client.token.usage, ...
+//
+// // Tool spans only (e.g. a request streamed without Client.Stream).
+// req.SetInstrumentation(mem)
+
+// WithInstrumentation traces the requests sent by Client.Stream (nil disables it):
+// a client span per call with the GenAI attributes (model, provider, tokens, finish reason,
+// time to first token), a child span per executed function call, and the duration,
+// token usage, time to first token and throughput histograms.
+//
+// The call span ends with the terminal event of the stream; the StreamAndTranscode*
+// helpers also end it when the stream is interrupted.
+func (c Client) WithInstrumentation(i instrumentation.Instrumentation) Client {
+	c.instrumentation = i
+	return c
+}
+
+// SetInstrumentation sets the instrumentation of the request. It takes precedence over the
+// client instrumentation, and traces the function calls of requests streamed without Client.Stream.
+func (r *ResponsesRequest) SetInstrumentation(i instrumentation.Instrumentation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instrumentation = i
+}
+
+// Instrumentation returns the request instrumentation (nil if none).
+func (r *ResponsesRequest) Instrumentation() instrumentation.Instrumentation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.instrumentation
+}
+
+// baseRequest is implemented by every request embedding *ResponsesRequest.
+type baseRequest interface {
+	base() *ResponsesRequest
+}
+
+func (r *ResponsesRequest) base() *ResponsesRequest {
+	return r
+}
+
+// llmCall is the instrumentation state of a streamed call.
+type llmCall struct {
+	inst  instrumentation.Instrumentation
+	ctx   context.Context // carries span
+	span  instrumentation.Span
+	attrs []instrumentation.Attribute // metric attributes
+
+	mu            sync.Mutex
+	start         time.Time
+	firstToken    time.Time
+	responseID    string
+	responseModel string
+	ended         bool
+}
+
+// beginCall starts the call span of r when the request or the client is instrumented.
+// It returns nil otherwise.
+func (c Client) beginCall(r Requestable, endpoint string) *llmCall {
+	b, ok := r.(baseRequest)
+	if !ok {
+		return nil
+	}
+	req := b.base()
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	inst := req.instrumentation
+	if inst == nil {
+		inst = c.instrumentation
+	}
+	if inst == nil {
+		return nil
+	}
+	if req.call != nil {
+		req.call.end(nil)
+	}
+
+	attrs := []instrumentation.Attribute{
+		instrumentation.String(instrumentation.AttrOperationName, instrumentation.OperationChat),
+		instrumentation.String(instrumentation.AttrProviderName, string(c.model.ProviderName)),
+		instrumentation.String(instrumentation.AttrRequestModel, string(req.Model)),
+	}
+	if u, err := url.Parse(endpoint); err == nil && u.Hostname() != "" {
+		attrs = append(attrs, instrumentation.String(instrumentation.AttrServerAddress, u.Hostname()))
+		if port, err := strconv.Atoi(u.Port()); err == nil {
+			attrs = append(attrs, instrumentation.Int(instrumentation.AttrServerPort, port))
+		}
+	}
+	spanAttrs := append([]instrumentation.Attribute(nil), attrs...)
+	if req.MaxOutputTokens > 0 {
+		spanAttrs = append(spanAttrs, instrumentation.Int(instrumentation.AttrRequestMaxTokens, req.MaxOutputTokens))
+	}
+	if req.Temperature != nil {
+		spanAttrs = append(spanAttrs, instrumentation.Float64(instrumentation.AttrRequestTemperature, *req.Temperature))
+	}
+	if req.TopP != nil {
+		spanAttrs = append(spanAttrs, instrumentation.Float64(instrumentation.AttrRequestTopP, *req.TopP))
+	}
+
+	ctx, span := inst.StartSpan(req.Context(), instrumentation.OperationChat+" "+string(req.Model), instrumentation.SpanKindClient, spanAttrs...)
+	req.call = &llmCall{inst: inst, ctx: ctx, span: span, attrs: attrs, start: time.Now()}
+	return req.call
+}
+
+// observeCall updates the call instrumentation with a stream event.
+func (r *ResponsesRequest) observeCall(ev StreamEvent) {
+	r.mu.Lock()
+	call := r.call
+	r.mu.Unlock()
+	if call != nil {
+		call.observe(ev)
+	}
+}
+
+// endCall ends the call span of a request (if any) with err.
+func endCall(r Requestable, err error) {
+	if b, ok := r.(baseRequest); ok {
+		req := b.base()
+		req.mu.Lock()
+		call := req.call
+		req.mu.Unlock()
+		call.end(err)
+	}
+}
+
+func (c *llmCall) observe(ev StreamEvent) {
+	switch ev.Type {
+	case OutputTextDelta, ReasoningSummaryTextDelta, ReasoningTextDelta, RefusalDelta, FunctionCallArgumentsDelta:
+		c.mu.Lock()
+		if c.firstToken.IsZero() && !c.ended {
+			c.firstToken = time.Now()
+		}
+		c.mu.Unlock()
+	case ResponseCreated, ResponseInProgress:
+		c.setResponse(ev.Response)
+	case ResponseCompleted, ResponseIncomplete:
+		c.setResponse(ev.Response)
+		c.finish(ev.Response, nil)
+	case ResponseFailed:
+		c.setResponse(ev.Response)
+		var resp Response
+		_ = json.Unmarshal(ev.Response, &resp)
+		var err error = resp.Error
+		if resp.Error == nil {
+			err = errors.New("textualopenai: response failed")
+		}
+		c.finish(ev.Response, err)
+	case Error:
+		c.finish(nil, &ResponseError{Code: firstNonBlank(ev.Code, "error"), Message: ev.Message})
+	}
+}
+
+func (c *llmCall) setResponse(raw json.RawMessage) {
+	var resp Response
+	if len(raw) == 0 || json.Unmarshal(raw, &resp) != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.responseID = firstNonBlank(resp.ID, c.responseID)
+	c.responseModel = firstNonBlank(resp.Model, c.responseModel)
+}
+
+// finish sets the response attributes and ends the call.
+func (c *llmCall) finish(raw json.RawMessage, err error) {
+	c.mu.Lock()
+	ended := c.ended
+	c.mu.Unlock()
+	if ended {
+		return
+	}
+	var attrs []instrumentation.Attribute
+	if reason := FinishReason(raw); reason != "" {
+		attrs = append(attrs, instrumentation.Strings(instrumentation.AttrResponseFinishReasons, []string{reason}))
+	}
+	var resp Response
+	_ = json.Unmarshal(raw, &resp)
+	if u, ok := resp.TokenUsage(); ok {
+		attrs = append(attrs,
+			instrumentation.Int(instrumentation.AttrUsageInputTokens, u.InputTokens),
+			instrumentation.Int(instrumentation.AttrUsageOutputTokens, u.OutputTokens),
+		)
+		c.recordUsage(u)
+	}
+	c.span.SetAttributes(attrs...)
+	c.end(err)
+}
+
+func (c *llmCall) recordUsage(u Usage) {
+	c.mu.Lock()
+	attrs, firstToken := c.metricAttrsLocked(), c.firstToken
+	c.mu.Unlock()
+	for tokenType, n := range map[string]int{"input": u.InputTokens, "output": u.OutputTokens} {
+		typed := append(append([]instrumentation.Attribute(nil), attrs...), instrumentation.String(instrumentation.AttrTokenType, tokenType))
+		c.inst.RecordHistogram(c.ctx, instrumentation.MetricTokenUsage, float64(n), typed...)
+	}
+	if !firstToken.IsZero() && u.OutputTokens > 0 {
+		if elapsed := time.Since(firstToken).Seconds(); elapsed > 0 {
+			c.inst.RecordHistogram(c.ctx, instrumentation.MetricTokenThroughput, float64(u.OutputTokens)/elapsed, attrs...)
+		}
+	}
+}
+
+// metricAttrsLocked returns the metric attributes, with the response model when known.
+func (c *llmCall) metricAttrsLocked() []instrumentation.Attribute {
+	attrs := append([]instrumentation.Attribute(nil), c.attrs...)
+	if c.responseModel != "" {
+		attrs = append(attrs, instrumentation.String(instrumentation.AttrResponseModel, c.responseModel))
+	}
+	return attrs
+}
+
+// end ends the span and records the duration metrics. Later calls are ignored; c may be nil.
+func (c *llmCall) end(err error) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	if c.ended {
+		c.mu.Unlock()
+		return
+	}
+	c.ended = true
+	now := time.Now()
+	attrs := c.metricAttrsLocked()
+	var spanAttrs []instrumentation.Attribute
+	if c.responseID != "" {
+		spanAttrs = append(spanAttrs, instrumentation.String(instrumentation.AttrResponseID, c.responseID))
+	}
+	if c.responseModel != "" {
+		spanAttrs = append(spanAttrs, instrumentation.String(instrumentation.AttrResponseModel, c.responseModel))
+	}
+	ttft := time.Duration(0)
+	if !c.firstToken.IsZero() {
+		ttft = c.firstToken.Sub(c.start)
+		spanAttrs = append(spanAttrs, instrumentation.Float64(instrumentation.AttrTimeToFirstToken, ttft.Seconds()))
+	}
+	c.mu.Unlock()
+
+	if err != nil {
+		errType := instrumentation.String(instrumentation.AttrErrorType, errorType(err))
+		spanAttrs = append(spanAttrs, errType)
+		attrs = append(attrs, errType)
+		c.span.RecordError(err)
+	}
+	c.span.SetAttributes(spanAttrs...)
+	c.span.End()
+
+	c.inst.RecordHistogram(c.ctx, instrumentation.MetricOperationDuration, now.Sub(c.start).Seconds(), attrs...)
+	if ttft > 0 {
+		c.inst.RecordHistogram(c.ctx, instrumentation.MetricTimeToFirstToken, ttft.Seconds(), attrs...)
+	}
+}
+
+// errorType returns a low cardinality error.type value.
+func errorType(err error) string {
+	var respErr *ResponseError
+	var apiErr *APIError
+	var streamErr *StreamError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &respErr):
+		return respErr.Code
+	case errors.As(err, &apiErr):
+		return strconv.Itoa(apiErr.StatusCode)
+	case errors.As(err, &streamErr):
+		return strconv.Itoa(streamErr.StatusCode)
+	}
+	return "_OTHER"
+}
+
+// startToolSpan starts the span of a function call execution, child of the call span.
+// The returned span is never nil.
+func (r *ResponsesRequest) startToolSpan(ctx context.Context, call FunctionCall, reg registeredFunctionTool) (context.Context, instrumentation.Span) {
+	r.mu.Lock()
+	inst, llm := r.instrumentation, r.call
+	r.mu.Unlock()
+	if llm != nil {
+		if inst == nil {
+			inst = llm.inst
+		}
+		ctx = inst.ContextWithSpan(ctx, llm.span)
+	}
+	return startToolSpan(ctx, inst, call, reg)
+}
+
+// startToolSpan starts the span of a function call execution with inst (Nop when nil).
+func startToolSpan(ctx context.Context, inst instrumentation.Instrumentation, call FunctionCall, reg registeredFunctionTool) (context.Context, instrumentation.Span) {
+	if inst == nil {
+		inst = instrumentation.Nop()
+	}
+	toolType := "function"
+	if reg.Custom != nil {
+		toolType = "extension"
+	}
+	return inst.StartSpan(ctx, instrumentation.OperationExecuteTool+" "+call.Name, instrumentation.SpanKindInternal,
+		instrumentation.String(instrumentation.AttrOperationName, instrumentation.OperationExecuteTool),
+		instrumentation.String(instrumentation.AttrToolName, call.Name),
+		instrumentation.String(instrumentation.AttrToolCallID, call.CallID),
+		instrumentation.String(instrumentation.AttrToolType, toolType),
+	)
+}
+
+// endToolSpan ends the span of a function call execution.
+func endToolSpan(span instrumentation.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetAttributes(instrumentation.String(instrumentation.AttrErrorType, toolErrorType(err)))
+	}
+	span.End()
+}
+
+func toolErrorType(err error) string {
+	switch {
+	case errors.Is(err, ErrFunctionCallDenied):
+		return "denied"
+	case errors.Is(err, ErrInvalidFunctionArguments):
+		return "invalid_arguments"
+	case errors.Is(err, ErrFunctionTimeout), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, ErrFunctionPanic):
+		return "panic"
+	case errors.Is(err, ErrFunctionOutputTooLarge):
+		return "output_too_large"
+	case errors.Is(err, context.Canceled):
+		return "cancelled"
+	}
+	return "_OTHER"
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/instrumentation"
+)
+
+func TestInstrumentationChatSpan(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(sseEvents(
+			resumableEvents[0], resumableEvents[1], resumableEvents[2],
+			`{"type":"response.completed","sequence_number":3,"response":{"id":"resp_1","status":"completed","model":"gpt-4.1-2025-04-14","usage":{"input_tokens":12,"output_tokens":2,"total_tokens":14}}}`,
+		)))
+	}))
+	defer srv.Close()
+
+	mem := instrumentation.NewInMemory()
+	model := "openai:gpt-4.1"
+	c := testClient(t, srv.URL, model).WithInstrumentation(mem)
+	req := NewResponsesRequest(context.Background(), testModel(t, model))
+	req.Input = "hi"
+	if _, _, err := c.StreamAndTranscodeResponses(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := mem.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %+v", spans)
+	}
+	s := spans[0]
+	if s.Name != "chat gpt-4.1" || s.Kind != instrumentation.SpanKindClient || s.End.IsZero() || s.Err != nil {
+		t.Fatalf("span = %+v", s)
+	}
+	for key, want := range map[string]any{
+		instrumentation.AttrOperationName:     instrumentation.OperationChat,
+		instrumentation.AttrResponseID:        "resp_1",
+		instrumentation.AttrUsageInputTokens:  12,
+		instrumentation.AttrUsageOutputTokens: 2,
+	} {
+		if got := s.Attributes[key]; got != want {
+			t.Errorf("%s = %v (%T), want %v", key, got, got, want)
+		}
+	}
+	if n := len(mem.Measurements(instrumentation.MetricOperationDuration)); n != 1 {
+		t.Fatalf("%d operation duration measurements, want 1", n)
+	}
+	if n := len(mem.Measurements(instrumentation.MetricTimeToFirstToken)); n != 1 {
+		t.Fatalf("%d time to first token measurements, want 1", n)
+	}
+}
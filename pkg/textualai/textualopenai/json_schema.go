@@ -0,0 +1,477 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonSchema is a compiled JSON Schema used to validate function call arguments.
+//
+// It implements the subset of JSON Schema used by function tools:
+// type, enum, const, properties, required, additionalProperties, items,
+// min/max (length, items, value), exclusive bounds, pattern, anyOf, oneOf, allOf, not
+// and local $ref ("#/$defs/..." and "#/definitions/...").
+type jsonSchema struct {
+	root     any
+	refs     map[string]any            // resolved $ref targets
+	patterns map[string]*regexp.Regexp // compiled patterns
+}
+
+// compileJSONSchema normalizes a schema (map[string]any, struct, json.RawMessage, ...)
+// into its decoded JSON form, resolves its references and compiles its patterns once.
+// Unresolvable or circular references and invalid patterns are reported here, at registration.
+// A nil schema returns (nil, nil): nothing to validate.
+func compileJSONSchema(schema any) (*jsonSchema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+	var b []byte
+	switch v := schema.(type) {
+	case json.RawMessage:
+		b = v
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		var err error
+		b, err = json.Marshal(schema)
+		if err != nil {
+			return nil, fmt.Errorf("textualopenai: invalid JSON schema: %w", err)
+		}
+	}
+	var root any
+	if err := json.Unmarshal(b, &root); err != nil {
+		return nil, fmt.Errorf("textualopenai: invalid JSON schema: %w", err)
+	}
+	if _, ok := root.(map[string]any); !ok {
+		return nil, fmt.Errorf("textualopenai: invalid JSON schema: expected an object")
+	}
+	s := &jsonSchema{root: root, refs: map[string]any{}, patterns: map[string]*regexp.Regexp{}}
+	if err := s.prepare(root); err != nil {
+		return nil, fmt.Errorf("textualopenai: invalid JSON schema: %w", err)
+	}
+	if err := s.checkRefCycles(); err != nil {
+		return nil, fmt.Errorf("textualopenai: invalid JSON schema: %w", err)
+	}
+	return s, nil
+}
+
+// prepare walks the schema to resolve every $ref and compile every pattern.
+func (s *jsonSchema) prepare(node any) error {
+	switch n := node.(type) {
+	case map[string]any:
+		if ref, ok := n["$ref"].(string); ok {
+			if _, done := s.refs[ref]; !done {
+				target, err := s.resolveRef(ref)
+				if err != nil {
+					return err
+				}
+				s.refs[ref] = target
+			}
+		}
+		if p, ok := n["pattern"].(string); ok {
+			if _, done := s.patterns[p]; !done {
+				re, err := regexp.Compile(p)
+				if err != nil {
+					return fmt.Errorf("pattern %q: %v", p, err)
+				}
+				s.patterns[p] = re
+			}
+		}
+		for k, v := range n {
+			switch k {
+			case "enum", "const", "default", "examples":
+				// Values, not schemas.
+				continue
+			case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+				// Maps of schemas: their keys are names, not keywords.
+				sub, _ := v.(map[string]any)
+				for _, sc := range sub {
+					if err := s.prepare(sc); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := s.prepare(v); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, v := range n {
+			if err := s.prepare(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRefCycles rejects references looping back to themselves without consuming the value
+// (e.g. {"$defs":{"a":{"$ref":"#/$defs/a"}}}): validating them would never end.
+// Recursion through properties or items is fine since it descends into the value.
+func (s *jsonSchema) checkRefCycles() error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var visit func(ref string) error
+	visit = func(ref string) error {
+		switch state[ref] {
+		case visiting:
+			return fmt.Errorf("circular $ref %q", ref)
+		case visited:
+			return nil
+		}
+		state[ref] = visiting
+		for _, next := range inPlaceRefs(s.refs[ref], nil) {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		state[ref] = visited
+		return nil
+	}
+	for ref := range s.refs {
+		if err := visit(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inPlaceRefs appends the references of a schema validated against the same value:
+// its own $ref and those of its allOf, anyOf, oneOf and not subschemas.
+func inPlaceRefs(node any, refs []string) []string {
+	sc, ok := node.(map[string]any)
+	if !ok {
+		return refs
+	}
+	if ref, ok := sc["$ref"].(string); ok {
+		refs = append(refs, ref)
+	}
+	for _, k := range []string{"allOf", "anyOf", "oneOf"} {
+		if subs, ok := sc[k].([]any); ok {
+			for _, sub := range subs {
+				refs = inPlaceRefs(sub, refs)
+			}
+		}
+	}
+	if not, ok := sc["not"]; ok {
+		refs = inPlaceRefs(not, refs)
+	}
+	return refs
+}
+
+// Validate checks raw JSON against the schema.
+func (s *jsonSchema) Validate(raw json.RawMessage) error {
+	if s == nil {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return fmt.Errorf("arguments are not valid JSON: %v", err)
+	}
+	return s.validate(s.root, value, "$")
+}
+
+func (s *jsonSchema) validate(schema any, value any, path string) error {
+	switch sc := schema.(type) {
+	case bool:
+		if !sc {
+			return fmt.Errorf("%s: no value is allowed here", path)
+		}
+		return nil
+	case map[string]any:
+		return s.validateObjectSchema(sc, value, path)
+	default:
+		return nil
+	}
+}
+
+func (s *jsonSchema) validateObjectSchema(sc map[string]any, value any, path string) error {
+	if ref, ok := sc["$ref"].(string); ok {
+		target, ok := s.refs[ref]
+		if !ok {
+			return fmt.Errorf("%s: unresolvable $ref %q", path, ref)
+		}
+		if err := s.validate(target, value, path); err != nil {
+			return err
+		}
+	}
+
+	if t, ok := sc["type"]; ok {
+		if err := checkType(t, value, path); err != nil {
+			return err
+		}
+	}
+
+	if enum, ok := sc["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value %s is not one of %s", path, compactJSON(value), compactJSON(enum))
+		}
+	}
+	if c, ok := sc["const"]; ok && !reflect.DeepEqual(c, value) {
+		return fmt.Errorf("%s: value must be %s", path, compactJSON(c))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if err := s.validateProperties(sc, v, path); err != nil {
+			return err
+		}
+	case []any:
+		if n, ok := number(sc["minItems"]); ok && float64(len(v)) < n {
+			return fmt.Errorf("%s: expected at least %v items", path, n)
+		}
+		if n, ok := number(sc["maxItems"]); ok && float64(len(v)) > n {
+			return fmt.Errorf("%s: expected at most %v items", path, n)
+		}
+		if items, ok := sc["items"]; ok {
+			for i, e := range v {
+				if err := s.validate(items, e, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if n, ok := number(sc["minLength"]); ok && length < n {
+			return fmt.Errorf("%s: expected at least %v characters", path, n)
+		}
+		if n, ok := number(sc["maxLength"]); ok && length > n {
+			return fmt.Errorf("%s: expected at most %v characters", path, n)
+		}
+		if p, ok := sc["pattern"].(string); ok {
+			if re := s.patterns[p]; re != nil && !re.MatchString(v) {
+				return fmt.Errorf("%s: %q does not match pattern %q", path, v, p)
+			}
+		}
+	case float64:
+		if n, ok := number(sc["minimum"]); ok && v < n {
+			return fmt.Errorf("%s: %v is lower than the minimum %v", path, v, n)
+		}
+		if n, ok := number(sc["maximum"]); ok && v > n {
+			return fmt.Errorf("%s: %v is greater than the maximum %v", path, v, n)
+		}
+		if n, ok := number(sc["exclusiveMinimum"]); ok && v <= n {
+			return fmt.Errorf("%s: %v must be greater than %v", path, v, n)
+		}
+		if n, ok := number(sc["exclusiveMaximum"]); ok && v >= n {
+			return fmt.Errorf("%s: %v must be lower than %v", path, v, n)
+		}
+	}
+
+	if all, ok := sc["allOf"].([]any); ok {
+		for _, sub := range all {
+			if err := s.validate(sub, value, path); err != nil {
+				return err
+			}
+		}
+	}
+	if anyOf, ok := sc["anyOf"].([]any); ok {
+		var firstErr error
+		matched := false
+		for _, sub := range anyOf {
+			if err := s.validate(sub, value, path); err == nil {
+				matched = true
+				break
+			} else if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: value does not match any allowed schema (%v)", path, firstErr)
+		}
+	}
+	if one, ok := sc["oneOf"].([]any); ok {
+		matches := 0
+		for _, sub := range one {
+			if s.validate(sub, value, path) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s: value must match exactly one schema (matched %d)", path, matches)
+		}
+	}
+	if not, ok := sc["not"]; ok && s.validate(not, value, path) == nil {
+		return fmt.Errorf("%s: value matches a forbidden schema", path)
+	}
+	return nil
+}
+
+func (s *jsonSchema) validateProperties(sc map[string]any, obj map[string]any, path string) error {
+	if required, ok := sc["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := obj[name]; name != "" && !present {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+	}
+
+	props, _ := sc["properties"].(map[string]any)
+
+	// Iterate in a deterministic order so error messages are stable.
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		child := path + "." + k
+		if ps, ok := props[k]; ok {
+			if err := s.validate(ps, obj[k], child); err != nil {
+				return err
+			}
+			continue
+		}
+		switch ap := sc["additionalProperties"].(type) {
+		case bool:
+			if !ap {
+				return fmt.Errorf("%s: unexpected property %q", path, k)
+			}
+		case map[string]any:
+			if err := s.validate(ap, obj[k], child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) resolveRef(ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref %q (only local references are supported)", ref)
+	}
+	var node any = s.root
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/"), "/") {
+		if part == "" {
+			continue
+		}
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		if node, ok = m[part]; !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+	return node, nil
+}
+
+func checkType(t any, value any, path string) error {
+	var types []string
+	switch tt := t.(type) {
+	case string:
+		types = []string{tt}
+	case []any:
+		for _, e := range tt {
+			if s, ok := e.(string); ok {
+				types = append(types, s)
+			}
+		}
+	default:
+		return nil
+	}
+	for _, typ := range types {
+		if isJSONType(typ, value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(types, " or "), jsonTypeOf(value))
+}
+
+func isJSONType(typ string, value any) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return false
+	}
+}
+
+func jsonTypeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func number(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
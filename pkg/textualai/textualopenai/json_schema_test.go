@@ -0,0 +1,114 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+	"type": "object",
+	"properties": {
+		"city": {"type": "string", "minLength": 2, "pattern": "^[A-Z]"},
+		"unit": {"enum": ["celsius", "fahrenheit"]},
+		"days": {"type": "integer", "minimum": 1, "maximum": 7},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2},
+		"place": {"$ref": "#/$defs/place"}
+	},
+	"required": ["city"],
+	"additionalProperties": false,
+	"$defs": {
+		"place": {"type": "object", "properties": {"near": {"$ref": "#/$defs/place"}}, "required": ["name"]}
+	}
+}`
+
+func TestJSONSchemaValidate(t *testing.T) {
+	s, err := compileJSONSchema(json.RawMessage(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args    string
+		wantErr string // empty: valid
+	}{
+		{`{"city":"Paris"}`, ""},
+		{`{"city":"Paris","unit":"celsius","days":3,"tags":["a"],"place":{"name":"x","near":{"name":"y"}}}`, ""},
+		{`{}`, `$: missing required property "city"`},
+		{`{"city":"paris"}`, `$.city: "paris" does not match pattern "^[A-Z]"`},
+		{`{"city":"P"}`, `$.city: expected at least 2 characters`},
+		{`{"city":"Paris","unit":"kelvin"}`, `$.unit: value "kelvin" is not one of ["celsius","fahrenheit"]`},
+		{`{"city":"Paris","days":1.5}`, `$.days: expected integer, got number`},
+		{`{"city":"Paris","days":8}`, `$.days: 8 is greater than the maximum 7`},
+		{`{"city":"Paris","tags":["a",1]}`, `$.tags[1]: expected string, got integer`},
+		{`{"city":"Paris","tags":["a","b","c"]}`, `$.tags: expected at most 2 items`},
+		{`{"city":"Paris","place":{"name":"x","near":{}}}`, `$.place.near: missing required property "name"`},
+		{`{"city":"Paris","extra":true}`, `$: unexpected property "extra"`},
+		{`{"city":`, `arguments are not valid JSON`},
+	}
+	for _, tt := range tests {
+		err := s.Validate(json.RawMessage(tt.args))
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("Validate(%s) = %v, want nil", tt.args, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("Validate(%s) = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestJSONSchemaKeywordNamedProperties(t *testing.T) {
+	// Properties named after value keywords are still schemas.
+	s, err := compileJSONSchema(json.RawMessage(`{
+		"$defs": {"s": {"type": "string"}},
+		"properties": {
+			"enum": {"$ref": "#/$defs/s"},
+			"default": {"type": "string", "pattern": "^[a-z]+$"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(json.RawMessage(`{"enum":"x","default":"abc"}`)); err != nil {
+		t.Fatalf("Validate = %v", err)
+	}
+	if err := s.Validate(json.RawMessage(`{"enum":1}`)); err == nil || !strings.Contains(err.Error(), "$.enum: expected string") {
+		t.Fatalf("Validate = %v", err)
+	}
+	if err := s.Validate(json.RawMessage(`{"default":"ABC"}`)); err == nil || !strings.Contains(err.Error(), "does not match pattern") {
+		t.Fatalf("Validate = %v", err)
+	}
+}
+
+func TestCompileJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		schema  string
+		wantErr string
+	}{
+		{`{"$defs":{"a":{"$ref":"#/$defs/a"}}}`, `circular $ref "#/$defs/a"`},
+		{`{"$defs":{"a":{"allOf":[{"$ref":"#/$defs/b"}]},"b":{"anyOf":[{"$ref":"#/$defs/a"}]}}}`, `circular $ref`},
+		{`{"properties":{"x":{"$ref":"#/$defs/missing"}}}`, `unresolvable $ref "#/$defs/missing"`},
+		{`{"properties":{"x":{"$ref":"other.json#/a"}}}`, `only local references are supported`},
+		{`{"properties":{"x":{"type":"string","pattern":"(["}}}`, `pattern "(["`},
+		{`[]`, `expected an object`},
+	}
+	for _, tt := range tests {
+		_, err := compileJSONSchema(json.RawMessage(tt.schema))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("compileJSONSchema(%s) = %v, want %q", tt.schema, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,683 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// Usage sample :
+// model, _ := models.ModelFromString("anthropic:claude-sonnet-4-5")
+// client, _ := textualopenai.ClientFrom("", model, ctx) // ANTHROPIC_API_KEY
+// req := textualopenai.NewMessagesRequest(ctx, model)
+// req.Instructions = "Answer in one sentence."
+// req.Input = "What's the weather in Paris?"
+// _ = req.RegisterFunctionTool("get_weather", "...", schema, getWeather) // same tool delegate
+// _ = req.AddListeners(textualopenai.StringCarrierFrom, textualopenai.OutputTextDelta)
+// text, _, err := client.StreamAndTranscodeResponses(ctx, req)
+//
+// // The Messages API is stateless: continue a tool loop by replaying the turn.
+// next := textualopenai.NewMessagesRequest(ctx, model)
+// next.Input = append([]any{textualopenai.InputItem{Role: "user", Content: "What's the weather in Paris?"}}, req.ContinuationItems()...)
+
+// AnthropicVersion is sent in the anthropic-version header.
+const AnthropicVersion = "2023-06-01"
+
+// DefaultMessagesMaxTokens is the max_tokens sent when MaxOutputTokens is not set
+// (max_tokens is required by the Messages API).
+var DefaultMessagesMaxTokens = 4096
+
+// messagesThinkingBudgets maps reasoning efforts to extended thinking budgets (tokens).
+var messagesThinkingBudgets = map[ReasoningEffort]int{
+	ReasoningEffortMinimal: 1024,
+	ReasoningEffortLow:     2048,
+	ReasoningEffortMedium:  8192,
+	ReasoningEffortHigh:    24576,
+}
+
+// MessagesRequest is a streaming request to the Anthropic Messages API (/messages).
+//
+// It embeds a ResponsesRequest: Input, Instructions, sampling, Reasoning, listeners, observers
+// and the function tool delegate are shared. Anthropic events are translated into the
+// StreamEvent types (output_text, function_call_arguments, reasoning_text, output_item, ...),
+// so listeners written for the Responses API work unchanged.
+type MessagesRequest struct {
+	*ResponsesRequest
+
+	// StopSequences stops the generation on custom sequences.
+	StopSequences []string
+
+	// TopK only samples from the K most likely tokens (ignored with extended thinking).
+	TopK *int
+
+	stream messagesStream
+}
+
+// NewMessagesRequest returns a streaming Messages API request.
+func NewMessagesRequest(ctx context.Context, model models.Model) *MessagesRequest {
+	r := NewResponsesRequest(ctx, model)
+	// Nothing is stored server side: follow-up turns replay the conversation (see ContinueFrom).
+	r.Store = BoolPtr(false)
+	return &MessagesRequest{ResponsesRequest: r}
+}
+
+func (m *MessagesRequest) URL(baseURL string) (string, error) {
+	if strings.TrimSpace(baseURL) == "" {
+		return "", errors.New("textualopenai: missing Anthropic base URL")
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("textualopenai: invalid base URL: %w", err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/messages"
+	return u.String(), nil
+}
+
+// SetHeaders sets the Anthropic authentication and version headers (see HeaderSetter).
+func (m *MessagesRequest) SetHeaders(h http.Header, apiKey string) {
+	if strings.TrimSpace(apiKey) != "" {
+		h.Set("x-api-key", apiKey)
+	}
+	h.Set("anthropic-version", AnthropicVersion)
+}
+
+func (m *MessagesRequest) Validate() error {
+	if !m.Stream {
+		return errors.New("textualopenai: streaming must be enabled")
+	}
+	if m.Input == nil {
+		return errors.New("textualopenai: input is required")
+	}
+	if strings.TrimSpace(m.PreviousResponseID) != "" || m.conversationProvided() || m.Background || m.Prompt != nil {
+		return errors.New("textualopenai: the Messages API is stateless (previous_response_id, conversation, background and prompt are not supported)")
+	}
+	if len(m.CustomTools()) > 0 {
+		return errors.New("textualopenai: custom tools are not supported by the Messages API")
+	}
+	if err := m.validateHostedTools(); err != nil {
+		return err
+	}
+	if err := m.validateContentParts(); err != nil {
+		return err
+	}
+	_, _, err := messagesFromInput(m.Input)
+	return err
+}
+
+// MarshalJSON serializes the Messages API body.
+//
+// Instructions and system/developer input items become the system prompt; the input is
+// redacted when a Redactor is attached. Extended thinking (Reasoning or Thinking) is
+// incompatible with temperature and top_k: they are dropped when it is enabled.
+func (m *MessagesRequest) MarshalJSON() ([]byte, error) {
+	system, messages, err := messagesFromInput(m.Input)
+	if err != nil {
+		return nil, err
+	}
+	if s := strings.TrimSpace(m.Instructions); s != "" {
+		system = append([]string{s}, system...)
+	}
+
+	body := messagesBody{
+		Model:         string(m.Model),
+		MaxTokens:     m.MaxOutputTokens,
+		System:        strings.Join(system, "\n\n"),
+		Messages:      messages,
+		Tools:         m.messagesTools(),
+		ToolChoice:    m.messagesToolChoice(),
+		Temperature:   m.Temperature,
+		TopP:          m.TopP,
+		TopK:          m.TopK,
+		StopSequences: m.StopSequences,
+		Stream:        m.Stream,
+	}
+	if body.MaxTokens <= 0 {
+		body.MaxTokens = DefaultMessagesMaxTokens
+	}
+	if user := firstNonBlank(m.SafetyIdentifier, m.User); user != "" {
+		body.Metadata = &messagesMetadata{UserID: user}
+	}
+	if budget := m.thinkingBudget(); budget > 0 {
+		body.Thinking = &messagesThinking{Type: "enabled", BudgetTokens: budget}
+		if body.MaxTokens <= budget {
+			body.MaxTokens = budget + DefaultMessagesMaxTokens
+		}
+		body.Temperature = nil
+		body.TopK = nil
+	}
+
+	if red := m.Redactor(); red != nil {
+		redacted, err := red.RedactJSON(messages, messagesPreservedKeys...)
+		if err != nil {
+			return nil, fmt.Errorf("textualopenai: redact input: %w", err)
+		}
+		body.Messages = redacted
+	}
+	return json.Marshal(body)
+}
+
+// thinkingBudget returns the extended thinking budget (0 when thinking is disabled).
+func (m *MessagesRequest) thinkingBudget() int {
+	cfg := m.Reasoning
+	if cfg == nil && m.Thinking {
+		cfg = &ReasoningConfig{Effort: ReasoningEffortMedium}
+	}
+	if cfg == nil {
+		return 0
+	}
+	if budget, ok := messagesThinkingBudgets[cfg.Effort]; ok {
+		return budget
+	}
+	return messagesThinkingBudgets[ReasoningEffortMedium]
+}
+
+// messagesTools converts the function tools to Messages API tools (strict is not supported).
+func (m *MessagesRequest) messagesTools() []messagesTool {
+	var tools []messagesTool
+	for _, ft := range m.effectiveFunctionTools() {
+		tools = append(tools, messagesTool{Name: ft.Name, Description: ft.Description, InputSchema: ft.Parameters})
+	}
+	return tools
+}
+
+// messagesToolChoice maps ToolChoice and ParallelToolCalls to the Messages API tool_choice.
+func (m *MessagesRequest) messagesToolChoice() map[string]any {
+	var choice map[string]any
+	switch v := m.ToolChoice.(type) {
+	case string:
+		switch v {
+		case "auto":
+			choice = map[string]any{"type": "auto"}
+		case "required":
+			choice = map[string]any{"type": "any"}
+		case "none":
+			choice = map[string]any{"type": "none"}
+		}
+	case map[string]any:
+		if name, _ := v["name"].(string); name != "" {
+			choice = map[string]any{"type": "tool", "name": name}
+		}
+	}
+	if m.ParallelToolCalls != nil && !*m.ParallelToolCalls {
+		if choice == nil {
+			choice = map[string]any{"type": "auto"}
+		}
+		if choice["type"] != "none" {
+			choice["disable_parallel_tool_use"] = true
+		}
+	}
+	return choice
+}
+
+// TranscodeStream applies Transcoder to body (see StreamingRequest).
+func (m *MessagesRequest) TranscodeStream(ctx context.Context, body io.Reader) <-chan textual.StringCarrier {
+	m.stream.reset()
+	return transcodeTranslated(ctx, m.ResponsesRequest, body, m.Transcoder())
+}
+
+// Transcoder translates the Anthropic events into StreamEvents (see translatingTranscoder).
+func (m *MessagesRequest) Transcoder() textual.TranscoderFunc[textual.JsonGenericCarrier[MessagesEvent], textual.StringCarrier] {
+	return translatingTranscoder(m.ResponsesRequest, func(ev MessagesEvent) []StreamEvent {
+		return m.stream.translate(string(m.Model), ev)
+	})
+}
+
+// ─────────────────────────────────────────────────────────────
+// Request body
+// ─────────────────────────────────────────────────────────────
+
+type messagesBody struct {
+	Model         string            `json:"model"`
+	MaxTokens     int               `json:"max_tokens"`
+	System        string            `json:"system,omitempty"`
+	Messages      any               `json:"messages"`
+	Tools         []messagesTool    `json:"tools,omitempty"`
+	ToolChoice    map[string]any    `json:"tool_choice,omitempty"`
+	Temperature   *float64          `json:"temperature,omitempty"`
+	TopP          *float64          `json:"top_p,omitempty"`
+	TopK          *int              `json:"top_k,omitempty"`
+	StopSequences []string          `json:"stop_sequences,omitempty"`
+	Thinking      *messagesThinking `json:"thinking,omitempty"`
+	Metadata      *messagesMetadata `json:"metadata,omitempty"`
+	Stream        bool              `json:"stream"`
+}
+
+type messagesTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema any    `json:"input_schema"`
+}
+
+type messagesThinking struct {
+	Type         string `json:"type"` // "enabled"
+	BudgetTokens int    `json:"budget_tokens"`
+}
+
+type messagesMetadata struct {
+	UserID string `json:"user_id"`
+}
+
+// messagesPreservedKeys are the keys of the Messages wire format kept by redaction.
+var messagesPreservedKeys = []string{"tool_use_id", "media_type", "signature", "url"}
+
+type messagesMessage struct {
+	Role    string           `json:"role"` // "user" or "assistant"
+	Content []map[string]any `json:"content"`
+}
+
+// messagesFromInput converts the request input (string, InputItems, content parts,
+// tool calls, tool outputs and reasoning items) to the system prompt and the messages.
+// Consecutive items of the same role are merged into a single message.
+func messagesFromInput(input any) (system []string, messages []messagesMessage, err error) {
+	add := func(role string, blocks ...map[string]any) {
+		if len(blocks) == 0 {
+			return
+		}
+		if n := len(messages); n > 0 && messages[n-1].Role == role {
+			messages[n-1].Content = append(messages[n-1].Content, blocks...)
+			return
+		}
+		messages = append(messages, messagesMessage{Role: role, Content: blocks})
+	}
+	err = walkInput(input, inputVisitor{
+		api: "Messages",
+		message: func(role string, content any) error {
+			blocks, err := messagesContentBlocks(content)
+			if err != nil {
+				return err
+			}
+			switch role {
+			case "system", "developer":
+				for _, b := range blocks {
+					if text, _ := b["text"].(string); text != "" {
+						system = append(system, text)
+					}
+				}
+			case "assistant":
+				add("assistant", blocks...)
+			default:
+				add("user", blocks...)
+			}
+			return nil
+		},
+		functionCall: func(callID, name string, args json.RawMessage) error {
+			add("assistant", map[string]any{"type": "tool_use", "id": callID, "name": name, "input": args})
+			return nil
+		},
+		functionOutput: func(callID, output string) error {
+			add("user", map[string]any{"type": "tool_result", "tool_use_id": callID, "content": output})
+			return nil
+		},
+		reasoning: func(item ReasoningItem) error {
+			if block := thinkingBlock(item); block != nil {
+				add("assistant", block)
+			}
+			return nil
+		},
+	})
+	return system, messages, err
+}
+
+// thinkingBlock converts a reasoning item to a thinking block (nil without encrypted content:
+// the Messages API requires the signature of the thinking it gets back).
+func thinkingBlock(item ReasoningItem) map[string]any {
+	if item.EncryptedContent == "" {
+		return nil
+	}
+	if len(item.Summary) == 0 {
+		return map[string]any{"type": "redacted_thinking", "data": item.EncryptedContent}
+	}
+	var b strings.Builder
+	for _, s := range item.Summary {
+		b.WriteString(s.Text)
+	}
+	return map[string]any{"type": "thinking", "thinking": b.String(), "signature": item.EncryptedContent}
+}
+
+// messagesContentBlocks converts a message content to Messages API content blocks.
+func messagesContentBlocks(content any) ([]map[string]any, error) {
+	var parts []ContentPart
+	switch v := content.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return []map[string]any{{"type": "text", "text": v}}, nil
+	case ContentPart:
+		parts = []ContentPart{v}
+	case []ContentPart:
+		parts = v
+	case []any:
+		for _, e := range v {
+			switch p := e.(type) {
+			case ContentPart:
+				parts = append(parts, p)
+			case string:
+				parts = append(parts, Text(p))
+			default:
+				var part ContentPart
+				if err := remarshal(p, &part); err != nil {
+					return nil, fmt.Errorf("textualopenai: unsupported content part %T: %w", e, err)
+				}
+				parts = append(parts, part)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("textualopenai: unsupported message content %T", content)
+	}
+
+	blocks := make([]map[string]any, 0, len(parts))
+	for _, p := range parts {
+		block, err := messagesContentBlock(p)
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
+
+// messagesContentBlock converts a content part to a text, image or document block.
+func messagesContentBlock(p ContentPart) (map[string]any, error) {
+	switch p.Type {
+	case InputTextType, "output_text", "text":
+		if p.Text == "" {
+			return nil, nil
+		}
+		return map[string]any{"type": "text", "text": p.Text}, nil
+
+	case InputImageType:
+		source, err := messagesSource(p.ImageURL, p.FileID, "")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "image", "source": source}, nil
+
+	case InputFileType:
+		ref := p.FileData
+		if ref == "" {
+			ref = p.FileURL
+		}
+		source, err := messagesSource(ref, p.FileID, p.Filename)
+		if err != nil {
+			return nil, err
+		}
+		block := map[string]any{"type": "document", "source": source}
+		if p.Filename != "" {
+			block["title"] = p.Filename
+		}
+		return block, nil
+
+	default:
+		return nil, fmt.Errorf("textualopenai: content part type %q is not supported by the Messages API", p.Type)
+	}
+}
+
+// messagesSource converts a data URL, a URL or a file id to a Messages API source.
+// Inlined text documents are sent as plain text sources.
+func messagesSource(ref, fileID, filename string) (map[string]any, error) {
+	if fileID != "" {
+		return map[string]any{"type": "file", "file_id": fileID}, nil
+	}
+	if mediaType, data, ok := strings.Cut(strings.TrimPrefix(ref, "data:"), ";base64,"); ok && strings.HasPrefix(ref, "data:") {
+		if strings.HasPrefix(mediaType, "text/") || mediaType == "application/json" {
+			text, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return nil, fmt.Errorf("textualopenai: invalid data URL for %s: %w", filename, err)
+			}
+			return map[string]any{"type": "text", "media_type": "text/plain", "data": string(text)}, nil
+		}
+		return map[string]any{"type": "base64", "media_type": mediaType, "data": data}, nil
+	}
+	if ref != "" {
+		return map[string]any{"type": "url", "url": ref}, nil
+	}
+	return nil, errors.New("textualopenai: content part has no data, URL or file id")
+}
+
+func firstNonBlank(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ─────────────────────────────────────────────────────────────
+// Stream translation
+// ─────────────────────────────────────────────────────────────
+
+// MessagesEvent is a Messages API stream event.
+type MessagesEvent struct {
+	Type         string                `json:"type"` // message_start, content_block_start, content_block_delta, ...
+	Index        int                   `json:"index"`
+	Message      *MessagesEventMessage `json:"message,omitempty"`
+	ContentBlock *MessagesContentBlock `json:"content_block,omitempty"`
+	Delta        *MessagesDelta        `json:"delta,omitempty"`
+	Usage        json.RawMessage       `json:"usage,omitempty"`
+	Error        *MessagesError        `json:"error,omitempty"`
+}
+
+// MessagesEventMessage is the message of a message_start event.
+type MessagesEventMessage struct {
+	ID    string          `json:"id"`
+	Model string          `json:"model,omitempty"`
+	Usage json.RawMessage `json:"usage,omitempty"`
+}
+
+// MessagesContentBlock is the block of a content_block_start event.
+type MessagesContentBlock struct {
+	Type      string          `json:"type"` // text, tool_use, thinking, redacted_thinking
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	Text      string          `json:"text,omitempty"`
+	Thinking  string          `json:"thinking,omitempty"`
+	Signature string          `json:"signature,omitempty"`
+	Data      string          `json:"data,omitempty"`
+}
+
+// MessagesDelta is the delta of content_block_delta and message_delta events.
+type MessagesDelta struct {
+	Type        string `json:"type,omitempty"` // text_delta, input_json_delta, thinking_delta, signature_delta
+	Text        string `json:"text,omitempty"`
+	PartialJSON string `json:"partial_json,omitempty"`
+	Thinking    string `json:"thinking,omitempty"`
+	Signature   string `json:"signature,omitempty"`
+	StopReason  string `json:"stop_reason,omitempty"`
+}
+
+// MessagesError is the payload of an error event.
+type MessagesError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// messagesStream holds the state of the translation of a stream.
+// Output indexes are the content block indexes.
+type messagesStream struct {
+	mu sync.Mutex
+	translatedStream
+	blocks     map[int]*messagesStreamBlock
+	stopReason string
+	usage      json.RawMessage
+}
+
+type messagesStreamBlock struct {
+	Type      string
+	ItemID    string
+	Name      string
+	content   strings.Builder // text, thinking or tool input
+	signature string
+}
+
+// reset prepares the translation of a new stream.
+func (s *messagesStream) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.translatedStream.reset()
+	s.blocks, s.stopReason, s.usage = nil, "", nil
+}
+
+// mergeMessagesUsage overlays the cumulative counts of a message_delta usage on the
+// message_start usage: input and cache counts are usually only sent by message_start,
+// so zero or missing fields keep their previous value.
+func mergeMessagesUsage(base, update json.RawMessage) json.RawMessage {
+	var merged, delta map[string]any
+	if json.Unmarshal(base, &merged) != nil || merged == nil {
+		return update
+	}
+	if json.Unmarshal(update, &delta) != nil {
+		return base
+	}
+	for k, v := range delta {
+		if v == nil || v == float64(0) {
+			if _, ok := merged[k]; ok {
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	b, err := json.Marshal(merged)
+	if err != nil {
+		return base
+	}
+	return b
+}
+
+// translate converts an Anthropic event to zero or more StreamEvents.
+func (s *messagesStream) translate(model string, ev MessagesEvent) []StreamEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.blocks == nil {
+		s.blocks = map[int]*messagesStreamBlock{}
+	}
+	response := func(status ResponseStatus) json.RawMessage {
+		return s.response(model, status, map[string]any{"stop_reason": s.stopReason, "usage": s.usage})
+	}
+
+	switch ev.Type {
+	case "message_start":
+		if ev.Message != nil {
+			s.responseID = ev.Message.ID
+			s.usage = ev.Message.Usage
+		}
+		s.push(StreamEvent{Type: ResponseCreated, Response: response(ResponseStatusInProgress)})
+
+	case "content_block_start":
+		cb := ev.ContentBlock
+		if cb == nil {
+			return nil
+		}
+		block := &messagesStreamBlock{Type: cb.Type, ItemID: fmt.Sprintf("%s_%d", s.responseID, ev.Index), Name: cb.Name}
+		s.blocks[ev.Index] = block
+		switch cb.Type {
+		case "text":
+			s.messageAdded(ev.Index, block.ItemID)
+			if cb.Text != "" {
+				block.content.WriteString(cb.Text)
+				s.push(StreamEvent{Type: OutputTextDelta, OutputIndex: ev.Index, ItemID: block.ItemID, Delta: cb.Text})
+			}
+		case "tool_use":
+			block.ItemID = cb.ID
+			s.functionCallAdded(ev.Index, cb.ID, cb.Name)
+		case "thinking", "redacted_thinking":
+			block.content.WriteString(cb.Thinking)
+			block.signature = cb.Signature + cb.Data
+			s.reasoningAdded(ev.Index, block.ItemID)
+		}
+
+	case "content_block_delta":
+		block, d := s.blocks[ev.Index], ev.Delta
+		if block == nil || d == nil {
+			return nil
+		}
+		switch d.Type {
+		case "text_delta":
+			block.content.WriteString(d.Text)
+			s.push(StreamEvent{Type: OutputTextDelta, OutputIndex: ev.Index, ItemID: block.ItemID, Delta: d.Text})
+		case "input_json_delta":
+			if d.PartialJSON == "" {
+				return nil
+			}
+			block.content.WriteString(d.PartialJSON)
+			s.push(StreamEvent{Type: FunctionCallArgumentsDelta, OutputIndex: ev.Index, ItemID: block.ItemID, Delta: d.PartialJSON})
+		case "thinking_delta":
+			block.content.WriteString(d.Thinking)
+			s.push(StreamEvent{Type: ReasoningTextDelta, OutputIndex: ev.Index, ItemID: block.ItemID, Delta: d.Thinking})
+		case "signature_delta":
+			block.signature += d.Signature
+		}
+
+	case "content_block_stop":
+		block := s.blocks[ev.Index]
+		if block == nil {
+			return nil
+		}
+		content := block.content.String()
+		switch block.Type {
+		case "text":
+			s.messageDone(ev.Index, block.ItemID, content)
+		case "tool_use":
+			if strings.TrimSpace(content) == "" {
+				content = "{}"
+			}
+			s.functionCallDone(ev.Index, block.ItemID, block.Name, content)
+		case "thinking", "redacted_thinking":
+			s.reasoningDone(ev.Index, block.ItemID, content, block.signature)
+		}
+		delete(s.blocks, ev.Index)
+
+	case "message_delta":
+		if ev.Delta != nil && ev.Delta.StopReason != "" {
+			s.stopReason = ev.Delta.StopReason
+		}
+		if len(ev.Usage) > 0 {
+			s.usage = mergeMessagesUsage(s.usage, ev.Usage)
+		}
+
+	case "message_stop":
+		if s.stopReason == "max_tokens" {
+			s.push(StreamEvent{Type: ResponseIncomplete, Response: response(ResponseStatusIncomplete)})
+		} else {
+			s.push(StreamEvent{Type: ResponseCompleted, Response: response(ResponseStatusCompleted)})
+		}
+
+	case "error":
+		e := StreamEvent{Type: Error, Message: "unknown error"}
+		if ev.Error != nil {
+			e.Code, e.Message = ev.Error.Type, ev.Error.Message
+		}
+		s.push(e)
+	}
+	return s.flush()
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+)
+
+const messagesSSE = `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","model":"claude-sonnet-4-5","usage":{"input_tokens":25,"cache_read_input_tokens":10,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"thinking","thinking":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"thinking_delta","thinking":"Let me check."}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"signature_delta","signature":"sig"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: content_block_start
+data: {"type":"content_block_start","index":1,"content_block":{"type":"text","text":""}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":"Hello"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":1,"delta":{"type":"text_delta","text":" world"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":1}
+
+event: content_block_start
+data: {"type":"content_block_start","index":2,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_weather","input":{}}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":2,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":2}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":42}}
+
+event: message_stop
+data: {"type":"message_stop"}
+
+`
+
+func TestMessagesRequestStreamTranslation(t *testing.T) {
+	var gotPath, gotVersion string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotVersion = r.URL.Path, r.Header.Get("anthropic-version")
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(messagesSSE))
+	}))
+	defer srv.Close()
+
+	model := "anthropic:claude-sonnet-4-5"
+	c := testClient(t, srv.URL, model)
+	req := NewMessagesRequest(context.Background(), testModel(t, model))
+	req.Input = "What's the weather in Paris?"
+
+	var mu sync.Mutex
+	var types []string
+	var reasoning, arguments string
+	var completed json.RawMessage
+	_ = req.AddObservers(func(e textual.JsonGenericCarrier[StreamEvent]) {
+		mu.Lock()
+		defer mu.Unlock()
+		types = append(types, string(e.Value.Type))
+		switch e.Value.Type {
+		case ReasoningTextDone:
+			reasoning = e.Value.Text
+		case FunctionCallArgumentsDone:
+			arguments = e.Value.Arguments
+		case ResponseCompleted:
+			completed = e.Value.Response
+		}
+	}, AllEvent)
+	_ = req.AddListeners(StringCarrierFrom, OutputTextDelta)
+
+	text, _, err := c.StreamAndTranscodeResponses(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/messages" || gotVersion != AnthropicVersion {
+		t.Fatalf("request = %s (anthropic-version %q)", gotPath, gotVersion)
+	}
+	if text != "Hello world" {
+		t.Fatalf("text = %q", text)
+	}
+	if reasoning != "Let me check." {
+		t.Fatalf("reasoning = %q", reasoning)
+	}
+	if arguments != `{"city":"Paris"}` {
+		t.Fatalf("arguments = %q", arguments)
+	}
+	if types[0] != string(ResponseCreated) || types[len(types)-1] != string(ResponseCompleted) {
+		t.Fatalf("events = %v", types)
+	}
+
+	var res struct {
+		ID         string          `json:"id"`
+		StopReason string          `json:"stop_reason"`
+		Usage      json.RawMessage `json:"usage"`
+	}
+	if err := json.Unmarshal(completed, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != "msg_1" || res.StopReason != "tool_use" {
+		t.Fatalf("response = %s", completed)
+	}
+	// message_delta only carries output_tokens: input and cache counts come from message_start.
+	u, ok := ParseUsage(res.Usage)
+	if !ok || u.InputTokens != 35 || u.CachedTokens != 10 || u.OutputTokens != 42 {
+		t.Fatalf("usage = %+v (%s)", u, res.Usage)
+	}
+}
+
+func TestMergeMessagesUsage(t *testing.T) {
+	tests := []struct {
+		base, update, want string
+	}{
+		{`{"input_tokens":25,"output_tokens":1}`, `{"output_tokens":42}`, `{"input_tokens":25,"output_tokens":42}`},
+		{`{"input_tokens":25,"output_tokens":1}`, `{"input_tokens":0,"output_tokens":42}`, `{"input_tokens":25,"output_tokens":42}`},
+		{`{"input_tokens":25}`, `{"input_tokens":30,"output_tokens":42}`, `{"input_tokens":30,"output_tokens":42}`},
+		{``, `{"output_tokens":42}`, `{"output_tokens":42}`},
+	}
+	for _, tt := range tests {
+		got := mergeMessagesUsage(json.RawMessage(tt.base), json.RawMessage(tt.update))
+		if string(got) != tt.want {
+			t.Errorf("mergeMessagesUsage(%s, %s) = %s, want %s", tt.base, tt.update, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Usage sample :
+// client = client.WithMiddleware(
+// 	textualopenai.LogRequests(log.Printf),
+// 	func(next textualopenai.Handler) textualopenai.Handler {
+// 		return func(req *http.Request) (*http.Response, error) {
+// 			req.Header.Set("X-Request-ID", uuid.NewString())
+// 			return next(req)
+// 		}
+// 	},
+// 	// Observe (or rewrite) the streamed events before the transcoder.
+// 	textualopenai.StreamFrames(func(req *http.Request, line []byte) []byte {
+// 		if bytes.HasPrefix(line, []byte("data: ")) {
+// 			metrics.Events.Inc()
+// 		}
+// 		return line
+// 	}),
+// )
+
+// Handler sends a request and returns its response.
+// The innermost Handler authenticates the request (see Authenticator) and sends it with the HTTP client.
+type Handler func(req *http.Request) (*http.Response, error)
+
+// Middleware wraps a Handler: it may inspect or modify the request, short-circuit it
+// (caching, fault injection, ...), or inspect or wrap the response, including its
+// streamed body (see StreamFrames).
+//
+// Middlewares see every exchange of the client: streams, resumed streams and JSON endpoints.
+// Requests retried after a 401 (see Invalidator) go through the middlewares again.
+type Middleware func(next Handler) Handler
+
+// Chain composes middlewares: the first one is the outermost.
+func Chain(mws ...Middleware) Middleware {
+	return func(next Handler) Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			if mws[i] != nil {
+				next = mws[i](next)
+			}
+		}
+		return next
+	}
+}
+
+// WithMiddleware appends middlewares to the client chain.
+// The first middleware added is the outermost.
+func (c Client) WithMiddleware(mws ...Middleware) Client {
+	merged := make([]Middleware, 0, len(c.middlewares)+len(mws))
+	merged = append(merged, c.middlewares...)
+	c.middlewares = append(merged, mws...)
+	return c
+}
+
+// handler returns the client Handler: the middlewares wrapping the authentication and the HTTP client.
+func (c Client) handler() Handler {
+	send := func(req *http.Request) (*http.Response, error) {
+		if c.auth != nil {
+			if err := c.auth.Authenticate(req); err != nil {
+				return nil, &authError{err: err}
+			}
+		}
+		return c.httpClient.Do(req)
+	}
+	return Chain(c.middlewares...)(send)
+}
+
+// authError distinguishes the authentication failures from the transport errors.
+type authError struct {
+	err error
+}
+
+func (e *authError) Error() string { return e.err.Error() }
+func (e *authError) Unwrap() error { return e.err }
+
+// LogRequests logs every exchange with logf (e.g. log.Printf): method, URL without
+// its query, status and the time to the response headers.
+func LogRequests(logf func(format string, args ...any)) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			u := *req.URL
+			u.RawQuery = ""
+			if err != nil {
+				logf("textualopenai: %s %s: %v (%s)", req.Method, u.String(), err, time.Since(start).Round(time.Millisecond))
+				return resp, err
+			}
+			logf("textualopenai: %s %s: %s (%s)", req.Method, u.String(), resp.Status, time.Since(start).Round(time.Millisecond))
+			return resp, nil
+		}
+	}
+}
+
+// StreamFrames calls fn for each line of the streamed response bodies (requests accepting
+// text/event-stream), before the transcoder: SSE fields ("event: ...", "data: {...}") or
+// NDJSON objects (native Ollama). fn returns the line to forward, or nil to drop it.
+// Empty lines (SSE event separators) are forwarded as-is.
+func StreamFrames(fn func(req *http.Request, line []byte) []byte) Middleware {
+	return func(next Handler) Handler {
+		return func(req *http.Request) (*http.Response, error) {
+			resp, err := next(req)
+			if err != nil || resp == nil || resp.Body == nil || fn == nil {
+				return resp, err
+			}
+			if !strings.Contains(req.Header.Get("Accept"), "text/event-stream") {
+				return resp, nil
+			}
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				return resp, nil
+			}
+			resp.Body = &frameReader{req: req, body: resp.Body, src: bufio.NewReader(resp.Body), fn: fn}
+			return resp, nil
+		}
+	}
+}
+
+// frameReader applies a StreamFrames function line by line.
+type frameReader struct {
+	req  *http.Request
+	body io.Closer
+	src  *bufio.Reader
+	fn   func(req *http.Request, line []byte) []byte
+	buf  bytes.Buffer
+	err  error
+}
+
+func (f *frameReader) Read(p []byte) (int, error) {
+	for f.buf.Len() == 0 {
+		if f.err != nil {
+			return 0, f.err
+		}
+		line, err := f.src.ReadBytes('\n')
+		f.err = err
+		if len(line) == 0 {
+			continue
+		}
+		trimmed := bytes.TrimRight(line, "\r\n")
+		if len(trimmed) == 0 {
+			f.buf.WriteByte('\n')
+			continue
+		}
+		if out := f.fn(f.req, trimmed); out != nil {
+			f.buf.Write(out)
+			f.buf.WriteByte('\n')
+		}
+	}
+	return f.buf.Read(p)
+}
+
+func (f *frameReader) Close() error {
+	return f.body.Close()
+}
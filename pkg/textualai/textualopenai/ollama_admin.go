@@ -0,0 +1,326 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// Usage sample :
+// admin := client.Ollama()
+// progress, err := admin.Pull(ctx, "qwen3:32b")
+// if err != nil { ... }
+// for p := range progress {
+// 	if p.Err != nil { log.Fatal(p.Err) }
+// 	fmt.Printf("\r%s %3.0f%%", p.Status, p.Percent())
+// }
+// info, err := admin.Show(ctx, "qwen3:32b")
+// running, err := admin.Running(ctx)
+// _ = admin.Copy(ctx, "qwen3:32b", "my-qwen")
+// _ = admin.Delete(ctx, "my-qwen")
+//
+// // Resolving a model pulls it when it is missing.
+// models.RegisterEnsurePresent(models.ProviderOllama, admin.EnsurePresentFunc(nil))
+// model, err := models.ModelFromStringPresent(ctx, "ollama:qwen3:32b")
+
+var (
+	// ErrOllamaAdminUnsupported is returned when the client model is not an Ollama model.
+	ErrOllamaAdminUnsupported = errors.New("textualopenai: the Ollama admin API requires an ollama model")
+
+	// ErrOllamaModelNotFound is returned when a model is not present locally.
+	ErrOllamaModelNotFound = errors.New("textualopenai: ollama model not found")
+)
+
+// OllamaProgress is a status event of a streamed pull or create operation.
+// The last event of a successful operation has the "success" status; a failed
+// operation ends with an event carrying Err.
+type OllamaProgress struct {
+	Status    string `json:"status"` // "pulling manifest", "pulling <digest>", "success", ...
+	Digest    string `json:"digest,omitempty"`
+	Total     int64  `json:"total,omitempty"`     // bytes of the layer being downloaded
+	Completed int64  `json:"completed,omitempty"` // bytes downloaded
+	Err       error  `json:"-"`
+}
+
+// Percent returns the download progress of the current layer (0 when unknown).
+func (p OllamaProgress) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return float64(p.Completed) * 100 / float64(p.Total)
+}
+
+// OllamaModelDetails describes the format and size of a model.
+type OllamaModelDetails struct {
+	ParentModel       string   `json:"parent_model,omitempty"`
+	Format            string   `json:"format,omitempty"` // "gguf"
+	Family            string   `json:"family,omitempty"`
+	Families          []string `json:"families,omitempty"`
+	ParameterSize     string   `json:"parameter_size,omitempty"`     // "32.8B"
+	QuantizationLevel string   `json:"quantization_level,omitempty"` // "Q4_K_M"
+}
+
+// OllamaLocalModel is a model present locally (/api/tags) or loaded in memory (/api/ps).
+type OllamaLocalModel struct {
+	Name          string             `json:"name"`
+	Model         string             `json:"model"`
+	ModifiedAt    time.Time          `json:"modified_at,omitempty"`
+	Size          int64              `json:"size"`
+	Digest        string             `json:"digest"`
+	Details       OllamaModelDetails `json:"details"`
+	ExpiresAt     time.Time          `json:"expires_at,omitempty"`     // running models
+	SizeVRAM      int64              `json:"size_vram,omitempty"`      // running models
+	ContextLength int                `json:"context_length,omitempty"` // running models
+}
+
+// OllamaModelInfo is the description of a model (/api/show).
+type OllamaModelInfo struct {
+	Modelfile    string             `json:"modelfile,omitempty"`
+	Parameters   string             `json:"parameters,omitempty"`
+	Template     string             `json:"template,omitempty"`
+	System       string             `json:"system,omitempty"`
+	License      string             `json:"license,omitempty"`
+	Details      OllamaModelDetails `json:"details"`
+	ModelInfo    map[string]any     `json:"model_info,omitempty"`   // architecture metadata
+	Capabilities []string           `json:"capabilities,omitempty"` // "completion", "tools", "thinking", "vision", ...
+	ModifiedAt   time.Time          `json:"modified_at,omitempty"`
+}
+
+// OllamaCreateOptions describes a model to create (/api/create).
+type OllamaCreateOptions struct {
+	Model      string            `json:"model"`                // name of the new model
+	From       string            `json:"from,omitempty"`       // existing model to derive from
+	Files      map[string]string `json:"files,omitempty"`      // file name -> blob digest (GGUF, safetensors)
+	Adapters   map[string]string `json:"adapters,omitempty"`   // file name -> blob digest (LoRA)
+	Template   string            `json:"template,omitempty"`   // prompt template
+	License    any               `json:"license,omitempty"`    // string or []string
+	System     string            `json:"system,omitempty"`     // system prompt
+	Parameters map[string]any    `json:"parameters,omitempty"` // Modelfile parameters
+	Messages   []OllamaMessage   `json:"messages,omitempty"`   // conversation preamble
+	Quantize   string            `json:"quantize,omitempty"`   // e.g. "q4_K_M"
+}
+
+// OllamaAdmin is the Ollama model management sub-client (/api/pull, /api/show, ...).
+type OllamaAdmin struct {
+	client Client
+}
+
+// Ollama returns the Ollama model management sub-client.
+// The /v1 suffix of the OpenAI-compatible base URL is removed.
+func (c Client) Ollama() OllamaAdmin {
+	c.baseURL = ollamaRootURL(c.baseURL)
+	return OllamaAdmin{client: c}
+}
+
+// ollamaRootURL returns the Ollama server URL of an OpenAI-compatible base URL.
+func ollamaRootURL(baseURL string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(baseURL), "/"), "/v1")
+}
+
+func (a OllamaAdmin) check() error {
+	if a.client.model.ProviderName != models.ProviderOllama {
+		return ErrOllamaAdminUnsupported
+	}
+	return nil
+}
+
+// List returns the models present locally (GET /api/tags).
+func (a OllamaAdmin) List(ctx context.Context) ([]OllamaLocalModel, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	var res struct {
+		Models []OllamaLocalModel `json:"models"`
+	}
+	err := a.client.doJSON(ctx, http.MethodGet, "api/tags", nil, nil, &res)
+	return res.Models, err
+}
+
+// Running returns the models loaded in memory (GET /api/ps).
+func (a OllamaAdmin) Running(ctx context.Context) ([]OllamaLocalModel, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	var res struct {
+		Models []OllamaLocalModel `json:"models"`
+	}
+	err := a.client.doJSON(ctx, http.MethodGet, "api/ps", nil, nil, &res)
+	return res.Models, err
+}
+
+// Show describes a model (POST /api/show). It returns ErrOllamaModelNotFound when the model is missing.
+func (a OllamaAdmin) Show(ctx context.Context, model string) (OllamaModelInfo, error) {
+	var info OllamaModelInfo
+	if err := a.check(); err != nil {
+		return info, err
+	}
+	err := a.client.doJSON(ctx, http.MethodPost, "api/show", nil, map[string]string{"model": model}, &info)
+	return info, notFound(err, model)
+}
+
+// Delete removes a model (DELETE /api/delete). It returns ErrOllamaModelNotFound when the model is missing.
+func (a OllamaAdmin) Delete(ctx context.Context, model string) error {
+	if err := a.check(); err != nil {
+		return err
+	}
+	err := a.client.doJSON(ctx, http.MethodDelete, "api/delete", nil, map[string]string{"model": model}, nil)
+	return notFound(err, model)
+}
+
+// Copy copies a model under a new name (POST /api/copy).
+func (a OllamaAdmin) Copy(ctx context.Context, source, destination string) error {
+	if err := a.check(); err != nil {
+		return err
+	}
+	body := map[string]string{"source": source, "destination": destination}
+	err := a.client.doJSON(ctx, http.MethodPost, "api/copy", nil, body, nil)
+	return notFound(err, source)
+}
+
+// Pull downloads a model (POST /api/pull) and streams its progress.
+// The channel is closed when the operation ends.
+func (a OllamaAdmin) Pull(ctx context.Context, model string) (<-chan OllamaProgress, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	return a.stream(ctx, "api/pull", map[string]any{"model": model, "stream": true})
+}
+
+// Create creates a model (POST /api/create) and streams its progress.
+// The channel is closed when the operation ends.
+func (a OllamaAdmin) Create(ctx context.Context, opts OllamaCreateOptions) (<-chan OllamaProgress, error) {
+	if err := a.check(); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(opts.Model) == "" {
+		return nil, errors.New("textualopenai: model name is required")
+	}
+	body := struct {
+		OllamaCreateOptions
+		Stream bool `json:"stream"`
+	}{OllamaCreateOptions: opts, Stream: true}
+	return a.stream(ctx, "api/create", body)
+}
+
+// EnsurePresent pulls the model when it is not present locally.
+// onProgress (optional) receives the pull progress.
+func (a OllamaAdmin) EnsurePresent(ctx context.Context, model string, onProgress func(OllamaProgress)) error {
+	_, err := a.Show(ctx, model)
+	if err == nil || !errors.Is(err, ErrOllamaModelNotFound) {
+		return err
+	}
+	progress, err := a.Pull(ctx, model)
+	if err != nil {
+		return err
+	}
+	var pullErr error
+	last := ""
+	for p := range progress {
+		if p.Err != nil {
+			pullErr = p.Err
+		}
+		last = p.Status
+		if onProgress != nil {
+			onProgress(p)
+		}
+	}
+	if pullErr == nil && last != "success" {
+		// The progress stream stops without an error when ctx is done.
+		if pullErr = ctx.Err(); pullErr == nil {
+			pullErr = fmt.Errorf("textualopenai: pull %s ended before success", model)
+		}
+	}
+	return pullErr
+}
+
+// EnsurePresentFunc adapts EnsurePresent to models.RegisterEnsurePresent.
+func (a OllamaAdmin) EnsurePresentFunc(onProgress func(OllamaProgress)) models.EnsurePresentFunc {
+	return func(ctx context.Context, m models.Model) error {
+		return a.EnsurePresent(ctx, string(m.ID), onProgress)
+	}
+}
+
+// stream sends a streamed operation and decodes its NDJSON status lines.
+func (a OllamaAdmin) stream(ctx context.Context, path string, in any) (<-chan OllamaProgress, error) {
+	resp, err := a.client.send(ctx, http.MethodPost, path, nil, in)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan OllamaProgress)
+	go func() {
+		defer close(out)
+		defer func() { _ = resp.Body.Close() }()
+
+		emit := func(p OllamaProgress) bool {
+			select {
+			case out <- p:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		last := ""
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var p struct {
+				OllamaProgress
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal([]byte(line), &p); err != nil {
+				emit(OllamaProgress{Err: fmt.Errorf("textualopenai: decode %s progress: %w", path, err)})
+				return
+			}
+			if p.Error != "" {
+				emit(OllamaProgress{Status: last, Err: fmt.Errorf("textualopenai: %s failed: %s", path, p.Error)})
+				return
+			}
+			last = p.Status
+			if !emit(p.OllamaProgress) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			emit(OllamaProgress{Status: last, Err: fmt.Errorf("textualopenai: read %s progress: %w", path, err)})
+			return
+		}
+		if last != "success" {
+			emit(OllamaProgress{Status: last, Err: fmt.Errorf("textualopenai: %s ended before success", path)})
+		}
+	}()
+	return out, nil
+}
+
+// notFound maps 404 responses to ErrOllamaModelNotFound.
+func notFound(err error, model string) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrOllamaModelNotFound, model)
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ollamaPullServer answers /api/show with 404 and streams the pull progress, one line every delay.
+func ollamaPullServer(t *testing.T, delay time.Duration, lines ...string) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/show":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"model not found"}`))
+		case "/api/pull":
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			for _, line := range lines {
+				_, _ = fmt.Fprintln(w, line)
+				w.(http.Flusher).Flush()
+				select {
+				case <-time.After(delay):
+				case <-r.Context().Done():
+					return
+				}
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestOllamaEnsurePresentPulls(t *testing.T) {
+	url := ollamaPullServer(t, 0,
+		`{"status":"pulling manifest"}`,
+		`{"status":"pulling abc","digest":"sha256:abc","total":100,"completed":50}`,
+		`{"status":"success"}`,
+	)
+	var statuses []string
+	err := testClient(t, url+"/v1", "ollama:llama3.2").Ollama().EnsurePresent(context.Background(), "llama3.2", func(p OllamaProgress) {
+		statuses = append(statuses, p.Status)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(statuses) != "[pulling manifest pulling abc success]" {
+		t.Fatalf("progress = %q", statuses)
+	}
+}
+
+func TestOllamaEnsurePresentCancelled(t *testing.T) {
+	url := ollamaPullServer(t, 100*time.Millisecond,
+		`{"status":"pulling manifest"}`,
+		`{"status":"pulling abc","digest":"sha256:abc","total":100,"completed":10}`,
+		`{"status":"pulling abc","digest":"sha256:abc","total":100,"completed":20}`,
+		`{"status":"pulling abc","digest":"sha256:abc","total":100,"completed":30}`,
+		`{"status":"success"}`,
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	err := testClient(t, url+"/v1", "ollama:llama3.2").Ollama().EnsurePresent(ctx, "llama3.2", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestOllamaEnsurePresentEndsBeforeSuccess(t *testing.T) {
+	url := ollamaPullServer(t, 0, `{"status":"pulling manifest"}`)
+	err := testClient(t, url+"/v1", "ollama:llama3.2").Ollama().EnsurePresent(context.Background(), "llama3.2", nil)
+	if err == nil {
+		t.Fatal("EnsurePresent succeeded without a success status")
+	}
+}
@@ -0,0 +1,547 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// Usage sample :
+// model, _ := models.ModelFromString("ollama:llama3.2")
+// client, _ := textualopenai.ClientFrom("", model, ctx) // http://localhost:11434/v1: /api/chat is derived
+// req := textualopenai.NewOllamaChatRequest(ctx, model)
+// req.Input = "Why is the sky blue?"
+// req.KeepAlive = "30m"
+// req.Options = textualopenai.OllamaOptions{NumCtx: textualopenai.IntPtr(8192), Seed: textualopenai.IntPtr(42)}
+// req.Format = schema // or "json"
+// _ = req.AddListeners(textualopenai.StringCarrierFrom, textualopenai.OutputTextDelta)
+// text, _, err := client.StreamAndTranscodeResponses(ctx, req)
+// if m, ok := req.Metrics(); ok {
+// 	fmt.Printf("%.1f tokens/s (load %s)\n", m.TokensPerSecond(), m.LoadDuration)
+// }
+
+// OllamaChatRequest is a streaming request to the native Ollama chat API (/api/chat).
+//
+// The OpenAI-compatible layer of Ollama hides keep_alive, options, format, think and the
+// timing statistics: this request exposes them. It embeds a ResponsesRequest (Input,
+// Instructions, sampling, Reasoning, listeners, observers and the function tool delegate
+// are shared) and translates the NDJSON chunks into StreamEvents.
+type OllamaChatRequest struct {
+	*ResponsesRequest
+
+	// KeepAlive controls how long the model stays loaded after the request
+	// (a duration string such as "5m", 0 unloads it, -1 keeps it loaded).
+	KeepAlive any
+
+	// Options are the model parameters. Temperature, TopP and MaxOutputTokens
+	// are used when the corresponding option is not set.
+	Options OllamaOptions
+
+	// Format constrains the output: "json" or a JSON schema.
+	// When nil, a json_schema or json_object Text format is used.
+	Format any
+
+	stream ollamaStream
+}
+
+// OllamaOptions are the Ollama model parameters (see the Modelfile PARAMETER documentation).
+type OllamaOptions struct {
+	NumCtx        *int     `json:"num_ctx,omitempty"`
+	NumPredict    *int     `json:"num_predict,omitempty"`
+	NumGPU        *int     `json:"num_gpu,omitempty"`
+	NumThread     *int     `json:"num_thread,omitempty"`
+	Seed          *int     `json:"seed,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopK          *int     `json:"top_k,omitempty"`
+	TopP          *float64 `json:"top_p,omitempty"`
+	MinP          *float64 `json:"min_p,omitempty"`
+	RepeatPenalty *float64 `json:"repeat_penalty,omitempty"`
+	Stop          []string `json:"stop,omitempty"`
+
+	// Extra holds the parameters without a dedicated field (e.g. "mirostat").
+	Extra map[string]any `json:"-"`
+}
+
+// OllamaMetrics are the timing statistics of the final chunk of an Ollama response.
+type OllamaMetrics struct {
+	DoneReason         string        // "stop", "length", ...
+	TotalDuration      time.Duration // whole request
+	LoadDuration       time.Duration // model load
+	PromptEvalCount    int           // prompt tokens
+	PromptEvalDuration time.Duration
+	EvalCount          int // generated tokens
+	EvalDuration       time.Duration
+}
+
+// TokensPerSecond returns the generation speed (0 when unknown).
+func (m OllamaMetrics) TokensPerSecond() float64 {
+	if m.EvalDuration <= 0 {
+		return 0
+	}
+	return float64(m.EvalCount) / m.EvalDuration.Seconds()
+}
+
+// PromptTokensPerSecond returns the prompt evaluation speed (0 when unknown).
+func (m OllamaMetrics) PromptTokensPerSecond() float64 {
+	if m.PromptEvalDuration <= 0 {
+		return 0
+	}
+	return float64(m.PromptEvalCount) / m.PromptEvalDuration.Seconds()
+}
+
+// NewOllamaChatRequest returns a streaming Ollama /api/chat request.
+func NewOllamaChatRequest(ctx context.Context, model models.Model) *OllamaChatRequest {
+	r := NewResponsesRequest(ctx, model)
+	// Nothing is stored server side: follow-up turns replay the conversation (see ContinueFrom).
+	r.Store = BoolPtr(false)
+	return &OllamaChatRequest{ResponsesRequest: r}
+}
+
+// URL returns the /api/chat endpoint. The /v1 suffix of OpenAI-compatible base URLs is removed.
+func (o *OllamaChatRequest) URL(baseURL string) (string, error) {
+	if strings.TrimSpace(baseURL) == "" {
+		return "", errors.New("textualopenai: missing Ollama base URL")
+	}
+	u, err := url.Parse(ollamaRootURL(baseURL))
+	if err != nil {
+		return "", fmt.Errorf("textualopenai: invalid base URL: %w", err)
+	}
+	u.Path += "/api/chat"
+	return u.String(), nil
+}
+
+// Metrics returns the timing statistics, available once the final chunk has been streamed.
+func (o *OllamaChatRequest) Metrics() (OllamaMetrics, bool) {
+	o.stream.mu.Lock()
+	defer o.stream.mu.Unlock()
+	if o.stream.metrics == nil {
+		return OllamaMetrics{}, false
+	}
+	return *o.stream.metrics, true
+}
+
+func (o *OllamaChatRequest) Validate() error {
+	if !o.Stream {
+		return errors.New("textualopenai: streaming must be enabled")
+	}
+	if o.Input == nil {
+		return errors.New("textualopenai: input is required")
+	}
+	if strings.TrimSpace(o.PreviousResponseID) != "" || o.conversationProvided() || o.Background || o.Prompt != nil {
+		return errors.New("textualopenai: the Ollama chat API is stateless (previous_response_id, conversation, background and prompt are not supported)")
+	}
+	if len(o.CustomTools()) > 0 {
+		return errors.New("textualopenai: custom tools are not supported by the Ollama chat API")
+	}
+	if err := o.validateHostedTools(); err != nil {
+		return err
+	}
+	if err := o.validateContentParts(); err != nil {
+		return err
+	}
+	_, err := ollamaMessagesFromInput(o.Input)
+	return err
+}
+
+// MarshalJSON serializes the /api/chat body.
+//
+// Instructions become a leading system message; the input is redacted when a Redactor
+// is attached. Reasoning (or Thinking) is sent as think (see providerReasoning).
+func (o *OllamaChatRequest) MarshalJSON() ([]byte, error) {
+	messages, err := ollamaMessagesFromInput(o.Input)
+	if err != nil {
+		return nil, err
+	}
+	if s := strings.TrimSpace(o.Instructions); s != "" {
+		messages = append([]OllamaMessage{{Role: "system", Content: s}}, messages...)
+	}
+
+	body := ollamaChatBody{
+		Model:     string(o.Model),
+		Messages:  messages,
+		Format:    o.format(),
+		Options:   o.ollamaOptions(),
+		Stream:    o.Stream,
+		KeepAlive: o.KeepAlive,
+	}
+	if choice, _ := o.ToolChoice.(string); choice != "none" {
+		for _, ft := range o.effectiveFunctionTools() {
+			body.Tools = append(body.Tools, ollamaTool{Type: "function", Function: ollamaFunction{
+				Name: ft.Name, Description: ft.Description, Parameters: ft.Parameters,
+			}})
+		}
+	}
+	_, body.Think = o.providerReasoning()
+
+	if red := o.Redactor(); red != nil {
+		redacted, err := red.RedactJSON(messages, ollamaPreservedKeys...)
+		if err != nil {
+			return nil, fmt.Errorf("textualopenai: redact input: %w", err)
+		}
+		body.Messages = redacted
+	}
+	return json.Marshal(body)
+}
+
+// format returns Format, or the schema of a json_schema Text format.
+func (o *OllamaChatRequest) format() any {
+	if o.Format != nil || o.Text == nil {
+		return o.Format
+	}
+	var text struct {
+		Format struct {
+			Type   string `json:"type"`
+			Schema any    `json:"schema"`
+		} `json:"format"`
+	}
+	if err := remarshal(o.Text, &text); err != nil {
+		return nil
+	}
+	switch text.Format.Type {
+	case "json_schema":
+		return text.Format.Schema
+	case "json_object":
+		return "json"
+	}
+	return nil
+}
+
+// ollamaOptions merges Options with the request sampling fields (nil when empty).
+func (o *OllamaChatRequest) ollamaOptions() map[string]any {
+	opts := o.Options
+	if opts.Temperature == nil {
+		opts.Temperature = o.Temperature
+	}
+	if opts.TopP == nil {
+		opts.TopP = o.TopP
+	}
+	if opts.NumPredict == nil && o.MaxOutputTokens > 0 {
+		opts.NumPredict = IntPtr(o.MaxOutputTokens)
+	}
+	out := map[string]any{}
+	if err := remarshal(opts, &out); err != nil {
+		return nil
+	}
+	for k, v := range opts.Extra {
+		if _, ok := out[k]; !ok {
+			out[k] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// TranscodeStream applies Transcoder to body (see StreamingRequest).
+func (o *OllamaChatRequest) TranscodeStream(ctx context.Context, body io.Reader) <-chan textual.StringCarrier {
+	o.stream.reset()
+	return transcodeTranslated(ctx, o.ResponsesRequest, body, o.Transcoder())
+}
+
+// Transcoder translates the NDJSON chunks into StreamEvents (see translatingTranscoder).
+func (o *OllamaChatRequest) Transcoder() textual.TranscoderFunc[textual.JsonGenericCarrier[OllamaChatChunk], textual.StringCarrier] {
+	return translatingTranscoder(o.ResponsesRequest, o.stream.translate)
+}
+
+// ─────────────────────────────────────────────────────────────
+// Request body
+// ─────────────────────────────────────────────────────────────
+
+type ollamaChatBody struct {
+	Model     string         `json:"model"`
+	Messages  any            `json:"messages"`
+	Tools     []ollamaTool   `json:"tools,omitempty"`
+	Format    any            `json:"format,omitempty"`
+	Options   map[string]any `json:"options,omitempty"`
+	Stream    bool           `json:"stream"`
+	KeepAlive any            `json:"keep_alive,omitempty"`
+	Think     any            `json:"think,omitempty"`
+}
+
+type ollamaTool struct {
+	Type     string         `json:"type"` // "function"
+	Function ollamaFunction `json:"function"`
+}
+
+type ollamaFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters"`
+}
+
+// ollamaPreservedKeys are the keys of the Ollama chat wire format kept by redaction.
+var ollamaPreservedKeys = []string{"images", "tool_name"}
+
+// OllamaMessage is a message of the Ollama chat API (request and streamed chunks).
+type OllamaMessage struct {
+	Role      string           `json:"role"` // system, user, assistant or tool
+	Content   string           `json:"content"`
+	Thinking  string           `json:"thinking,omitempty"`
+	Images    []string         `json:"images,omitempty"` // base64, without data URL prefix
+	ToolCalls []OllamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"` // tool messages
+}
+
+// OllamaToolCall is a complete tool call (Arguments is a JSON object, never a delta).
+type OllamaToolCall struct {
+	ID       string `json:"id,omitempty"`
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// ollamaMessagesFromInput converts the request input (string, InputItems, content parts,
+// tool calls, tool outputs and reasoning items) to Ollama messages.
+// Consecutive assistant items (text, tool calls, thinking) are merged into a single message.
+func ollamaMessagesFromInput(input any) (messages []OllamaMessage, err error) {
+	callNames := map[string]string{}
+	assistant := func() *OllamaMessage {
+		if n := len(messages); n > 0 && messages[n-1].Role == "assistant" {
+			return &messages[n-1]
+		}
+		messages = append(messages, OllamaMessage{Role: "assistant"})
+		return &messages[len(messages)-1]
+	}
+	err = walkInput(input, inputVisitor{
+		api: "Ollama chat",
+		message: func(role string, content any) error {
+			text, images, err := ollamaContent(content)
+			if err != nil {
+				return err
+			}
+			if text == "" && len(images) == 0 {
+				return nil
+			}
+			switch role {
+			case "assistant":
+				m := assistant()
+				m.Content += text
+				m.Images = append(m.Images, images...)
+				return nil
+			case "system", "developer":
+				role = "system"
+			default:
+				role = "user"
+			}
+			messages = append(messages, OllamaMessage{Role: role, Content: text, Images: images})
+			return nil
+		},
+		functionCall: func(callID, name string, args json.RawMessage) error {
+			callNames[callID] = name
+			var call OllamaToolCall
+			call.Function.Name, call.Function.Arguments = name, args
+			m := assistant()
+			m.ToolCalls = append(m.ToolCalls, call)
+			return nil
+		},
+		functionOutput: func(callID, output string) error {
+			messages = append(messages, OllamaMessage{Role: "tool", Content: output, ToolName: callNames[callID]})
+			return nil
+		},
+		reasoning: func(item ReasoningItem) error {
+			for _, s := range item.Summary {
+				assistant().Thinking += s.Text
+			}
+			return nil
+		},
+	})
+	return messages, err
+}
+
+// ollamaContent converts a message content to a text and base64 images.
+// Inlined text documents are appended to the text; other files are not supported.
+func ollamaContent(content any) (text string, images []string, err error) {
+	var parts []ContentPart
+	switch v := content.(type) {
+	case nil:
+		return "", nil, nil
+	case string:
+		return v, nil, nil
+	case ContentPart:
+		parts = []ContentPart{v}
+	case []ContentPart:
+		parts = v
+	case []any:
+		for _, e := range v {
+			switch p := e.(type) {
+			case ContentPart:
+				parts = append(parts, p)
+			case string:
+				parts = append(parts, Text(p))
+			default:
+				var part ContentPart
+				if err := remarshal(p, &part); err != nil {
+					return "", nil, fmt.Errorf("textualopenai: unsupported content part %T: %w", e, err)
+				}
+				parts = append(parts, part)
+			}
+		}
+	default:
+		return "", nil, fmt.Errorf("textualopenai: unsupported message content %T", content)
+	}
+
+	var b strings.Builder
+	for _, p := range parts {
+		switch p.Type {
+		case InputTextType, "output_text", "text":
+			b.WriteString(p.Text)
+		case InputImageType:
+			_, data, ok := strings.Cut(p.ImageURL, ";base64,")
+			if !ok || !strings.HasPrefix(p.ImageURL, "data:") {
+				return "", nil, errors.New("textualopenai: the Ollama chat API only accepts inlined (data URL) images")
+			}
+			images = append(images, data)
+		case InputFileType:
+			mediaType, data, ok := strings.Cut(strings.TrimPrefix(p.FileData, "data:"), ";base64,")
+			if !ok || !(strings.HasPrefix(mediaType, "text/") || mediaType == "application/json") {
+				return "", nil, fmt.Errorf("textualopenai: the Ollama chat API only accepts inlined text files (%s)", p.Filename)
+			}
+			decoded, err := base64.StdEncoding.DecodeString(data)
+			if err != nil {
+				return "", nil, fmt.Errorf("textualopenai: invalid data URL for %s: %w", p.Filename, err)
+			}
+			if b.Len() > 0 {
+				b.WriteString("\n\n")
+			}
+			b.Write(decoded)
+		default:
+			return "", nil, fmt.Errorf("textualopenai: content part type %q is not supported by the Ollama chat API", p.Type)
+		}
+	}
+	return b.String(), images, nil
+}
+
+// ─────────────────────────────────────────────────────────────
+// Stream translation
+// ─────────────────────────────────────────────────────────────
+
+// OllamaChatChunk is a line of the /api/chat NDJSON stream.
+// Durations are in nanoseconds and only set on the final (done) chunk.
+type OllamaChatChunk struct {
+	Model              string         `json:"model"`
+	CreatedAt          string         `json:"created_at"`
+	Message            *OllamaMessage `json:"message,omitempty"`
+	Done               bool           `json:"done"`
+	DoneReason         string         `json:"done_reason,omitempty"`
+	TotalDuration      int64          `json:"total_duration,omitempty"`
+	LoadDuration       int64          `json:"load_duration,omitempty"`
+	PromptEvalCount    int            `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64          `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int            `json:"eval_count,omitempty"`
+	EvalDuration       int64          `json:"eval_duration,omitempty"`
+	Error              string         `json:"error,omitempty"`
+}
+
+// ollamaStream holds the state of the translation of a stream.
+type ollamaStream struct {
+	mu sync.Mutex
+	translatedStream
+	started bool
+	metrics *OllamaMetrics
+}
+
+// reset prepares the translation of a new stream.
+func (s *ollamaStream) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.translatedStream.reset()
+	s.started, s.metrics = false, nil
+}
+
+// translate converts an Ollama chunk to zero or more StreamEvents.
+//
+// Content and thinking are streamed as deltas of message and reasoning items; tool calls
+// arrive complete and are emitted as a single arguments delta followed by the done events.
+// The final chunk records the metrics, which are also reported in the response usage.
+func (s *ollamaStream) translate(chunk OllamaChatChunk) []StreamEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if chunk.Error != "" {
+		s.push(StreamEvent{Type: Error, Message: chunk.Error})
+		return s.flush()
+	}
+	if !s.started {
+		s.started = true
+		s.responseID = "ollama_" + strings.NewReplacer(":", "", "-", "", ".", "").Replace(chunk.CreatedAt)
+		s.push(StreamEvent{Type: ResponseCreated, Response: s.response(chunk.Model, ResponseStatusInProgress, nil)})
+	}
+
+	if msg := chunk.Message; msg != nil {
+		if msg.Thinking != "" {
+			it := s.openItem(ReasoningItemType)
+			it.content.WriteString(msg.Thinking)
+			s.push(StreamEvent{Type: ReasoningTextDelta, OutputIndex: it.OutputIndex, ItemID: it.ItemID, Delta: msg.Thinking})
+		}
+		if msg.Content != "" {
+			it := s.openItem("message")
+			it.content.WriteString(msg.Content)
+			s.push(StreamEvent{Type: OutputTextDelta, OutputIndex: it.OutputIndex, ItemID: it.ItemID, Delta: msg.Content})
+		}
+		for _, tc := range msg.ToolCalls {
+			s.closeOpen()
+			s.functionCall(tc.ID, tc.Function.Name, tc.Function.Arguments)
+		}
+	}
+
+	if chunk.Done {
+		s.closeOpen()
+		m := OllamaMetrics{
+			DoneReason:         chunk.DoneReason,
+			TotalDuration:      time.Duration(chunk.TotalDuration),
+			LoadDuration:       time.Duration(chunk.LoadDuration),
+			PromptEvalCount:    chunk.PromptEvalCount,
+			PromptEvalDuration: time.Duration(chunk.PromptEvalDuration),
+			EvalCount:          chunk.EvalCount,
+			EvalDuration:       time.Duration(chunk.EvalDuration),
+		}
+		s.metrics = &m
+		status, typ := ResponseStatusCompleted, ResponseCompleted
+		if chunk.DoneReason == "length" {
+			status, typ = ResponseStatusIncomplete, ResponseIncomplete
+		}
+		s.push(StreamEvent{Type: typ, Response: s.response(chunk.Model, status, map[string]any{
+			"done_reason": chunk.DoneReason,
+			"usage": map[string]any{
+				"input_tokens":  chunk.PromptEvalCount,
+				"output_tokens": chunk.EvalCount,
+				"total_tokens":  chunk.PromptEvalCount + chunk.EvalCount,
+			},
+			"metrics": map[string]any{
+				"total_duration_ms":        m.TotalDuration.Milliseconds(),
+				"load_duration_ms":         m.LoadDuration.Milliseconds(),
+				"prompt_eval_duration_ms":  m.PromptEvalDuration.Milliseconds(),
+				"eval_duration_ms":         m.EvalDuration.Milliseconds(),
+				"tokens_per_second":        m.TokensPerSecond(),
+				"prompt_tokens_per_second": m.PromptTokensPerSecond(),
+			},
+		})})
+	}
+	return s.flush()
+}
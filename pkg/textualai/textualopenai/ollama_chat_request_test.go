@@ -0,0 +1,102 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+)
+
+var ollamaChunks = []string{
+	`{"model":"llama3.2","message":{"role":"assistant","content":"","thinking":"Blue light scatters."},"done":false}`,
+	`{"model":"llama3.2","message":{"role":"assistant","content":"Rayleigh"},"done":false}`,
+	`{"model":"llama3.2","message":{"role":"assistant","content":" scattering."},"done":false}`,
+	`{"model":"llama3.2","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"get_weather","arguments":{"city":"Paris"}}}]},"done":false}`,
+	`{"model":"llama3.2","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","total_duration":3000000000,"load_duration":500000000,"prompt_eval_count":20,"prompt_eval_duration":100000000,"eval_count":50,"eval_duration":2000000000}`,
+}
+
+func TestOllamaChatRequestStreamTranslation(t *testing.T) {
+	var gotPath string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(strings.Join(ollamaChunks, "\n") + "\n"))
+	}))
+	defer srv.Close()
+
+	model := "ollama:llama3.2"
+	c := testClient(t, srv.URL+"/v1", model)
+	req := NewOllamaChatRequest(context.Background(), testModel(t, model))
+	req.Input = "Why is the sky blue?"
+	req.KeepAlive = "30m"
+
+	var mu sync.Mutex
+	var reasoning, arguments string
+	var completed json.RawMessage
+	_ = req.AddObservers(func(e textual.JsonGenericCarrier[StreamEvent]) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch e.Value.Type {
+		case ReasoningTextDone:
+			reasoning = e.Value.Text
+		case FunctionCallArgumentsDone:
+			arguments = e.Value.Arguments
+		case ResponseCompleted:
+			completed = e.Value.Response
+		}
+	}, AllEvent)
+	_ = req.AddListeners(StringCarrierFrom, OutputTextDelta)
+
+	text, _, err := c.StreamAndTranscodeResponses(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The native endpoint is derived from the OpenAI-compatible base URL.
+	if gotPath != "/api/chat" || gotBody["keep_alive"] != "30m" || gotBody["stream"] != true {
+		t.Fatalf("request = %s %v", gotPath, gotBody)
+	}
+	if text != "Rayleigh scattering." {
+		t.Fatalf("text = %q", text)
+	}
+	if reasoning != "Blue light scatters." {
+		t.Fatalf("reasoning = %q", reasoning)
+	}
+	if arguments != `{"city":"Paris"}` {
+		t.Fatalf("arguments = %q", arguments)
+	}
+	var res struct {
+		Usage json.RawMessage `json:"usage"`
+	}
+	if err := json.Unmarshal(completed, &res); err != nil {
+		t.Fatal(err)
+	}
+	if u, ok := ParseUsage(res.Usage); !ok || u.InputTokens != 20 || u.OutputTokens != 50 {
+		t.Fatalf("usage = %+v (%s)", u, res.Usage)
+	}
+	m, ok := req.Metrics()
+	if !ok || m.DoneReason != "stop" || m.LoadDuration != 500*time.Millisecond || m.TokensPerSecond() != 25 {
+		t.Fatalf("metrics = %+v (%v)", m, ok)
+	}
+}
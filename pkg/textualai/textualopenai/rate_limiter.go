@@ -0,0 +1,433 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// Usage sample :
+// limiter := textualopenai.NewRateLimiter(textualopenai.RateLimit{RequestsPerMinute: 500, TokensPerMinute: 200_000}).
+// 	SetLimit("openai:gpt-4.1", textualopenai.RateLimit{RequestsPerMinute: 100, TokensPerMinute: 30_000, MaxConcurrent: 8}).
+// 	SetLimit("ollama", textualopenai.RateLimit{MaxConcurrent: 2}) // one bucket shared by every Ollama model
+// client = client.WithRateLimiter(limiter)
+//
+// // Batch traffic yields to interactive traffic (the default lane).
+// batchCtx := textualopenai.WithPriority(ctx, textualopenai.PriorityBatch)
+// req := textualopenai.NewResponsesRequest(batchCtx, model)
+
+// RateLimit configures a bucket of a RateLimiter. Zero values are unlimited.
+type RateLimit struct {
+	RequestsPerMinute int
+	TokensPerMinute   int // estimated input tokens (see EstimateTokens)
+
+	// MaxConcurrent bounds the streams in flight (a stream is in flight until its body is closed).
+	MaxConcurrent int
+}
+
+func (l RateLimit) isZero() bool {
+	return l.RequestsPerMinute <= 0 && l.TokensPerMinute <= 0 && l.MaxConcurrent <= 0
+}
+
+// Priority is the lane of a request in a RateLimiter queue: higher priorities are served first.
+type Priority int
+
+const (
+	PriorityBatch       Priority = -1
+	PriorityInteractive Priority = 0 // default
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a copy of ctx carrying the RateLimiter priority of the requests bound to it.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the priority carried by ctx (PriorityInteractive when none).
+func PriorityFrom(ctx context.Context) Priority {
+	if ctx != nil {
+		if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+			return p
+		}
+	}
+	return PriorityInteractive
+}
+
+// EstimateTokens estimates the tokens of a serialized request (about 4 bytes per token).
+func EstimateTokens(body []byte) int {
+	return (len(body) + 3) / 4
+}
+
+// RateLimiter is a client-side token bucket limiter keyed by provider and model.
+//
+// A request waits in its bucket queue until a request, its estimated tokens and
+// (optionally) a concurrency slot are available. Queued requests are served by priority,
+// then in arrival order. Buckets adapt to the rate limit headers of the responses
+// (remaining, reset) and pause after a 429 for the Retry-After delay.
+//
+// RateLimiter is safe for concurrent use.
+type RateLimiter struct {
+	mu       sync.Mutex
+	defaults RateLimit
+	limits   map[string]RateLimit // "provider" or "provider:model"
+	buckets  map[string]*rateBucket
+	seq      uint64
+}
+
+// NewRateLimiter returns a RateLimiter applying defaults to each model without a specific limit.
+func NewRateLimiter(defaults RateLimit) *RateLimiter {
+	return &RateLimiter{defaults: defaults, limits: map[string]RateLimit{}, buckets: map[string]*rateBucket{}}
+}
+
+// SetLimit sets the limit of a model ("openai:gpt-4.1", own bucket) or of a provider
+// ("openai", one bucket shared by the provider models without a model limit).
+func (l *RateLimiter) SetLimit(key string, limit RateLimit) *RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key = strings.ToLower(strings.TrimSpace(key))
+	l.limits[key] = limit
+	if b, ok := l.buckets[key]; ok {
+		b.limit = limit
+	}
+	return l
+}
+
+// bucketLocked returns the bucket of a model (nil when it is not limited).
+// create makes a bucket for a model without limit, driven by the response headers only.
+func (l *RateLimiter) bucketLocked(m models.Model, create bool) *rateBucket {
+	provider := strings.ToLower(string(m.ProviderName))
+	key := provider + ":" + strings.ToLower(string(m.ID))
+	limit, ok := l.limits[key]
+	if !ok {
+		if limit, ok = l.limits[provider]; ok {
+			key = provider
+		} else {
+			limit = l.defaults
+		}
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		if limit.isZero() && !create {
+			return nil
+		}
+		b = &rateBucket{limit: limit, last: time.Now()}
+		b.requests, b.tokens = float64(limit.RequestsPerMinute), float64(limit.TokensPerMinute)
+		l.buckets[key] = b
+	}
+	return b
+}
+
+// Wait blocks until the bucket of model can serve a request of tokens estimated tokens,
+// or ctx is done. The priority is read from ctx (see WithPriority).
+// release must be called when the request ends (it frees the concurrency slot).
+func (l *RateLimiter) Wait(ctx context.Context, model models.Model, tokens int) (release func(), err error) {
+	l.mu.Lock()
+	b := l.bucketLocked(model, false)
+	if b == nil {
+		l.mu.Unlock()
+		return func() {}, nil
+	}
+	l.seq++
+	w := &rateWaiter{priority: PriorityFrom(ctx), seq: l.seq, tokens: tokens, wake: make(chan struct{}, 1)}
+	b.enqueue(w)
+
+	for {
+		now := time.Now()
+		b.refill(now)
+		wait, ready := b.waitTime(w, now)
+		if ready {
+			b.take(w)
+			b.remove(w)
+			b.notifyHead()
+			l.mu.Unlock()
+			var once sync.Once
+			return func() {
+				once.Do(func() {
+					l.mu.Lock()
+					defer l.mu.Unlock()
+					b.inFlight--
+					b.notifyHead()
+				})
+			}, nil
+		}
+		l.mu.Unlock()
+
+		var timer *time.Timer
+		var expired <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			expired = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			l.mu.Lock()
+			b.remove(w)
+			b.notifyHead()
+			l.mu.Unlock()
+			return nil, ctx.Err()
+		case <-w.wake:
+		case <-expired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		l.mu.Lock()
+	}
+}
+
+// Observe adapts the bucket of model to the rate limit headers of a response:
+// the available requests and tokens are capped by the remaining values, the bucket
+// pauses until the reset time when nothing remains, and unset per-minute limits are
+// learned from the limit headers (models without limit get a bucket on their first rate
+// limit headers).
+func (l *RateLimiter) Observe(model models.Model, h HeaderInfos) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.bucketLocked(model, h.RateLimitRequestsLimit > 0 || h.RateLimitTokensLimit > 0)
+	if b == nil {
+		return
+	}
+	now := time.Now()
+	b.refill(now)
+	if h.RateLimitRequestsLimit > 0 {
+		if b.rpm() <= 0 {
+			b.requests = float64(h.RateLimitRequestsRemaining) // first observation
+		}
+		if b.limit.RequestsPerMinute <= 0 {
+			b.learnedRPM = h.RateLimitRequestsLimit
+		}
+		b.requests = math.Min(b.requests, float64(h.RateLimitRequestsRemaining))
+		if h.RateLimitRequestsRemaining <= 0 && h.RateLimitRequestsReset.After(now) {
+			b.pause(h.RateLimitRequestsReset)
+		}
+	}
+	if h.RateLimitTokensLimit > 0 {
+		if b.tpm() <= 0 {
+			b.tokens = float64(h.RateLimitTokensRemaining) // first observation
+		}
+		if b.limit.TokensPerMinute <= 0 {
+			b.learnedTPM = h.RateLimitTokensLimit
+		}
+		b.tokens = math.Min(b.tokens, float64(h.RateLimitTokensRemaining))
+		if h.RateLimitTokensRemaining <= 0 && h.RateLimitTokensReset.After(now) {
+			b.pause(h.RateLimitTokensReset)
+		}
+	}
+	b.notifyHead()
+}
+
+// Pause stops serving the bucket of model for d (e.g. after a 429), creating
+// the bucket of a model without limit.
+func (l *RateLimiter) Pause(model models.Model, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d <= 0 {
+		return
+	}
+	if b := l.bucketLocked(model, true); b != nil {
+		b.pause(time.Now().Add(d))
+		b.notifyHead()
+	}
+}
+
+// retryAfter returns the delay of the Retry-After (or retry-after-ms) header (0 if none).
+func retryAfter(h http.Header) time.Duration {
+	if ms, err := strconv.ParseFloat(strings.TrimSpace(h.Get("retry-after-ms")), 64); err == nil && ms > 0 {
+		return time.Duration(ms * float64(time.Millisecond))
+	}
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.ParseFloat(v, 64); err == nil && s > 0 {
+		return time.Duration(s * float64(time.Second))
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
+
+// rateBucket holds the state of a limited provider or model.
+type rateBucket struct {
+	limit                  RateLimit
+	learnedRPM, learnedTPM int // from the limit headers when limit leaves them unset
+	requests, tokens       float64
+	last                   time.Time
+	pausedUntil            time.Time
+	inFlight               int
+	waiters                []*rateWaiter // by priority, then arrival
+}
+
+type rateWaiter struct {
+	priority Priority
+	seq      uint64
+	tokens   int
+	wake     chan struct{}
+}
+
+func (b *rateBucket) rpm() int { return max(b.limit.RequestsPerMinute, b.learnedRPM) }
+func (b *rateBucket) tpm() int { return max(b.limit.TokensPerMinute, b.learnedTPM) }
+
+func (b *rateBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Minutes()
+	b.last = now
+	if elapsed <= 0 {
+		return
+	}
+	if rpm := float64(b.rpm()); rpm > 0 {
+		b.requests = math.Min(rpm, b.requests+elapsed*rpm)
+	}
+	if tpm := float64(b.tpm()); tpm > 0 {
+		b.tokens = math.Min(tpm, b.tokens+elapsed*tpm)
+	}
+}
+
+// waitTime tells whether w can be served now; otherwise it returns the time to wait
+// before checking again (0: wait for a notification).
+func (b *rateBucket) waitTime(w *rateWaiter, now time.Time) (time.Duration, bool) {
+	if len(b.waiters) == 0 || b.waiters[0] != w {
+		return 0, false
+	}
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now), false
+	}
+	if b.limit.MaxConcurrent > 0 && b.inFlight >= b.limit.MaxConcurrent {
+		return 0, false
+	}
+	var wait time.Duration
+	if rpm := float64(b.rpm()); rpm > 0 && b.requests < 1 {
+		wait = max(wait, time.Duration((1-b.requests)/rpm*float64(time.Minute)))
+	}
+	if tpm := float64(b.tpm()); tpm > 0 {
+		need := math.Min(float64(w.tokens), tpm) // a request larger than the bucket waits for a full bucket
+		if b.tokens < need {
+			wait = max(wait, time.Duration((need-b.tokens)/tpm*float64(time.Minute)))
+		}
+	}
+	if wait > 0 {
+		return wait + time.Millisecond, false
+	}
+	return 0, true
+}
+
+func (b *rateBucket) take(w *rateWaiter) {
+	if b.rpm() > 0 {
+		b.requests--
+	}
+	if tpm := float64(b.tpm()); tpm > 0 {
+		b.tokens -= math.Min(float64(w.tokens), tpm)
+	}
+	b.inFlight++
+}
+
+func (b *rateBucket) pause(until time.Time) {
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+func (b *rateBucket) enqueue(w *rateWaiter) {
+	b.waiters = append(b.waiters, w)
+	sort.SliceStable(b.waiters, func(i, j int) bool {
+		if b.waiters[i].priority != b.waiters[j].priority {
+			return b.waiters[i].priority > b.waiters[j].priority
+		}
+		return b.waiters[i].seq < b.waiters[j].seq
+	})
+	b.notifyHead()
+}
+
+func (b *rateBucket) remove(w *rateWaiter) {
+	for i, x := range b.waiters {
+		if x == w {
+			b.waiters = append(b.waiters[:i], b.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// notifyHead wakes the first waiter up so it re-evaluates its wait.
+func (b *rateBucket) notifyHead() {
+	if len(b.waiters) == 0 {
+		return
+	}
+	select {
+	case b.waiters[0].wake <- struct{}{}:
+	default:
+	}
+}
+
+// WithRateLimiter limits the streams opened by Client.Stream with l (nil disables it).
+// Each stream waits for its bucket (see RateLimiter.Wait) with the tokens estimated from
+// the serialized request, and the bucket adapts to the rate limit headers of the response.
+func (c Client) WithRateLimiter(l *RateLimiter) Client {
+	c.rateLimiter = l
+	return c
+}
+
+// acquireRate waits for the rate limiter (if any) before sending body.
+// release must be called when the stream ends.
+func (c Client) acquireRate(ctx context.Context, body []byte) (release func(), err error) {
+	if c.rateLimiter == nil {
+		return func() {}, nil
+	}
+	return c.rateLimiter.Wait(ctx, c.model, EstimateTokens(body))
+}
+
+// observeRate adapts the rate limiter to a response or to the error of a refused stream.
+func (c Client) observeRate(resp *http.Response, err error) {
+	if c.rateLimiter == nil {
+		return
+	}
+	if resp != nil {
+		c.rateLimiter.Observe(c.model, HeaderInfosFromHTTPResponse(resp))
+	}
+	var streamErr *StreamError
+	if errors.As(err, &streamErr) {
+		c.rateLimiter.Observe(c.model, HeaderInfosFromHTTPResponse(&http.Response{Header: streamErr.Header}))
+		if streamErr.StatusCode == http.StatusTooManyRequests {
+			d := retryAfter(streamErr.Header)
+			if d <= 0 {
+				d = time.Second
+			}
+			c.rateLimiter.Pause(c.model, d)
+		}
+	}
+}
+
+// releasingBody calls release when the body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.once.Do(b.release)
+	return b.ReadCloser.Close()
+}
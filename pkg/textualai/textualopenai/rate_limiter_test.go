@@ -0,0 +1,198 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitQueued waits until n requests are queued in the bucket of model.
+func waitQueued(t *testing.T, l *RateLimiter, model string, n int) {
+	t.Helper()
+	m := testModel(t, model)
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		l.mu.Lock()
+		b := l.bucketLocked(m, false)
+		queued := b != nil && len(b.waiters) == n
+		l.mu.Unlock()
+		if queued {
+			return
+		}
+	}
+	t.Fatalf("%d requests not queued", n)
+}
+
+func TestRateLimiterPriorityOrdering(t *testing.T) {
+	const model = "openai:gpt-4.1"
+	m := testModel(t, model)
+	l := NewRateLimiter(RateLimit{MaxConcurrent: 1})
+	release, err := l.Wait(context.Background(), m, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan string, 3)
+	enqueue := func(name string, p Priority, queued int) {
+		go func() {
+			r, err := l.Wait(WithPriority(context.Background(), p), m, 0)
+			if err != nil {
+				served <- err.Error()
+				return
+			}
+			served <- name
+			r()
+		}()
+		waitQueued(t, l, model, queued)
+	}
+	enqueue("batch", PriorityBatch, 1)
+	enqueue("interactive-1", PriorityInteractive, 2)
+	enqueue("interactive-2", PriorityInteractive, 3)
+	release()
+
+	var got []string
+	for range 3 {
+		got = append(got, <-served)
+	}
+	// Higher priorities first, then arrival order.
+	if want := []string{"interactive-1", "interactive-2", "batch"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("served %v, want %v", got, want)
+	}
+}
+
+func TestRateLimiterMaxConcurrent(t *testing.T) {
+	m := testModel(t, "openai:gpt-4.1")
+	l := NewRateLimiter(RateLimit{}).SetLimit("openai", RateLimit{MaxConcurrent: 2})
+	r1, _ := l.Wait(context.Background(), m, 0)
+	r2, _ := l.Wait(context.Background(), m, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := l.Wait(ctx, m, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("third request: err = %v, want it to wait", err)
+	}
+	r1()
+	r1() // release is idempotent
+	r3, err := l.Wait(context.Background(), m, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2()
+	r3()
+}
+
+func TestRateLimiterHeaderDrivenBucket(t *testing.T) {
+	m := testModel(t, "openai:gpt-4.1")
+	l := NewRateLimiter(RateLimit{}) // no configured limit
+
+	// Pause creates the bucket of an unlimited model.
+	l.Pause(m, 50*time.Millisecond)
+	start := time.Now()
+	release, err := l.Wait(context.Background(), m, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("Wait returned after %s, want the 50ms pause", elapsed)
+	}
+
+	// So do the rate limit headers.
+	other := testModel(t, "openai:gpt-4.1-mini")
+	l.Observe(other, HeaderInfos{RateLimitRequestsLimit: 100, RateLimitRequestsRemaining: 0, RateLimitRequestsReset: time.Now().Add(50 * time.Millisecond)})
+	start = time.Now()
+	if release, err = l.Wait(context.Background(), other, 0); err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("Wait returned after %s, want the reset delay", elapsed)
+	}
+
+	// A response without rate limit headers leaves unlimited models alone.
+	third := testModel(t, "ollama:llama3.2")
+	l.Observe(third, HeaderInfos{})
+	l.mu.Lock()
+	b := l.bucketLocked(third, false)
+	l.mu.Unlock()
+	if b != nil {
+		t.Fatal("bucket created without rate limit headers")
+	}
+}
+
+func TestRateLimiterRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("retry-after-ms", "80")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"message":"slow down","type":"rate_limit_error"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(sseEvents(resumableEvents...)))
+	}))
+	defer srv.Close()
+
+	model := "openai:gpt-4.1"
+	c := testClient(t, srv.URL, model).WithRateLimiter(NewRateLimiter(RateLimit{}))
+	stream := func() error {
+		req := NewResponsesRequest(context.Background(), testModel(t, model))
+		req.Input = "hi"
+		_, _, err := c.StreamAndTranscodeResponses(context.Background(), req)
+		return err
+	}
+	if err := stream(); ClassifyError(err) != ErrorClassRateLimit {
+		t.Fatalf("first call: err = %v", err)
+	}
+	start := time.Now()
+	if err := stream(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Fatalf("second call sent after %s, want the Retry-After delay", elapsed)
+	}
+}
+
+func TestResumeStreamTakesAConcurrencySlot(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(sseEvents(resumableEvents...)))
+	}))
+	defer srv.Close()
+
+	c := testClient(t, srv.URL, "openai:gpt-4.1").WithRateLimiter(NewRateLimiter(RateLimit{MaxConcurrent: 1}))
+	resp, err := c.ResumeStream(context.Background(), "resp_1", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := c.ResumeStream(ctx, "resp_1", -1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second resume: err = %v, want it to wait", err)
+	}
+	_ = resp.Body.Close()
+	if resp, err = c.ResumeStream(context.Background(), "resp_1", 2); err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"strings"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// Usage sample :
+// req.Reasoning = &textualopenai.ReasoningConfig{
+// 	Effort:  textualopenai.ReasoningEffortHigh,
+// 	Summary: textualopenai.ReasoningSummaryAuto, // stream reasoning summaries
+// }
+// // OpenAI receives {"reasoning":{"effort":"high","summary":"auto"}},
+// // Ollama {"think":"high"} (gpt-oss) or {"think":true}, xAI only what its reasoning models accept.
+
+// ReasoningEffort bounds the reasoning effort of reasoning models.
+type ReasoningEffort string
+
+const (
+	ReasoningEffortMinimal ReasoningEffort = "minimal"
+	ReasoningEffortLow     ReasoningEffort = "low"
+	ReasoningEffortMedium  ReasoningEffort = "medium"
+	ReasoningEffortHigh    ReasoningEffort = "high"
+)
+
+// ReasoningSummary selects the reasoning summary streamed by the provider.
+type ReasoningSummary string
+
+const (
+	ReasoningSummaryAuto     ReasoningSummary = "auto"
+	ReasoningSummaryConcise  ReasoningSummary = "concise"
+	ReasoningSummaryDetailed ReasoningSummary = "detailed"
+)
+
+// ReasoningConfig configures reasoning models.
+type ReasoningConfig struct {
+	Effort  ReasoningEffort  `json:"effort,omitempty"`
+	Summary ReasoningSummary `json:"summary,omitempty"`
+}
+
+// providerReasoning maps Reasoning (or the legacy Thinking flag) to the provider dialect:
+// the OpenAI `reasoning` object, or the Ollama `think` field.
+func (r *ResponsesRequest) providerReasoning() (reasoning *ReasoningConfig, think any) {
+	cfg := r.Reasoning
+	if cfg == nil && r.Thinking {
+		cfg = &ReasoningConfig{Summary: ReasoningSummaryAuto}
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+
+	switch r.model.ProviderName {
+	case models.ProviderOllama:
+		// Ollama accepts an effort level for gpt-oss models only, a boolean otherwise.
+		switch cfg.Effort {
+		case ReasoningEffortLow, ReasoningEffortMedium, ReasoningEffortHigh:
+			if strings.HasPrefix(string(r.Model), "gpt-oss") {
+				return nil, string(cfg.Effort)
+			}
+		}
+		return nil, true
+
+	case models.ProviderXAI:
+		// Grok 4 models always reason and reject reasoning parameters;
+		// grok-3-mini accepts a "low" or "high" effort, and no summary.
+		if !strings.Contains(string(r.Model), "grok-3-mini") || cfg.Effort == "" {
+			return nil, nil
+		}
+		effort := ReasoningEffortHigh
+		if cfg.Effort == ReasoningEffortMinimal || cfg.Effort == ReasoningEffortLow {
+			effort = ReasoningEffortLow
+		}
+		return &ReasoningConfig{Effort: effort}, nil
+
+	default:
+		return cfg, nil
+	}
+}
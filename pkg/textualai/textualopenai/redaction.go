@@ -0,0 +1,113 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"encoding/json"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/redaction"
+)
+
+// Usage sample :
+// red := redaction.NewRedactor() // built-in detectors: API keys, emails, credit cards, phones.
+// req := textualopenai.NewResponsesRequest(ctx, model)
+// req.SetRedactor(red)
+// req.Input = "Mail john.doe@example.com the invoice"
+// // The provider receives "Mail [REDACTED_EMAIL_1] the invoice".
+// // Function tool arguments and streamed text deltas are re-hydrated locally.
+//
+// history.SetAddProcessor(textualopenai.InputItemRedactor(red)) // memories never store PII.
+
+// SetRedactor attaches a redaction.Redactor to the request.
+//
+// When set:
+//   - Input is redacted when the request is serialized (the request itself is left untouched),
+//   - function tool arguments are re-hydrated before the handlers are invoked,
+//   - output text deltas (and *.done text payloads) are re-hydrated before observers and listeners.
+//
+// Tool outputs sent back in a follow-up request are redacted as part of that request Input.
+// Passing nil disables redaction.
+func (r *ResponsesRequest) SetRedactor(red *redaction.Redactor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.redactor = red
+	if red == nil {
+		r.textRestorer = nil
+		return
+	}
+	r.textRestorer = redaction.NewStreamRestorer(red)
+}
+
+// Redactor returns the attached redaction.Redactor (nil if none).
+func (r *ResponsesRequest) Redactor() *redaction.Redactor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.redactor
+}
+
+// InputItemRedactor returns a function redacting the Content of an InputItem.
+// It is designed to be installed with memories.Memory.SetAddProcessor.
+func InputItemRedactor(red *redaction.Redactor) func(InputItem) InputItem {
+	return func(item InputItem) InputItem {
+		if red == nil || item.Content == nil {
+			return item
+		}
+		content, err := red.RedactJSON(item.Content, attachmentRefKeys...)
+		if err != nil {
+			// Never store an unredacted item silently.
+			item.Content = ""
+			return item
+		}
+		item.Content = content
+		return item
+	}
+}
+
+// restoreStreamEvent re-hydrates the placeholders contained in text events.
+//
+// It returns the text held back by the stream restorer when a text part is complete:
+// the caller must emit it as an extra OutputTextDelta before the done event.
+func (r *ResponsesRequest) restoreStreamEvent(ev *StreamEvent) (flushed string) {
+	r.mu.Lock()
+	red := r.redactor
+	restorer := r.textRestorer
+	r.mu.Unlock()
+	if red == nil {
+		return ""
+	}
+
+	switch ev.Type {
+	case OutputTextDelta:
+		ev.Delta = restorer.Write(ev.Delta)
+	case TextDone:
+		flushed = restorer.Flush()
+		ev.Text = red.Restore(ev.Text)
+	case ReasoningSummaryTextDelta, ReasoningTextDelta, RefusalDelta:
+		ev.Delta = red.Restore(ev.Delta)
+	case ReasoningSummaryTextDone, ReasoningTextDone:
+		ev.Text = red.Restore(ev.Text)
+	case RefusalDone:
+		ev.Refusal = red.Restore(ev.Refusal)
+	}
+	return flushed
+}
+
+// restoreArguments re-hydrates function call arguments before handler invocation.
+func restoreArguments(red *redaction.Redactor, args json.RawMessage) json.RawMessage {
+	if red == nil {
+		return args
+	}
+	return red.RestoreJSON(args)
+}
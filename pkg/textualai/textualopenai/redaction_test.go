@@ -0,0 +1,84 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/redaction"
+)
+
+// preservedValue is personal-looking data in a value that must reach the provider unchanged.
+const preservedValue = "jane@example.com"
+
+func TestWireRequestsPreserveTheirKeysFromRedaction(t *testing.T) {
+	ctx := context.Background()
+	message := InputItem{Role: "user", Content: "mail " + preservedValue}
+	reasoning := ReasoningItem{Type: ReasoningItemType, Summary: []ReasoningSummaryPart{{Type: "summary_text", Text: "Thinking."}}, EncryptedContent: preservedValue}
+	image := InputItem{Role: "user", Content: []ContentPart{ImageFromURL("https://cdn.example.com/" + preservedValue + ".png")}}
+	call := map[string]any{"type": "function_call", "call_id": "call_1", "name": preservedValue, "arguments": "{}"}
+	output := FunctionCallOutputItem{Type: "function_call_output", CallID: "call_1", Output: "done"}
+
+	messages := NewMessagesRequest(ctx, testModel(t, "anthropic:claude-sonnet-4-5"))
+	messages.Input = []any{message, image, reasoning, call, output} // url, signature
+	gemini := NewGenerateContentRequest(ctx, testModel(t, "gemini:gemini-2.5-pro"))
+	gemini.Input = []any{message, image, reasoning, call, output} // fileUri, thoughtSignature
+	ollama := NewOllamaChatRequest(ctx, testModel(t, "ollama:llama3.2"))
+	ollama.Input = []any{message, call, output} // tool_name
+
+	tests := []struct {
+		name string
+		req  interface {
+			json.Marshaler
+			SetRedactor(*redaction.Redactor)
+		}
+		preserved []string // keys holding preservedValue
+	}{
+		{"messages", messages, []string{`"url":"https://cdn.example.com/jane@example.com.png"`, `"signature":"jane@example.com"`}},
+		{"gemini", gemini, []string{`"fileUri":"https://cdn.example.com/jane@example.com.png"`, `"thoughtSignature":"jane@example.com"`}},
+		{"ollama", ollama, []string{`"tool_name":"jane@example.com"`}},
+	}
+	for _, tt := range tests {
+		tt.req.SetRedactor(redaction.NewRedactor())
+		b, err := tt.req.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		body := string(b)
+		if strings.Contains(body, "mail "+preservedValue) {
+			t.Errorf("%s: message not redacted: %s", tt.name, body)
+		}
+		for _, want := range tt.preserved {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: %s not preserved: %s", tt.name, want, body)
+			}
+		}
+	}
+}
+
+func TestInputItemRedactorPreservesAttachmentRefs(t *testing.T) {
+	ref := AttachmentRef{Type: AttachmentRefType, Kind: InputImageType, Path: "/home/" + preservedValue + "/photo.png", SHA256: "abc"}
+	item := InputItemRedactor(redaction.NewRedactor())(InputItem{Role: "user", Content: []any{Text("mail " + preservedValue), ref}})
+	b, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := string(b); strings.Contains(body, "mail "+preservedValue) || !strings.Contains(body, `"path":"/home/jane@example.com/photo.png"`) {
+		t.Fatalf("item = %s", body)
+	}
+}
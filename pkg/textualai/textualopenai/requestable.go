@@ -16,6 +16,10 @@ package textualopenai
 
 import (
 	"context"
+	"io"
+	"net/http"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
 )
 
 type Requestable interface {
@@ -25,3 +29,25 @@ type Requestable interface {
 
 	URL(baseURL string) (string, error)
 }
+
+// HeaderSetter is implemented by requests needing provider specific headers
+// (authentication scheme, API version). Client.Stream calls it instead of setting
+// the default "Authorization: Bearer" header.
+type HeaderSetter interface {
+	SetHeaders(h http.Header, apiKey string)
+}
+
+// StreamingRequest is a Requestable whose event stream can be transcoded to text.
+// It is implemented by ResponsesRequest and MessagesRequest.
+type StreamingRequest interface {
+	Requestable
+
+	// TranscodeStream transcodes the streamed body to the listeners outputs.
+	TranscodeStream(ctx context.Context, body io.Reader) <-chan textual.StringCarrier
+
+	// WaitFunctionCalls waits for the function calls started while streaming.
+	WaitFunctionCalls(ctx context.Context) error
+
+	RemoveListeners()
+	RemoveObservers()
+}
@@ -20,12 +20,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/url"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/instrumentation"
 	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/redaction"
 )
 
 // InputItem is the minimal "message-like" shape used in the Responses `input` array.
@@ -68,8 +74,9 @@ type ResponsesRequest struct {
 	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
 
 	// Thinking is a legacy flag kept for backward compatibility with earlier experiments.
-	// Prefer the `Reasoning` field for modern reasoning controls.
-	Thinking bool `json:"thinking,omitempty"`
+	// It is never sent as-is: when Reasoning is nil it requests the provider default
+	// reasoning (see reasoning.go). Prefer the `Reasoning` field.
+	Thinking bool `json:"-"`
 
 	// Temperature controls sampling randomness. Use a pointer so callers can explicitly
 	// What sampling temperature to use, between 0 and 2.
@@ -102,6 +109,10 @@ type ResponsesRequest struct {
 
 	// Tools defines built-in tools (web_search, file_search, code_interpreter, ...) and/or
 	// custom tools (function calling). This is intentionally `[]any` to support all tool types.
+	//
+	// Function tools registered with RegisterFunctionTool* live in the request ToolRegistry
+	// and their definitions are kept in this field. Tools registered directly on an attached
+	// ToolRegistry are merged when the request is serialized (see EffectiveTools).
 	Tools []any `json:"tools,omitempty"`
 
 	// ToolChoice controls how tools are selected ("auto", "required", "none") or can be an
@@ -148,8 +159,9 @@ type ResponsesRequest struct {
 	// Background runs the response in background mode.
 	Background bool `json:"background,omitempty"`
 
-	// Reasoning holds reasoning-specific configuration (effort, summary, ...).
-	Reasoning any `json:"reasoning,omitempty"`
+	// Reasoning holds reasoning-specific configuration (effort, summary).
+	// It is mapped to the provider dialect when the request is serialized (see reasoning.go).
+	Reasoning *ReasoningConfig `json:"reasoning,omitempty"`
 
 	// SafetyIdentifier is a stable identifier for your end-users.
 	SafetyIdentifier string `json:"safety_identifier,omitempty"`
@@ -169,6 +181,7 @@ type ResponsesRequest struct {
 	// Non serializable
 	ctx       context.Context
 	splitFunc bufio.SplitFunc
+	model     models.Model // capability gating (see hosted_tools.go, content_parts.go)
 
 	// Listeners
 	mu        sync.Mutex
@@ -177,16 +190,60 @@ type ResponsesRequest struct {
 
 	// Function calling delegate (non-serializable).
 	// Initialized lazily when the first function tool is registered.
-	functionTools                 map[string]registeredFunctionTool
+	toolRegistry                  *ToolRegistry
+	mirroredTools                 map[string]bool // registry tools kept in Tools (see syncTool)
+	toolOverrides                 map[string]bool // per request enable (true) / disable (false)
+	disabledToolGroups            map[string]bool
 	functionCalls                 map[string]*functionCallState
 	functionCallOutputs           []FunctionCallOutputItem
 	functionCallOutputIndexByCall map[string]int
 	functionCallObserver          FunctionCallObserver
+	functionCallOutputOrder       map[string]int // call_id -> output_index
+
+	// Function call worker pool (see function_delegate.go).
+	functionCallConcurrency int
+	functionCallSem         chan struct{}
+	functionCallWG          sync.WaitGroup
+
+	// Function call safeguards (see function_safeguards.go).
+	functionCallTimeout        time.Duration
+	functionOutputLimit        int
+	functionValidationDisabled bool
+
+	// Human-in-the-loop approval (see function_approval.go).
+	functionCallApprover        FunctionCallApprover
+	functionCallApprovalTimeout time.Duration
+
+	// Stream progress, for background responses resumption (see background.go).
+	responseID         string
+	lastSequenceNumber int
+	sequenceSeen       bool // lastSequenceNumber is set (sequence numbers start at 0)
+	terminated         bool
+
+	// Output items kept for stateless continuations (see encrypted_reasoning.go).
+	outputItems []capturedOutputItem
+
+	// Redaction (non-serializable).
+	redactor     *redaction.Redactor
+	textRestorer *redaction.StreamRestorer
+
+	// Tracing and metrics (see instrumentation.go).
+	instrumentation instrumentation.Instrumentation
+	call            *llmCall
+
+	// Stats of the last stream (see stream_stats.go).
+	stats *streamStats
 }
 
 type registeredFunctionTool struct {
 	Tool    FunctionTool
 	Handler JSONFunction
+	Schema  *jsonSchema
+	Options FunctionToolOptions
+
+	// Custom tools (see custom_tools.go): Tool only carries the name and description.
+	Custom  *CustomTool
+	Grammar *regexp.Regexp
 }
 
 type functionCallState struct {
@@ -204,6 +261,7 @@ type outputItemEnvelope struct {
 	CallID    string `json:"call_id,omitempty"`
 	Name      string `json:"name,omitempty"`
 	Arguments string `json:"arguments,omitempty"`
+	Input     string `json:"input,omitempty"`
 }
 
 func NewResponsesRequest(ctx context.Context, model models.Model) *ResponsesRequest {
@@ -211,6 +269,7 @@ func NewResponsesRequest(ctx context.Context, model models.Model) *ResponsesRequ
 		ctx:             ctx,
 		splitFunc:       textual.ScanJSON,
 		Model:           model.ID,
+		model:           model,
 		Input:           nil,
 		Stream:          true,
 		MaxOutputTokens: 0,
@@ -254,6 +313,13 @@ func (r *ResponsesRequest) Validate() error {
 		return errors.New("textualopenai: previous_response_id cannot be used with conversation")
 	}
 
+	if err := r.validateHostedTools(); err != nil {
+		return err
+	}
+	if err := r.validateContentParts(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -338,38 +404,101 @@ func (r *ResponsesRequest) RemoveObservers() {
 func (r *ResponsesRequest) Transcoder() textual.TranscoderFunc[textual.JsonGenericCarrier[StreamEvent], textual.StringCarrier] {
 	return func(ctx context.Context, in <-chan textual.JsonGenericCarrier[StreamEvent]) <-chan textual.StringCarrier {
 		return textual.AsyncEmitter(ctx, in, func(ctx context.Context, c textual.JsonGenericCarrier[StreamEvent], emit func(s textual.StringCarrier)) {
-			ev := c.Value
-
-			// Built-in delegate: handle function calling support.
-			// This runs before user observers/listeners so the request state is up to date
-			// when they receive the event.
-			r.processFunctionCalling(ctx, ev)
-
-			// Snapshot callbacks under lock, then call them outside the lock.
-			r.mu.Lock()
-			observerFunc := r.observers[ev.Type]
-			if observerFunc == nil {
-				observerFunc = r.observers[AllEvent]
-			}
-			listenerFunc := r.listeners[ev.Type]
-			if listenerFunc == nil {
-				listenerFunc = r.listeners[AllEvent]
-			}
-			r.mu.Unlock()
-
-			// the StreamEvent is unaltered.
-			if observerFunc != nil {
-				observerFunc(c)
-			}
-
-			// The StreamEvent will be processed: we emit the result of the listener function.
-			if listenerFunc != nil {
-				emit(listenerFunc(c))
-			}
+			r.handleEvent(ctx, c, func(_ StreamEvent, s textual.StringCarrier) {
+				emit(s)
+			}, nil)
 		})
 	}
 }
 
+// TranscodeStream applies Transcoder to body, split with SplitFunc (see StreamingRequest).
+func (r *ResponsesRequest) TranscodeStream(ctx context.Context, body io.Reader) <-chan textual.StringCarrier {
+	ioT := textual.NewIOReaderTranscoder[textual.JsonGenericCarrier[StreamEvent], textual.StringCarrier](r.Transcoder(), body)
+	ioT.SetSplitFunc(r.SplitFunc())
+	ioT.SetContext(ctx)
+	return ioT.Start()
+}
+
+// handleEvent drops replayed events, re-hydrates redacted placeholders, then dispatches the event.
+// emit receives the listener outputs along with the event they were produced for;
+// fallback (optional) replaces the listener of events that have none.
+func (r *ResponsesRequest) handleEvent(ctx context.Context, c textual.JsonGenericCarrier[StreamEvent], emit func(ev StreamEvent, s textual.StringCarrier), fallback eventListener) {
+	// Events replayed by a resumed stream were already handled.
+	if !r.trackProgress(c.Value) {
+		return
+	}
+	// Re-hydrate redacted placeholders before anyone sees the event.
+	// Text held back by the stream restorer is emitted as a synthetic delta.
+	if flushed := r.restoreStreamEvent(&c.Value); flushed != "" {
+		synthetic := textual.JsonGenericCarrier[StreamEvent]{
+			Index: c.Index,
+			Value: StreamEvent{
+				Type:         OutputTextDelta,
+				ResponseID:   c.Value.ResponseID,
+				OutputIndex:  c.Value.OutputIndex,
+				ItemID:       c.Value.ItemID,
+				ContentIndex: c.Value.ContentIndex,
+				Delta:        flushed,
+			},
+		}
+		r.dispatch(ctx, synthetic, func(s textual.StringCarrier) {
+			emit(synthetic.Value, s)
+		}, fallback)
+	}
+	r.dispatch(ctx, c, func(s textual.StringCarrier) {
+		emit(c.Value, s)
+	}, fallback)
+}
+
+// eventListener produces the output of an event; ok is false when there is nothing to emit.
+type eventListener func(c textual.JsonGenericCarrier[StreamEvent]) (s textual.StringCarrier, ok bool)
+
+// dispatch runs the built-in delegates, then the observer and the listener registered for the event type
+// (or fallback when no listener is registered).
+func (r *ResponsesRequest) dispatch(ctx context.Context, c textual.JsonGenericCarrier[StreamEvent], emit func(s textual.StringCarrier), fallback eventListener) {
+	ev := c.Value
+
+	// Built-in delegate: handle function calling support.
+	// This runs before user observers/listeners so the request state is up to date
+	// when they receive the event.
+	r.processFunctionCalling(ctx, ev)
+
+	// Built-in delegate: keep output items for stateless continuations.
+	r.captureOutputItem(ev)
+
+	// Built-in delegate: time to first token, usage and end of the call span.
+	r.observeCall(ev)
+
+	// Built-in delegate: latency, throughput and event counts.
+	r.observeStats(ev)
+
+	// Snapshot callbacks under lock, then call them outside the lock.
+	r.mu.Lock()
+	observerFunc := r.observers[ev.Type]
+	if observerFunc == nil {
+		observerFunc = r.observers[AllEvent]
+	}
+	listenerFunc := r.listeners[ev.Type]
+	if listenerFunc == nil {
+		listenerFunc = r.listeners[AllEvent]
+	}
+	r.mu.Unlock()
+
+	// the StreamEvent is unaltered.
+	if observerFunc != nil {
+		observerFunc(c)
+	}
+
+	// The StreamEvent will be processed: we emit the result of the listener function.
+	if listenerFunc != nil {
+		emit(listenerFunc(c))
+	} else if fallback != nil {
+		if s, ok := fallback(c); ok {
+			emit(s)
+		}
+	}
+}
+
 /////////////////////////////////////
 // Tools support
 /////////////////////////////////////
@@ -403,62 +532,41 @@ func (r *ResponsesRequest) Transcoder() textual.TranscoderFunc[textual.JsonGener
 
 // RegisterFunctionToolStrict is the same as RegisterFunctionTool but allows setting `strict`
 // on the tool definition when supported by the API/model.
-func (r *ResponsesRequest) RegisterFunctionToolStrict(name, description string, parameters any, strict bool, fn JSONFunction) error {
-	return r.registerFunctionTool(FunctionTool{
-		Type:        "function",
-		Name:        name,
-		Description: description,
-		Parameters:  parameters,
-		Strict:      BoolPtr(strict),
-	}, fn)
+func (r *ResponsesRequest) RegisterFunctionToolStrict(name, description string, parameters any, strict bool, fn JSONFunction, opts ...FunctionToolOption) error {
+	if r == nil {
+		return errors.New("textualopenai: nil ResponsesRequest")
+	}
+	if err := r.ToolRegistry().RegisterFunctionToolStrict(name, description, parameters, strict, fn, opts...); err != nil {
+		return err
+	}
+	r.syncTool(name)
+	return nil
 }
 
 // RegisterFunctionToolTyped registers a custom "function" tool whose handler uses typed Go
 // arguments and results. JSON marshaling/unmarshaling is handled internally using the standard
 // library only (encoding/json).
-func RegisterFunctionToolTyped[A any, R any](r *ResponsesRequest, name, description string, parameters any, fn func(context.Context, A) (R, error)) error {
-	wrapped := func(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
-		if len(args) == 0 {
-			args = json.RawMessage(`{}`)
-		}
-		var a A
-		if err := json.Unmarshal(args, &a); err != nil {
-			return nil, err
-		}
-
-		res, err := fn(ctx, a)
-		if err != nil {
-			return nil, err
-		}
-
-		b, err := json.Marshal(res)
-		if err != nil {
-			return nil, err
-		}
-		return json.RawMessage(b), nil
-	}
-	return r.RegisterFunctionTool(name, description, parameters, wrapped)
+func RegisterFunctionToolTyped[A any, R any](r *ResponsesRequest, name, description string, parameters any, fn func(context.Context, A) (R, error), opts ...FunctionToolOption) error {
+	return r.RegisterFunctionTool(name, description, parameters, typedJSONFunction(fn), opts...)
 }
 
-// UnregisterFunctionTool removes a previously registered function tool and
-// removes its definition from the `Tools` request field (when present).
+// UnregisterFunctionTool removes a previously registered function tool from the request
+// registry and removes its definition from the `Tools` request field (when present).
+//
+// When the registry is shared (see SetToolRegistry), the tool is removed for every request
+// using it: prefer DisableTools to hide a tool from a single request.
 func (r *ResponsesRequest) UnregisterFunctionTool(name string) error {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return errors.New("textualopenai: function tool name is required")
 	}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if r.functionTools == nil {
-		return fmt.Errorf("textualopenai: function tool not found: %s", name)
-	}
-	if _, ok := r.functionTools[name]; !ok {
-		return fmt.Errorf("textualopenai: function tool not found: %s", name)
+	if err := r.ToolRegistry().Unregister(name); err != nil {
+		return err
 	}
 
-	delete(r.functionTools, name)
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	if len(r.Tools) == 0 {
 		return nil
@@ -467,7 +575,8 @@ func (r *ResponsesRequest) UnregisterFunctionTool(name string) error {
 	// Remove any matching function tools from the request tools slice.
 	filtered := r.Tools[:0]
 	for _, t := range r.Tools {
-		if isSameFunctionTool(t, name) {
+		if n, ok := functionToolName(t); ok && n == name {
+			delete(r.mirroredTools, n)
 			continue
 		}
 		filtered = append(filtered, t)
@@ -477,15 +586,15 @@ func (r *ResponsesRequest) UnregisterFunctionTool(name string) error {
 }
 
 func (r *ResponsesRequest) ensureFunctionDelegateLocked() {
-	if r.functionTools == nil {
-		r.functionTools = make(map[string]registeredFunctionTool)
-	}
 	if r.functionCalls == nil {
 		r.functionCalls = make(map[string]*functionCallState)
 	}
 	if r.functionCallOutputIndexByCall == nil {
 		r.functionCallOutputIndexByCall = make(map[string]int)
 	}
+	if r.functionCallOutputOrder == nil {
+		r.functionCallOutputOrder = make(map[string]int)
+	}
 }
 
 func (r *ResponsesRequest) SetFunctionCallObserver(f FunctionCallObserver) {
@@ -496,22 +605,30 @@ func (r *ResponsesRequest) SetFunctionCallObserver(f FunctionCallObserver) {
 }
 
 // RegisterFunctionTool registers a custom "function" tool for the Responses API and
-// binds it to a JSON-only handler.
+// binds it to a JSON-only handler. The tool is added to the request ToolRegistry
+// (a private one unless a shared registry was attached with SetToolRegistry).
 //
 // - name: tool name (must match the function call name emitted by the model)
 // - description: natural language description for the model
 // - parameters: JSON Schema object (use map[string]any or a struct that marshals to the schema)
 // - fn: handler invoked with raw JSON arguments, returns raw JSON output
-func (r *ResponsesRequest) RegisterFunctionTool(name, description string, parameters any, fn JSONFunction) error {
-	return r.registerFunctionTool(FunctionTool{
-		Type:        "function",
-		Name:        name,
-		Description: description,
-		Parameters:  parameters,
-	}, fn)
+// - opts: optional execution safeguards (timeout, output limit, ...)
+//
+// Arguments are validated against the parameters JSON Schema before the handler is invoked.
+func (r *ResponsesRequest) RegisterFunctionTool(name, description string, parameters any, fn JSONFunction, opts ...FunctionToolOption) error {
+	if r == nil {
+		return errors.New("textualopenai: nil ResponsesRequest")
+	}
+	if err := r.ToolRegistry().RegisterFunctionTool(name, description, parameters, fn, opts...); err != nil {
+		return err
+	}
+	r.syncTool(name)
+	return nil
 }
 
-// FunctionCallOutputs returns the tool output items produced by the embedded delegate.
+// FunctionCallOutputs returns the tool output items produced by the embedded delegate,
+// ordered by the OutputIndex of their function call (handlers may complete in any order).
+// Call WaitFunctionCalls first to make sure pending executions are collected.
 // The returned slice is a copy and safe to modify.
 func (r *ResponsesRequest) FunctionCallOutputs() []FunctionCallOutputItem {
 	r.mu.Lock()
@@ -522,6 +639,9 @@ func (r *ResponsesRequest) FunctionCallOutputs() []FunctionCallOutputItem {
 	}
 	out := make([]FunctionCallOutputItem, len(r.functionCallOutputs))
 	copy(out, r.functionCallOutputs)
+	sort.SliceStable(out, func(i, j int) bool {
+		return r.functionCallOutputOrder[out[i].CallID] < r.functionCallOutputOrder[out[j].CallID]
+	})
 	return out
 }
 
@@ -536,67 +656,11 @@ func (r *ResponsesRequest) ClearFunctionCallOutputs() {
 			delete(r.functionCallOutputIndexByCall, k)
 		}
 	}
-}
-
-func (r *ResponsesRequest) registerFunctionTool(tool FunctionTool, fn JSONFunction) error {
-	if r == nil {
-		return errors.New("textualopenai: nil ResponsesRequest")
-	}
-	name := strings.TrimSpace(tool.Name)
-	if name == "" {
-		return errors.New("textualopenai: function tool name is required")
-	}
-	if fn == nil {
-		return errors.New("textualopenai: function tool handler is required")
-	}
-
-	tool.Type = "function"
-	tool.Name = name
-
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	r.ensureFunctionDelegateLocked()
-
-	if _, exists := r.functionTools[name]; exists {
-		return fmt.Errorf("textualopenai: function tool already registered: %s", name)
-	}
-
-	r.functionTools[name] = registeredFunctionTool{
-		Tool:    tool,
-		Handler: fn,
-	}
-
-	r.upsertFunctionToolIntoRequestLocked(tool)
-	return nil
-}
-
-func (r *ResponsesRequest) upsertFunctionToolIntoRequestLocked(tool FunctionTool) {
-	if r.Tools == nil {
-		r.Tools = make([]any, 0, 1)
-	}
-	for i, t := range r.Tools {
-		if isSameFunctionTool(t, tool.Name) {
-			r.Tools[i] = tool
-			return
+	if r.functionCallOutputOrder != nil {
+		for k := range r.functionCallOutputOrder {
+			delete(r.functionCallOutputOrder, k)
 		}
 	}
-	r.Tools = append(r.Tools, tool)
-}
-
-func isSameFunctionTool(t any, name string) bool {
-	switch v := t.(type) {
-	case FunctionTool:
-		return v.Type == "function" && v.Name == name
-	case *FunctionTool:
-		return v != nil && v.Type == "function" && v.Name == name
-	case map[string]any:
-		typ, _ := v["type"].(string)
-		nm, _ := v["name"].(string)
-		return typ == "function" && nm == name
-	default:
-		return false
-	}
 }
 
 // processFunctionCalling inspects streaming events and, when function calling is used,
@@ -609,7 +673,7 @@ func (r *ResponsesRequest) processFunctionCalling(ctx context.Context, ev Stream
 	}
 	// If no tools are registered, skip quickly.
 	r.mu.Lock()
-	hasTools := len(r.functionTools) > 0
+	hasTools := r.toolRegistry.Len() > 0
 	r.mu.Unlock()
 	if !hasTools {
 		return
@@ -624,6 +688,13 @@ func (r *ResponsesRequest) processFunctionCalling(ctx context.Context, ev Stream
 
 	case FunctionCallArgumentsDone:
 		r.captureFunctionCallArgumentsDoneAndExecute(ctx, ev)
+
+	case CustomToolCallInputDelta:
+		// Freeform input is accumulated like function arguments.
+		r.captureFunctionCallArgumentsDelta(ev)
+
+	case CustomToolCallInputDone:
+		r.captureCustomToolCallInputDoneAndExecute(ctx, ev)
 	}
 }
 
@@ -636,7 +707,7 @@ func (r *ResponsesRequest) captureFunctionCallItem(ev StreamEvent) {
 	if err := json.Unmarshal(ev.Item, &item); err != nil {
 		return
 	}
-	if item.Type != "function_call" || strings.TrimSpace(item.ID) == "" {
+	if (item.Type != "function_call" && item.Type != "custom_tool_call") || strings.TrimSpace(item.ID) == "" {
 		return
 	}
 
@@ -699,7 +770,7 @@ func (r *ResponsesRequest) captureFunctionCallArgumentsDoneAndExecute(ctx contex
 	outputIndex := ev.OutputIndex
 
 	// Snapshot state and handler under lock.
-	var handler JSONFunction
+	var reg registeredFunctionTool
 	var observer FunctionCallObserver
 	var argsFromState string
 
@@ -721,10 +792,12 @@ func (r *ResponsesRequest) captureFunctionCallArgumentsDoneAndExecute(ctx contex
 		argsStr = argsFromState
 	}
 
-	if reg, ok := r.functionTools[name]; ok {
-		handler = reg.Handler
+	// Tools disabled for this request (and custom tools) are treated as unknown.
+	if registered, ok := r.toolRegistry.lookup(name); ok && registered.Custom == nil && r.toolEnabledLocked(registered) {
+		reg = registered
 	}
 	observer = r.functionCallObserver
+	red := r.redactor
 	r.mu.Unlock()
 
 	if name == "" {
@@ -740,7 +813,7 @@ func (r *ResponsesRequest) captureFunctionCallArgumentsDoneAndExecute(ctx contex
 		return
 	}
 
-	if handler == nil {
+	if reg.Handler == nil {
 		if observer != nil {
 			observer(ctx, FunctionCall{
 				ItemID:      itemID,
@@ -757,46 +830,106 @@ func (r *ResponsesRequest) captureFunctionCallArgumentsDoneAndExecute(ctx contex
 	if len(argsJSON) == 0 {
 		argsJSON = json.RawMessage(`{}`)
 	}
+	// The model only knows placeholders: handlers receive the original values.
+	argsJSON = restoreArguments(red, argsJSON)
 
-	outJSON, err := handler(ctx, argsJSON)
+	// Dispatch the handler to the worker pool: the stream keeps being consumed meanwhile.
+	r.scheduleFunctionCall(ctx, FunctionCall{
+		ItemID:      itemID,
+		CallID:      callID,
+		Name:        name,
+		Arguments:   argsJSON,
+		OutputIndex: outputIndex,
+	}, reg, observer)
+}
+
+// executeFunctionCall invokes the handler of a finalized function call and records its output.
+// It runs on the function call worker pool (see scheduleFunctionCall).
+func (r *ResponsesRequest) executeFunctionCall(ctx context.Context, call FunctionCall, reg registeredFunctionTool, observer FunctionCallObserver) {
+	// Validation, timeouts, panic recovery and output limits (see function_safeguards.go).
+	spanCtx, span := r.startToolSpan(ctx, call, reg)
+	outJSON, err := r.invokeFunctionHandler(spanCtx, &call, reg)
+	endToolSpan(span, err)
 
 	outItem := FunctionCallOutputItem{
 		Type:   "function_call_output",
-		CallID: callID,
+		CallID: call.CallID,
 		Output: string(outJSON),
 	}
 
 	if err != nil {
 		// Still provide a JSON payload the model can interpret as an error.
-		b, _ := json.Marshal(map[string]any{"error": err.Error()})
-		outItem.Output = string(b)
+		outItem.Output = functionErrorOutput(err)
 	} else if strings.TrimSpace(outItem.Output) == "" {
 		outItem.Output = "null"
 	}
+	if reg.Custom != nil {
+		// Custom tools exchange plain text.
+		outItem.Type = "custom_tool_call_output"
+		var text string
+		if err == nil && json.Unmarshal(outJSON, &text) == nil {
+			outItem.Output = text
+		}
+	}
 
 	// Persist the output for the next request (requires call_id).
-	if strings.TrimSpace(callID) != "" {
+	if strings.TrimSpace(call.CallID) != "" {
 		r.mu.Lock()
 		r.ensureFunctionDelegateLocked()
 
-		if idx, ok := r.functionCallOutputIndexByCall[callID]; ok && idx >= 0 && idx < len(r.functionCallOutputs) {
+		if idx, ok := r.functionCallOutputIndexByCall[call.CallID]; ok && idx >= 0 && idx < len(r.functionCallOutputs) {
 			r.functionCallOutputs[idx] = outItem
 		} else {
-			r.functionCallOutputIndexByCall[callID] = len(r.functionCallOutputs)
+			r.functionCallOutputIndexByCall[call.CallID] = len(r.functionCallOutputs)
 			r.functionCallOutputs = append(r.functionCallOutputs, outItem)
 		}
+		r.functionCallOutputOrder[call.CallID] = call.OutputIndex
 
 		r.mu.Unlock()
 	}
 
 	if observer != nil {
 		tmp := outItem
-		observer(ctx, FunctionCall{
-			ItemID:      itemID,
-			CallID:      callID,
-			Name:        name,
-			Arguments:   argsJSON,
-			OutputIndex: outputIndex,
-		}, &tmp, err)
+		observer(ctx, call, &tmp, err)
 	}
 }
+
+// MarshalJSON implements json.Marshaler.
+//
+// It serializes the request as-is, except for:
+//   - Input, redacted when a Redactor is attached (see SetRedactor),
+//   - Tools, resolved with EffectiveTools (enabled tools of the ToolRegistry),
+//   - Reasoning (and Thinking), mapped to the provider dialect.
+func (r *ResponsesRequest) MarshalJSON() ([]byte, error) {
+	type alias ResponsesRequest
+
+	r.mu.Lock()
+	red := r.redactor
+	r.mu.Unlock()
+
+	input := r.Input
+	if red != nil && input != nil {
+		redacted, err := red.RedactJSON(input)
+		if err != nil {
+			return nil, fmt.Errorf("textualopenai: redact input: %w", err)
+		}
+		input = redacted
+	}
+
+	reasoning, think := r.providerReasoning()
+
+	// The outer fields shadow the embedded ones.
+	return json.Marshal(struct {
+		*alias
+		Input     any              `json:"input,omitempty"`
+		Tools     []any            `json:"tools,omitempty"`
+		Reasoning *ReasoningConfig `json:"reasoning,omitempty"`
+		Think     any              `json:"think,omitempty"`
+	}{
+		alias:     (*alias)(r),
+		Input:     input,
+		Tools:     r.EffectiveTools(),
+		Reasoning: reasoning,
+		Think:     think,
+	})
+}
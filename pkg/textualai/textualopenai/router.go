@@ -0,0 +1,415 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// Usage sample :
+// router := textualopenai.NewRouter(
+// 	// Load balanced 3:1 between two OpenAI models, 2s to the first byte.
+// 	textualopenai.Route{Model: "openai:gpt-4.1", Pool: "openai", Weight: 3, LatencyBudget: 2 * time.Second},
+// 	textualopenai.Route{Model: "openai:gpt-4.1-mini", Pool: "openai", Weight: 1, LatencyBudget: 2 * time.Second},
+// 	// Then xAI, then a local model.
+// 	textualopenai.Route{Model: "xai:grok-4-1-fast"},
+// 	textualopenai.Route{Model: "ollama:qwen3:32b", FailoverOn: textualopenai.ErrorClassAll},
+// )
+// router.Requires = []models.Tag{models.TagTools}
+//
+// text, headerInfos, result, err := router.StreamAndTranscodeResponses(ctx, func(ctx context.Context, m models.Model) (textualopenai.StreamingRequest, error) {
+// 	req := textualopenai.NewResponsesRequest(ctx, m)
+// 	req.Input = "Summarize the incident report"
+// 	return req, nil
+// })
+// fmt.Println("served by", result.Model.ProviderName, result.Model.ID, "after", len(result.Attempts), "attempt(s)")
+
+var (
+	// ErrNoRouteAvailable is returned when every route was skipped or failed.
+	ErrNoRouteAvailable = errors.New("textualopenai: no route available")
+
+	// ErrLatencyBudgetExceeded is the error of an attempt exceeding its Route.LatencyBudget.
+	ErrLatencyBudgetExceeded = errors.New("textualopenai: latency budget exceeded")
+
+	// ErrRouteUnsupported is the error of a route skipped because its model lacks a required capability.
+	ErrRouteUnsupported = errors.New("textualopenai: model lacks a required capability")
+)
+
+// ErrorClass classifies the errors of a stream attempt (see ClassifyError).
+// Classes are bit flags: Route.FailoverOn combines them.
+type ErrorClass uint
+
+const (
+	ErrorClassRateLimit ErrorClass = 1 << iota // 429
+	ErrorClassServer                           // 5xx (including overloaded providers)
+	ErrorClassTimeout                          // latency budget or deadline exceeded
+	ErrorClassNetwork                          // connection errors
+	ErrorClassAuth                             // 401, 403
+	ErrorClassClient                           // other 4xx (invalid request, unknown model, ...)
+	ErrorClassOther                            // anything else
+
+	// ErrorClassAll fails over on any error.
+	ErrorClassAll = ErrorClassRateLimit | ErrorClassServer | ErrorClassTimeout | ErrorClassNetwork | ErrorClassAuth | ErrorClassClient | ErrorClassOther
+
+	// DefaultFailoverOn fails over on transient errors.
+	DefaultFailoverOn = ErrorClassRateLimit | ErrorClassServer | ErrorClassTimeout | ErrorClassNetwork
+)
+
+func (c ErrorClass) String() string {
+	names := []string{"rate_limit", "server", "timeout", "network", "auth", "client", "other"}
+	var parts []string
+	for i, name := range names {
+		if c&(1<<i) != 0 {
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// ClassifyError returns the class of an error returned by Client.Stream (0 for nil).
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return 0
+	}
+	status := 0
+	var streamErr *StreamError
+	var apiErr *APIError
+	var respErr *ResponseError
+	var netErr net.Error
+	var urlErr *url.Error
+	switch {
+	case errors.Is(err, ErrLatencyBudgetExceeded), errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.As(err, &streamErr):
+		status = streamErr.StatusCode
+	case errors.As(err, &apiErr):
+		status = apiErr.StatusCode
+	case errors.As(err, &respErr):
+		code := strings.ToLower(respErr.Code)
+		switch {
+		case strings.Contains(code, "rate_limit"):
+			return ErrorClassRateLimit
+		case strings.Contains(code, "server_error"), strings.Contains(code, "overloaded"):
+			return ErrorClassServer
+		}
+		return ErrorClassOther
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.As(err, &netErr), errors.As(err, &urlErr), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return ErrorClassNetwork
+	}
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrorClassRateLimit
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrorClassAuth
+	case status == http.StatusRequestTimeout:
+		return ErrorClassTimeout
+	case status >= 500:
+		return ErrorClassServer
+	case status >= 400:
+		return ErrorClassClient
+	}
+	return ErrorClassOther
+}
+
+// Route is a candidate model of a Router.
+type Route struct {
+	Model models.ModelString
+
+	// Pool load balances the consecutive routes sharing the same (non-empty) pool:
+	// they are tried in a random order weighted by Weight (<= 0 counts as 1).
+	Pool   string
+	Weight int
+
+	// FailoverOn lists the error classes moving to the next route (DefaultFailoverOn when 0).
+	// Other errors are returned immediately.
+	FailoverOn ErrorClass
+
+	// LatencyBudget bounds the time to the first byte of the stream (0 = unbounded).
+	LatencyBudget time.Duration
+
+	// Requires lists the capabilities the model must advertise (see models.Tag), in addition
+	// to Router.Requires. Routes lacking one are skipped.
+	Requires []models.Tag
+
+	// BaseURL overrides the provider base URL (see ClientFrom).
+	BaseURL string
+
+	// Configure (optional) customizes the client of the route (API key, authenticator, middleware, ...).
+	Configure func(c Client) Client
+}
+
+// RouteRequestFunc builds the request of an attempt for the model of a route.
+// ctx is the attempt context: the request must be bound to it.
+type RouteRequestFunc func(ctx context.Context, model models.Model) (StreamingRequest, error)
+
+// RouteAttempt describes a tried (or skipped) route.
+type RouteAttempt struct {
+	Model    models.ModelString
+	Err      error // nil for the route serving the response
+	Class    ErrorClass
+	Skipped  bool // capability mismatch
+	Duration time.Duration
+}
+
+// RouteResult tells which model served the response.
+type RouteResult struct {
+	Model    models.Model // zero when no route succeeded
+	Route    Route
+	Attempts []RouteAttempt
+}
+
+// Router streams a request on the first available route: the next route is tried when an
+// attempt fails before any output has streamed (refused request, connection error,
+// latency budget exceeded). Once the first byte is received, the route is kept.
+//
+// A Router is safe for concurrent use.
+type Router struct {
+	Routes []Route
+
+	// Requires lists the capabilities required from every route (e.g. models.TagTools).
+	Requires []models.Tag
+
+	// OnAttempt (optional) is called after each tried or skipped route.
+	OnAttempt func(RouteAttempt)
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewRouter returns a Router trying routes in order.
+func NewRouter(routes ...Route) *Router {
+	return &Router{Routes: routes}
+}
+
+// WithSeed makes the load balancing deterministic (tests).
+func (rt *Router) WithSeed(seed uint64) *Router {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.rand = rand.New(rand.NewPCG(seed, seed))
+	return rt
+}
+
+// plan returns the routes in the order they will be tried.
+func (rt *Router) plan() []Route {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	var out []Route
+	for i := 0; i < len(rt.Routes); {
+		j := i + 1
+		if pool := rt.Routes[i].Pool; pool != "" {
+			for j < len(rt.Routes) && rt.Routes[j].Pool == pool {
+				j++
+			}
+		}
+		out = append(out, rt.weightedOrderLocked(rt.Routes[i:j])...)
+		i = j
+	}
+	return out
+}
+
+// weightedOrderLocked returns routes in a random order weighted by Route.Weight.
+func (rt *Router) weightedOrderLocked(routes []Route) []Route {
+	if len(routes) < 2 {
+		return routes
+	}
+	rest := append([]Route(nil), routes...)
+	out := make([]Route, 0, len(rest))
+	for len(rest) > 0 {
+		total := 0
+		for _, r := range rest {
+			total += max(r.Weight, 1)
+		}
+		var n int
+		if rt.rand != nil {
+			n = rt.rand.IntN(total)
+		} else {
+			n = rand.IntN(total)
+		}
+		k := 0
+		for ; k < len(rest)-1; k++ {
+			if n -= max(rest[k].Weight, 1); n < 0 {
+				break
+			}
+		}
+		out = append(out, rest[k])
+		rest = append(rest[:k], rest[k+1:]...)
+	}
+	return out
+}
+
+// Stream opens the stream of the request built by build on the first available route.
+// The returned request is the one of the serving route; callers must close resp.Body.
+func (rt *Router) Stream(ctx context.Context, build RouteRequestFunc) (*http.Response, StreamingRequest, RouteResult, error) {
+	var result RouteResult
+	var lastErr error
+	record := func(a RouteAttempt) {
+		result.Attempts = append(result.Attempts, a)
+		if rt.OnAttempt != nil {
+			rt.OnAttempt(a)
+		}
+	}
+
+	for _, route := range rt.plan() {
+		start := time.Now()
+		model, err := route.Model.Model()
+		if err != nil {
+			lastErr = err
+			record(RouteAttempt{Model: route.Model, Err: err, Class: ErrorClassClient, Skipped: true})
+			continue
+		}
+		if missing := missingCapability(model, append(append([]models.Tag(nil), rt.Requires...), route.Requires...)); missing != "" {
+			lastErr = fmt.Errorf("%w: %s does not support %s", ErrRouteUnsupported, route.Model, missing)
+			record(RouteAttempt{Model: route.Model, Err: lastErr, Skipped: true})
+			continue
+		}
+
+		resp, req, err := rt.attempt(ctx, route, model, build)
+		if err == nil {
+			record(RouteAttempt{Model: route.Model, Duration: time.Since(start)})
+			result.Model, result.Route = model, route
+			return resp, req, result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, nil, result, err
+		}
+		class := ClassifyError(err)
+		record(RouteAttempt{Model: route.Model, Err: err, Class: class, Duration: time.Since(start)})
+		failoverOn := route.FailoverOn
+		if failoverOn == 0 {
+			failoverOn = DefaultFailoverOn
+		}
+		if class&failoverOn == 0 {
+			return nil, nil, result, err
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		return nil, nil, result, ErrNoRouteAvailable
+	}
+	return nil, nil, result, fmt.Errorf("%w: %w", ErrNoRouteAvailable, lastErr)
+}
+
+// attempt opens the stream on a route and waits for its first byte.
+func (rt *Router) attempt(ctx context.Context, route Route, model models.Model, build RouteRequestFunc) (*http.Response, StreamingRequest, error) {
+	client, err := ClientFrom(route.BaseURL, model, ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if route.Configure != nil {
+		client = route.Configure(client)
+	}
+
+	attemptCtx, cancel := context.WithCancel(ctx)
+	req, err := build(attemptCtx, model)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	var timer *time.Timer
+	exceeded := make(chan struct{})
+	if route.LatencyBudget > 0 {
+		timer = time.AfterFunc(route.LatencyBudget, func() {
+			close(exceeded)
+			cancel()
+		})
+	}
+	resp, err := client.Stream(req)
+	var body *bufio.Reader
+	if err == nil {
+		// The route is kept once the first byte is received.
+		body = bufio.NewReader(resp.Body)
+		if _, peekErr := body.Peek(1); peekErr != nil {
+			_ = resp.Body.Close()
+			err = fmt.Errorf("textualopenai: read stream: %w", peekErr)
+		}
+	}
+	if timer != nil && !timer.Stop() {
+		<-exceeded
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		err = fmt.Errorf("%w: %s on %s", ErrLatencyBudgetExceeded, route.LatencyBudget, route.Model)
+	}
+	if err != nil {
+		cancel()
+		endCall(req, err) // no-op when Stream already ended the call
+		return nil, nil, err
+	}
+	resp.Body = &routedBody{Reader: body, body: resp.Body, cancel: cancel}
+	return resp, req, nil
+}
+
+// routedBody releases the attempt context when the body is closed.
+type routedBody struct {
+	io.Reader
+	body   io.Closer
+	cancel context.CancelFunc
+}
+
+func (b *routedBody) Close() error {
+	defer b.cancel()
+	return b.body.Close()
+}
+
+// StreamAndTranscodeResponses behaves like Client.StreamAndTranscodeResponses on the first available route.
+func (rt *Router) StreamAndTranscodeResponses(ctx context.Context, build RouteRequestFunc) (string, HeaderInfos, RouteResult, error) {
+	resp, req, result, err := rt.Stream(ctx, build)
+	if err != nil {
+		return "", HeaderInfos{}, result, err
+	}
+	text, headerInfos, err := transcodeResponse(ctx, req, resp)
+	return text, headerInfos, result, err
+}
+
+// missingCapability returns the first tag of required the model does not advertise.
+func missingCapability(m models.Model, required []models.Tag) models.Tag {
+	for _, tag := range required {
+		var ok bool
+		switch tag {
+		case models.TagTools:
+			ok = m.SupportsTools()
+		case models.TagVision:
+			ok = m.SupportsVision()
+		case models.TagThinking:
+			ok = m.SupportsThinking()
+		case models.TagEmbedding:
+			ok = m.SupportsEmbedding()
+		default:
+			for _, t := range m.Tags {
+				ok = ok || strings.EqualFold(string(t), string(tag))
+			}
+		}
+		if !ok {
+			return tag
+		}
+	}
+	return ""
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/instrumentation"
+	"github.com/benoit-pereira-da-silva/textualai/pkg/textualai/models"
+)
+
+// routeServer answers with status (streaming a response on 200) after delay; calls counts the requests.
+func routeServer(t *testing.T, status int, delay time.Duration, calls *atomic.Int32) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body) // lets the server notice a cancelled request
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		if status != http.StatusOK {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			_, _ = fmt.Fprintf(w, `{"error":{"message":"http %d","type":"test"}}`, status)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(sseEvents(resumableEvents...)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func withTestKey(c Client) Client { return c.WithApiKey("test-key") }
+
+func buildRouteRequest(ctx context.Context, m models.Model) (StreamingRequest, error) {
+	req := NewResponsesRequest(ctx, m)
+	req.Input = "hi"
+	_ = req.AddListeners(StringCarrierFrom, OutputTextDelta)
+	return req, nil
+}
+
+func TestRouterFailsOverOnRateLimit(t *testing.T) {
+	var first, second atomic.Int32
+	router := NewRouter(
+		Route{Model: "openai:gpt-4.1", BaseURL: routeServer(t, http.StatusTooManyRequests, 0, &first), Configure: withTestKey},
+		Route{Model: "openai:gpt-4.1-mini", BaseURL: routeServer(t, http.StatusOK, 0, &second), Configure: withTestKey},
+	)
+	text, _, result, err := router.StreamAndTranscodeResponses(context.Background(), buildRouteRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Hello world" || result.Model.ID != "gpt-4.1-mini" {
+		t.Fatalf("text = %q, served by %q", text, result.Model.ID)
+	}
+	if len(result.Attempts) != 2 || result.Attempts[0].Class != ErrorClassRateLimit || result.Attempts[1].Err != nil {
+		t.Fatalf("attempts = %+v", result.Attempts)
+	}
+}
+
+func TestRouterDoesNotFailOverOnClientError(t *testing.T) {
+	var first, second atomic.Int32
+	router := NewRouter(
+		Route{Model: "openai:gpt-4.1", BaseURL: routeServer(t, http.StatusBadRequest, 0, &first), Configure: withTestKey},
+		Route{Model: "openai:gpt-4.1-mini", BaseURL: routeServer(t, http.StatusOK, 0, &second), Configure: withTestKey},
+	)
+	_, _, result, err := router.StreamAndTranscodeResponses(context.Background(), buildRouteRequest)
+	if err == nil || ClassifyError(err) != ErrorClassClient {
+		t.Fatalf("err = %v (%s)", err, ClassifyError(err))
+	}
+	if second.Load() != 0 || len(result.Attempts) != 1 {
+		t.Fatalf("second route called %d times, attempts = %+v", second.Load(), result.Attempts)
+	}
+
+	// FailoverOn widens the classes moving to the next route.
+	router.Routes[0].FailoverOn = ErrorClassAll
+	if _, _, _, err := router.StreamAndTranscodeResponses(context.Background(), buildRouteRequest); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRouterLatencyBudget(t *testing.T) {
+	var slow, fast atomic.Int32
+	router := NewRouter(
+		Route{Model: "openai:gpt-4.1", BaseURL: routeServer(t, http.StatusOK, time.Second, &slow), Configure: withTestKey, LatencyBudget: 20 * time.Millisecond},
+		Route{Model: "openai:gpt-4.1-mini", BaseURL: routeServer(t, http.StatusOK, 0, &fast), Configure: withTestKey},
+	)
+	start := time.Now()
+	text, _, result, err := router.StreamAndTranscodeResponses(context.Background(), buildRouteRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("failover took %s", elapsed)
+	}
+	a := result.Attempts[0]
+	if text != "Hello world" || a.Class != ErrorClassTimeout || !errors.Is(a.Err, ErrLatencyBudgetExceeded) {
+		t.Fatalf("text = %q, first attempt = %+v", text, a)
+	}
+}
+
+func TestRouterEndsCallsOfAbandonedRoutes(t *testing.T) {
+	// Headers are sent at once: the attempts fail while reading the body.
+	bodyServer := func(stall bool) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			if stall {
+				<-r.Context().Done()
+			}
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+	mem := instrumentation.NewInMemory()
+	configure := func(c Client) Client { return withTestKey(c).WithInstrumentation(mem) }
+	var ok atomic.Int32
+	router := NewRouter(
+		Route{Model: "openai:gpt-4.1", BaseURL: bodyServer(true), Configure: configure, LatencyBudget: 20 * time.Millisecond},
+		Route{Model: "openai:gpt-4.1", BaseURL: bodyServer(false), Configure: configure, FailoverOn: ErrorClassAll},
+		Route{Model: "openai:gpt-4.1-mini", BaseURL: routeServer(t, http.StatusOK, 0, &ok), Configure: configure},
+	)
+	if _, _, _, err := router.StreamAndTranscodeResponses(context.Background(), buildRouteRequest); err != nil {
+		t.Fatal(err)
+	}
+	spans := mem.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("%d spans, want 3", len(spans))
+	}
+	for i, s := range spans {
+		if s.End.IsZero() || (s.Err == nil) != (i == 2) {
+			t.Errorf("span %d: end = %v, err = %v", i, s.End, s.Err)
+		}
+	}
+	if !errors.Is(spans[0].Err, ErrLatencyBudgetExceeded) {
+		t.Errorf("span 0: err = %v", spans[0].Err)
+	}
+}
+
+func TestRouterSkipsMissingCapability(t *testing.T) {
+	var embedding, chat atomic.Int32
+	router := NewRouter(
+		Route{Model: "openai:text-embedding-3-small", BaseURL: routeServer(t, http.StatusOK, 0, &embedding), Configure: withTestKey},
+		Route{Model: "openai:gpt-4.1", BaseURL: routeServer(t, http.StatusOK, 0, &chat), Configure: withTestKey},
+	)
+	router.Requires = []models.Tag{models.TagTools}
+	_, _, result, err := router.StreamAndTranscodeResponses(context.Background(), buildRouteRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := result.Attempts[0]
+	if embedding.Load() != 0 || !a.Skipped || !errors.Is(a.Err, ErrRouteUnsupported) || result.Model.ID != "gpt-4.1" {
+		t.Fatalf("attempts = %+v, served by %q", result.Attempts, result.Model.ID)
+	}
+
+	// Every route skipped.
+	router.Routes = router.Routes[:1]
+	if _, _, _, err := router.StreamAndTranscodeResponses(context.Background(), buildRouteRequest); !errors.Is(err, ErrNoRouteAvailable) {
+		t.Fatalf("err = %v, want ErrNoRouteAvailable", err)
+	}
+}
+
+func TestRouterWithSeed(t *testing.T) {
+	routes := []Route{
+		{Model: "openai:gpt-4.1", Pool: "openai", Weight: 3},
+		{Model: "openai:gpt-4.1-mini", Pool: "openai", Weight: 1},
+		{Model: "xai:grok-4-1-fast"},
+	}
+	plans := func(rt *Router) (out []string, firsts map[models.ModelString]int) {
+		firsts = map[models.ModelString]int{}
+		for i := 0; i < 200; i++ {
+			plan := rt.plan()
+			if len(plan) != 3 || plan[2].Model != "xai:grok-4-1-fast" {
+				t.Fatalf("plan = %+v", plan)
+			}
+			firsts[plan[0].Model]++
+			out = append(out, string(plan[0].Model))
+		}
+		return out, firsts
+	}
+	a, firsts := plans(NewRouter(routes...).WithSeed(42))
+	b, _ := plans(NewRouter(routes...).WithSeed(42))
+	if fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Fatal("the same seed gave different plans")
+	}
+	// Weighted 3:1.
+	if n := firsts["openai:gpt-4.1"]; n < 120 || n > 180 {
+		t.Fatalf("gpt-4.1 first in %d of 200 plans, want about 150", n)
+	}
+}
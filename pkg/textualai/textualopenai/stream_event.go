@@ -68,6 +68,10 @@ const (
 	// ResponseFailed indicates that response generation failed.
 	ResponseFailed EventType = "response.failed"
 
+	// ResponseIncomplete indicates that the response finished before
+	// completion (e.g. max_output_tokens reached).
+	ResponseIncomplete EventType = "response.incomplete"
+
 	// ─────────────────────────────────────────────────────────────
 	// Output item events
 	// ─────────────────────────────────────────────────────────────
@@ -109,6 +113,14 @@ const (
 	// has been streamed.
 	ReasoningSummaryTextDone EventType = "response.reasoning_summary_text.done"
 
+	// ReasoningTextDelta contains an incremental chunk of the raw reasoning
+	// text (open-weight reasoning models, e.g. gpt-oss served by Ollama).
+	// The `Delta` field will be populated.
+	ReasoningTextDelta EventType = "response.reasoning_text.delta"
+
+	// ReasoningTextDone indicates that all reasoning text has been streamed.
+	ReasoningTextDone EventType = "response.reasoning_text.done"
+
 	// ReasoningSummaryPartAdded signals that a new reasoning summary part
 	// has been added (for multi-part summaries).
 	ReasoningSummaryPartAdded EventType = "response.reasoning_summary_part.added"
@@ -175,6 +187,35 @@ const (
 	// tool has completed.
 	FileSearchCallCompleted EventType = "response.file_search_call.completed"
 
+	// ─────────────────────────────────────────────────────────────
+	// Web search events
+	// ─────────────────────────────────────────────────────────────
+
+	// WebSearchCallInProgress indicates a web search tool invocation has started.
+	WebSearchCallInProgress EventType = "response.web_search_call.in_progress"
+
+	// WebSearchCallSearching indicates that the web search is running.
+	WebSearchCallSearching EventType = "response.web_search_call.searching"
+
+	// WebSearchCallCompleted indicates the web search has completed.
+	WebSearchCallCompleted EventType = "response.web_search_call.completed"
+
+	// ─────────────────────────────────────────────────────────────
+	// Image generation events
+	// ─────────────────────────────────────────────────────────────
+
+	// ImageGenerationCallInProgress indicates an image generation has started.
+	ImageGenerationCallInProgress EventType = "response.image_generation_call.in_progress"
+
+	// ImageGenerationCallGenerating indicates the image is being generated.
+	ImageGenerationCallGenerating EventType = "response.image_generation_call.generating"
+
+	// ImageGenerationCallPartialImage streams a partial image (PartialImageB64).
+	ImageGenerationCallPartialImage EventType = "response.image_generation_call.partial_image"
+
+	// ImageGenerationCallCompleted indicates the image generation has completed.
+	ImageGenerationCallCompleted EventType = "response.image_generation_call.completed"
+
 	// ─────────────────────────────────────────────────────────────
 	// Refusal & error events
 	// ─────────────────────────────────────────────────────────────
@@ -211,7 +252,8 @@ Field semantics:
   - Message: Error or informational message
   - Item: Structured output item payload (output_item.* events)
   - Response: Full response payload (response.* lifecycle events)
-  - Annotation: Annotation payload (output_text.annotation.added)
+  - Annotation: Annotation payload (output_text.annotation.added), see DecodeAnnotation
+  - PartialImageB64 / PartialImageIndex: partial image (image_generation_call.partial_image)
 */
 type StreamEvent struct {
 	Type EventType `json:"type"`
@@ -233,6 +275,9 @@ type StreamEvent struct {
 	Name      string `json:"name,omitempty"`
 	Arguments string `json:"arguments,omitempty"`
 
+	// Custom tool payload (finalized freeform input)
+	Input string `json:"input,omitempty"`
+
 	// Code interpreter / error payload
 	Code    string `json:"code,omitempty"`
 	Message string `json:"message,omitempty"`
@@ -242,16 +287,21 @@ type StreamEvent struct {
 	Item       json.RawMessage `json:"item,omitempty"`
 	Response   json.RawMessage `json:"response,omitempty"`
 	Annotation json.RawMessage `json:"annotation,omitempty"`
+
+	// Image generation payload
+	PartialImageB64   string `json:"partial_image_b64,omitempty"`
+	PartialImageIndex int    `json:"partial_image_index,omitempty"`
 }
 
 /*
 IsTerminal returns true if this event represents a terminal state
-for the stream (completed, failed, or error).
+for the stream (completed, failed, incomplete or error).
 */
 func (s StreamEvent) IsTerminal() bool {
 	switch s.Type {
 	case ResponseCompleted,
 		ResponseFailed,
+		ResponseIncomplete,
 		Error:
 		return true
 	default:
@@ -276,7 +326,7 @@ func (s StreamEvent) Summary() string {
 	switch s.Type {
 	case OutputTextDelta:
 		sum = fmt.Sprintf("\ntext delta: %s", s.Delta)
-	case ReasoningSummaryTextDelta:
+	case ReasoningSummaryTextDelta, ReasoningTextDelta:
 		sum = fmt.Sprintf("\nreasoning delta: %s", s.Delta)
 	case TextDone:
 		sum = fmt.Sprintf("\ntext: %s", s.Text)
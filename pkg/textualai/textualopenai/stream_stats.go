@@ -0,0 +1,270 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptrace"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Usage sample :
+// _, headerInfos, err := client.StreamAndTranscodeResponses(ctx, req)
+// if s := headerInfos.Stats; s != nil {
+// 	fmt.Printf("ttft %s, %.1f tokens/s, %d bytes\n", s.TimeToFirstToken, s.TokensPerSecond, s.BytesReceived)
+// }
+//
+// // Requests streamed with Client.Stream directly.
+// stats := req.StreamStats()
+
+// StreamStats measures a streamed response. Durations are relative to the start of the request.
+type StreamStats struct {
+	ConnectTime      time.Duration     // connection obtained (DNS, TCP and TLS, ~0 when reused)
+	TimeToFirstByte  time.Duration     // first byte of the response (headers)
+	TimeToFirstToken time.Duration     // first output text delta
+	TotalDuration    time.Duration     // end of the stream
+	BytesReceived    int64             // body bytes
+	Events           map[EventType]int // event counts (after translation for native APIs)
+	InputTokens      int               // reported usage
+	OutputTokens     int               // reported usage (reasoning included)
+
+	// TokensPerSecond is the output throughput, from the first delta (text, reasoning or
+	// function arguments) to the end of the stream. It is 0 without reported usage.
+	TokensPerSecond float64
+}
+
+// TotalEvents returns the number of events.
+func (s StreamStats) TotalEvents() int {
+	n := 0
+	for _, c := range s.Events {
+		n += c
+	}
+	return n
+}
+
+// ToString returns a human-readable representation of the stats, one information per line.
+func (s StreamStats) ToString() string {
+	var b strings.Builder
+	ms := func(label string, d time.Duration) {
+		if d > 0 {
+			fmt.Fprintf(&b, "%s: %.1f ms\n", label, float64(d)/float64(time.Millisecond))
+		}
+	}
+	ms("Connect Time", s.ConnectTime)
+	ms("Time To First Byte", s.TimeToFirstByte)
+	ms("Time To First Token", s.TimeToFirstToken)
+	ms("Total Duration", s.TotalDuration)
+	if s.BytesReceived > 0 {
+		fmt.Fprintf(&b, "Bytes Received: %d\n", s.BytesReceived)
+	}
+	if s.OutputTokens > 0 {
+		fmt.Fprintf(&b, "Tokens: %d in, %d out\n", s.InputTokens, s.OutputTokens)
+	}
+	if s.TokensPerSecond > 0 {
+		fmt.Fprintf(&b, "Tokens Per Second: %.1f\n", s.TokensPerSecond)
+	}
+	if len(s.Events) > 0 {
+		types := make([]string, 0, len(s.Events))
+		for t := range s.Events {
+			types = append(types, string(t))
+		}
+		sort.Strings(types)
+		fmt.Fprintf(&b, "Events: %d\n", s.TotalEvents())
+		for _, t := range types {
+			fmt.Fprintf(&b, "  %s: %d\n", t, s.Events[EventType(t)])
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// MarshalJSON encodes the durations in milliseconds.
+func (s StreamStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ConnectTimeMS      int64             `json:"connect_time_ms,omitempty"`
+		TimeToFirstByteMS  int64             `json:"time_to_first_byte_ms,omitempty"`
+		TimeToFirstTokenMS int64             `json:"time_to_first_token_ms,omitempty"`
+		TotalDurationMS    int64             `json:"total_duration_ms,omitempty"`
+		BytesReceived      int64             `json:"bytes_received,omitempty"`
+		Events             map[EventType]int `json:"events,omitempty"`
+		InputTokens        int               `json:"input_tokens,omitempty"`
+		OutputTokens       int               `json:"output_tokens,omitempty"`
+		TokensPerSecond    float64           `json:"tokens_per_second,omitempty"`
+	}{
+		ConnectTimeMS:      durationToMilliseconds(s.ConnectTime),
+		TimeToFirstByteMS:  durationToMilliseconds(s.TimeToFirstByte),
+		TimeToFirstTokenMS: durationToMilliseconds(s.TimeToFirstToken),
+		TotalDurationMS:    durationToMilliseconds(s.TotalDuration),
+		BytesReceived:      s.BytesReceived,
+		Events:             s.Events,
+		InputTokens:        s.InputTokens,
+		OutputTokens:       s.OutputTokens,
+		TokensPerSecond:    s.TokensPerSecond,
+	})
+}
+
+// StreamStats returns the stats of the last stream opened with Client.Stream
+// (zero before). They are complete once the stream is consumed.
+func (r *ResponsesRequest) StreamStats() StreamStats {
+	r.mu.Lock()
+	st := r.stats
+	r.mu.Unlock()
+	if st == nil {
+		return StreamStats{}
+	}
+	return st.snapshot()
+}
+
+// streamStats collects the StreamStats of a stream.
+type streamStats struct {
+	mu         sync.Mutex
+	start      time.Time
+	connect    time.Duration
+	firstByte  time.Duration
+	firstDelta time.Duration // any delta, for the throughput
+	firstToken time.Duration
+	end        time.Duration
+	bytes      int64
+	events     map[EventType]int
+	usage      Usage
+}
+
+// beginStreamStats attaches a new collector to r (nil when r does not embed *ResponsesRequest)
+// and returns ctx traced by the collector.
+func beginStreamStats(ctx context.Context, r Requestable) (context.Context, *streamStats) {
+	b, ok := r.(baseRequest)
+	if !ok {
+		return ctx, nil
+	}
+	st := &streamStats{start: time.Now(), events: map[EventType]int{}}
+	req := b.base()
+	req.mu.Lock()
+	req.stats = st
+	req.mu.Unlock()
+
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GotConn: func(httptrace.GotConnInfo) {
+			st.mu.Lock()
+			defer st.mu.Unlock()
+			if st.connect == 0 {
+				st.connect = time.Since(st.start)
+			}
+		},
+		GotFirstResponseByte: func() {
+			st.mu.Lock()
+			defer st.mu.Unlock()
+			if st.firstByte == 0 {
+				st.firstByte = time.Since(st.start)
+			}
+		},
+	}), st
+}
+
+// streamStatsOf returns the collector of r (nil if none).
+func streamStatsOf(r Requestable) *streamStats {
+	if b, ok := r.(baseRequest); ok {
+		req := b.base()
+		req.mu.Lock()
+		defer req.mu.Unlock()
+		return req.stats
+	}
+	return nil
+}
+
+// countBody counts the bytes read from body and marks the end of the stream at EOF. s may be nil.
+func (s *streamStats) countBody(body io.ReadCloser) io.ReadCloser {
+	if s == nil || body == nil {
+		return body
+	}
+	return &countingBody{ReadCloser: body, stats: s}
+}
+
+type countingBody struct {
+	io.ReadCloser
+	stats *streamStats
+}
+
+func (c *countingBody) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.stats.mu.Lock()
+	c.stats.bytes += int64(n)
+	if err == io.EOF {
+		c.stats.end = time.Since(c.stats.start)
+	}
+	c.stats.mu.Unlock()
+	return n, err
+}
+
+// observeStats updates the stream stats with an event.
+func (r *ResponsesRequest) observeStats(ev StreamEvent) {
+	r.mu.Lock()
+	st := r.stats
+	r.mu.Unlock()
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.events[ev.Type]++
+	elapsed := time.Since(st.start)
+	switch ev.Type {
+	case OutputTextDelta, ReasoningSummaryTextDelta, ReasoningTextDelta, RefusalDelta, FunctionCallArgumentsDelta:
+		if st.firstDelta == 0 {
+			st.firstDelta = elapsed
+		}
+	}
+	if ev.IsTextDelta() && st.firstToken == 0 {
+		st.firstToken = elapsed
+	}
+	if ev.IsTerminal() {
+		var resp Response
+		if json.Unmarshal(ev.Response, &resp) == nil {
+			if u, ok := resp.TokenUsage(); ok {
+				st.usage = u
+			}
+		}
+		st.end = elapsed
+	}
+}
+
+func (s *streamStats) snapshot() StreamStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := StreamStats{
+		ConnectTime:      s.connect,
+		TimeToFirstByte:  s.firstByte,
+		TimeToFirstToken: s.firstToken,
+		TotalDuration:    s.end,
+		BytesReceived:    s.bytes,
+		Events:           make(map[EventType]int, len(s.events)),
+		InputTokens:      s.usage.InputTokens,
+		OutputTokens:     s.usage.OutputTokens,
+	}
+	for t, n := range s.events {
+		out.Events[t] = n
+	}
+	if out.TotalDuration == 0 {
+		out.TotalDuration = time.Since(s.start)
+	}
+	if generation := out.TotalDuration - s.firstDelta; out.OutputTokens > 0 && generation > 0 {
+		out.TokensPerSecond = float64(out.OutputTokens) / generation.Seconds()
+	}
+	return out
+}
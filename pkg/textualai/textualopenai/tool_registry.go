@@ -0,0 +1,592 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NamespaceSeparator joins a namespace and a tool name ("crm__create_ticket").
+// Function tool names only allow [a-zA-Z0-9_-], so dots cannot be used.
+const NamespaceSeparator = "__"
+
+// ErrFunctionToolNotFound is returned by ToolRegistry.Call for unknown tools.
+var ErrFunctionToolNotFound = errors.New("textualopenai: function tool not found")
+
+// Usage sample :
+// tools := textualopenai.NewToolRegistry()
+// _ = tools.RegisterFunctionTool("get_time", "Get the current time", schema, getTimeHandler)
+//
+// crm := tools.Namespace("crm") // tools are registered as "crm__<name>"
+// _ = crm.RegisterFunctionTool("create_ticket", "Create a ticket", ticketSchema, createTicket,
+// 	textualopenai.WithToolGroups("writes"), textualopenai.WithRequiresApproval())
+//
+// // For every turn (from any goroutine):
+// req := textualopenai.NewResponsesRequest(ctx, model)
+// req.SetToolRegistry(tools)
+// req.DisableToolGroups("writes") // read-only turn
+
+// ToolRegistry is a reusable, concurrency-safe set of function tools and their handlers.
+//
+// A registry can be attached to any number of ResponsesRequest (see SetToolRegistry).
+// Namespace returns a view of the same registry that prefixes tool names, so independent
+// components can register tools without name collisions.
+type ToolRegistry struct {
+	store  *toolStore
+	prefix string
+}
+
+// toolStore is the storage shared by a registry and its namespace views.
+type toolStore struct {
+	mu    sync.RWMutex
+	tools map[string]registeredFunctionTool
+}
+
+// NewToolRegistry returns an empty ToolRegistry.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{
+		store: &toolStore{tools: make(map[string]registeredFunctionTool)},
+	}
+}
+
+// Namespace returns a view of the registry whose tool names are prefixed by ns + NamespaceSeparator.
+// Namespaces can be nested.
+func (t *ToolRegistry) Namespace(ns string) *ToolRegistry {
+	return &ToolRegistry{
+		store:  t.store,
+		prefix: t.prefix + strings.TrimSpace(ns) + NamespaceSeparator,
+	}
+}
+
+// QualifiedName returns the full tool name (including the namespace prefix) of name.
+func (t *ToolRegistry) QualifiedName(name string) string {
+	return t.prefix + strings.TrimSpace(name)
+}
+
+// RegisterFunctionTool registers a "function" tool bound to a JSON-only handler.
+// See ResponsesRequest.RegisterFunctionTool for the parameters semantics.
+func (t *ToolRegistry) RegisterFunctionTool(name, description string, parameters any, fn JSONFunction, opts ...FunctionToolOption) error {
+	return t.Register(FunctionTool{
+		Type:        "function",
+		Name:        name,
+		Description: description,
+		Parameters:  parameters,
+	}, fn, opts...)
+}
+
+// RegisterFunctionToolStrict is the same as RegisterFunctionTool but sets `strict` on the definition.
+func (t *ToolRegistry) RegisterFunctionToolStrict(name, description string, parameters any, strict bool, fn JSONFunction, opts ...FunctionToolOption) error {
+	return t.Register(FunctionTool{
+		Type:        "function",
+		Name:        name,
+		Description: description,
+		Parameters:  parameters,
+		Strict:      BoolPtr(strict),
+	}, fn, opts...)
+}
+
+// RegisterTypedTool registers a function tool whose handler uses typed Go arguments and results.
+func RegisterTypedTool[A any, R any](t *ToolRegistry, name, description string, parameters any, fn func(context.Context, A) (R, error), opts ...FunctionToolOption) error {
+	return t.RegisterFunctionTool(name, description, parameters, typedJSONFunction(fn), opts...)
+}
+
+// Register registers a function tool definition and its handler.
+// The tool name is prefixed with the registry namespace (if any).
+func (t *ToolRegistry) Register(tool FunctionTool, fn JSONFunction, opts ...FunctionToolOption) error {
+	if t == nil || t.store == nil {
+		return errors.New("textualopenai: nil ToolRegistry")
+	}
+	name := strings.TrimSpace(tool.Name)
+	if name == "" {
+		return errors.New("textualopenai: function tool name is required")
+	}
+	if fn == nil {
+		return errors.New("textualopenai: function tool handler is required")
+	}
+
+	tool.Type = "function"
+	tool.Name = t.prefix + name
+
+	schema, err := compileJSONSchema(tool.Parameters)
+	if err != nil {
+		return fmt.Errorf("textualopenai: function tool %s: %w", tool.Name, err)
+	}
+	return t.insert(registeredFunctionTool{
+		Tool:    tool,
+		Handler: fn,
+		Schema:  schema,
+		Options: applyFunctionToolOptions(opts),
+	})
+}
+
+// insert adds a tool under its full name.
+func (t *ToolRegistry) insert(reg registeredFunctionTool) error {
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	if _, exists := t.store.tools[reg.Tool.Name]; exists {
+		return fmt.Errorf("textualopenai: function tool already registered: %s", reg.Tool.Name)
+	}
+	t.store.tools[reg.Tool.Name] = reg
+	return nil
+}
+
+func applyFunctionToolOptions(opts []FunctionToolOption) FunctionToolOptions {
+	var options FunctionToolOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	return options
+}
+
+// Unregister removes a tool. name is relative to the registry namespace.
+func (t *ToolRegistry) Unregister(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("textualopenai: function tool name is required")
+	}
+	full := t.prefix + name
+
+	t.store.mu.Lock()
+	defer t.store.mu.Unlock()
+
+	if _, ok := t.store.tools[full]; !ok {
+		return fmt.Errorf("textualopenai: function tool not found: %s", full)
+	}
+	delete(t.store.tools, full)
+	return nil
+}
+
+// Len returns the number of tools visible from this registry (or namespace view).
+func (t *ToolRegistry) Len() int {
+	if t == nil || t.store == nil {
+		return 0
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	if t.prefix == "" {
+		return len(t.store.tools)
+	}
+	n := 0
+	for name := range t.store.tools {
+		if strings.HasPrefix(name, t.prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// Names returns the full names of the visible tools, sorted.
+func (t *ToolRegistry) Names() []string {
+	var names []string
+	for _, reg := range t.snapshot() {
+		names = append(names, reg.Tool.Name)
+	}
+	return names
+}
+
+// Definitions returns the visible function tool definitions (custom tools excluded), sorted by name.
+// They can be sent as-is in the `tools` field of any OpenAI-compatible request,
+// or mapped to another provider format.
+func (t *ToolRegistry) Definitions() []FunctionTool {
+	snapshot := t.snapshot()
+	defs := make([]FunctionTool, 0, len(snapshot))
+	for _, reg := range snapshot {
+		if reg.Custom == nil {
+			defs = append(defs, reg.Tool)
+		}
+	}
+	return defs
+}
+
+// Group returns the full names of the visible tools belonging to group, sorted.
+func (t *ToolRegistry) Group(group string) []string {
+	var names []string
+	for _, reg := range t.snapshot() {
+		if reg.inGroup(group) {
+			names = append(names, reg.Tool.Name)
+		}
+	}
+	return names
+}
+
+// Groups returns every group name used by the visible tools, sorted.
+func (t *ToolRegistry) Groups() []string {
+	seen := make(map[string]struct{})
+	for _, reg := range t.snapshot() {
+		for _, g := range reg.Options.Groups {
+			seen[g] = struct{}{}
+		}
+	}
+	groups := make([]string, 0, len(seen))
+	for g := range seen {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// Call invokes a tool by full name outside of any model response (e.g. to serve it over MCP).
+//
+// The registration safeguards apply (arguments validation, per-tool timeout, panic recovery
+// and output limit) with opts as the request-level settings: tools requiring approval are
+// submitted to opts.Approver (denied if nil), and the call is traced with opts.Instrumentation.
+func (t *ToolRegistry) Call(ctx context.Context, name string, args json.RawMessage, opts ToolCallOptions) (json.RawMessage, error) {
+	reg, ok := t.lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFunctionToolNotFound, name)
+	}
+	if len(args) == 0 {
+		args = json.RawMessage(`{}`)
+		if reg.Custom != nil {
+			args = json.RawMessage(`""`)
+		}
+	}
+	call := FunctionCall{Name: name, Arguments: restoreArguments(opts.Redactor, args)}
+	spanCtx, span := startToolSpan(ctx, opts.Instrumentation, call, reg)
+	out, err := invokeTool(spanCtx, &call, reg, opts)
+	endToolSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Redactor != nil && len(out) > 0 {
+		var decoded any
+		if json.Unmarshal(out, &decoded) == nil {
+			if b, err := json.Marshal(opts.Redactor.RedactValue(decoded)); err == nil {
+				out = b
+			}
+		}
+	}
+	return out, nil
+}
+
+// lookup returns a tool by full name. Namespace views only see their own tools.
+func (t *ToolRegistry) lookup(fullName string) (registeredFunctionTool, bool) {
+	if t == nil || t.store == nil || !strings.HasPrefix(fullName, t.prefix) {
+		return registeredFunctionTool{}, false
+	}
+	t.store.mu.RLock()
+	defer t.store.mu.RUnlock()
+	reg, ok := t.store.tools[fullName]
+	return reg, ok
+}
+
+// snapshot returns the visible tools sorted by name.
+func (t *ToolRegistry) snapshot() []registeredFunctionTool {
+	if t == nil || t.store == nil {
+		return nil
+	}
+	t.store.mu.RLock()
+	out := make([]registeredFunctionTool, 0, len(t.store.tools))
+	for name, reg := range t.store.tools {
+		if strings.HasPrefix(name, t.prefix) {
+			out = append(out, reg)
+		}
+	}
+	t.store.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Tool.Name < out[j].Tool.Name
+	})
+	return out
+}
+
+func (reg registeredFunctionTool) inGroup(group string) bool {
+	for _, g := range reg.Options.Groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// WithToolGroups adds the tool to one or more groups, so requests can enable or
+// disable related tools together (see ResponsesRequest.DisableToolGroups).
+func WithToolGroups(groups ...string) FunctionToolOption {
+	return func(o *FunctionToolOptions) {
+		for _, g := range groups {
+			if g = strings.TrimSpace(g); g != "" {
+				o.Groups = append(o.Groups, g)
+			}
+		}
+	}
+}
+
+// typedJSONFunction wraps a typed handler into a JSONFunction.
+// JSON marshaling/unmarshaling is handled with the standard library only (encoding/json).
+func typedJSONFunction[A any, R any](fn func(context.Context, A) (R, error)) JSONFunction {
+	return func(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+		if len(args) == 0 {
+			args = json.RawMessage(`{}`)
+		}
+		var a A
+		if err := json.Unmarshal(args, &a); err != nil {
+			return nil, err
+		}
+
+		res, err := fn(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := json.Marshal(res)
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(b), nil
+	}
+}
+
+/////////////////////////////////////
+// Per request tool selection
+/////////////////////////////////////
+
+// SetToolRegistry attaches a (possibly shared) ToolRegistry to the request.
+// It replaces the registry used by RegisterFunctionTool* on this request: the definitions
+// of the tools registered through the request on the previous registry are removed from Tools.
+func (r *ResponsesRequest) SetToolRegistry(reg *ToolRegistry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if reg == r.toolRegistry {
+		return
+	}
+	if len(r.mirroredTools) > 0 {
+		filtered := r.Tools[:0]
+		for _, t := range r.Tools {
+			if name, ok := functionToolName(t); ok && r.mirroredTools[name] {
+				continue
+			}
+			filtered = append(filtered, t)
+		}
+		r.Tools = filtered
+		r.mirroredTools = nil
+	}
+	r.toolRegistry = reg
+}
+
+// ToolRegistry returns the registry attached to the request.
+// A private registry is created on first use when none was attached.
+func (r *ResponsesRequest) ToolRegistry() *ToolRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.toolRegistryLocked()
+}
+
+func (r *ResponsesRequest) toolRegistryLocked() *ToolRegistry {
+	if r.toolRegistry == nil {
+		r.toolRegistry = NewToolRegistry()
+	}
+	return r.toolRegistry
+}
+
+// DisableTools disables registry tools (full names) for this request only.
+// Disabled tools are neither advertised to the model nor executed.
+func (r *ResponsesRequest) DisableTools(names ...string) {
+	r.setToolOverrides(false, names)
+}
+
+// EnableTools re-enables registry tools for this request, even if one of their groups is disabled.
+func (r *ResponsesRequest) EnableTools(names ...string) {
+	r.setToolOverrides(true, names)
+}
+
+// DisableToolGroups disables every registry tool belonging to one of the groups, for this request only.
+func (r *ResponsesRequest) DisableToolGroups(groups ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.disabledToolGroups == nil {
+		r.disabledToolGroups = make(map[string]bool)
+	}
+	for _, g := range groups {
+		r.disabledToolGroups[strings.TrimSpace(g)] = true
+	}
+}
+
+// EnableToolGroups re-enables groups previously disabled with DisableToolGroups.
+func (r *ResponsesRequest) EnableToolGroups(groups ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, g := range groups {
+		delete(r.disabledToolGroups, strings.TrimSpace(g))
+	}
+}
+
+func (r *ResponsesRequest) setToolOverrides(enabled bool, names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.toolOverrides == nil {
+		r.toolOverrides = make(map[string]bool)
+	}
+	for _, n := range names {
+		r.toolOverrides[strings.TrimSpace(n)] = enabled
+	}
+}
+
+// toolEnabledLocked reports whether a registry tool is enabled for this request.
+// Explicit per-tool overrides win over group settings.
+func (r *ResponsesRequest) toolEnabledLocked(reg registeredFunctionTool) bool {
+	if enabled, ok := r.toolOverrides[reg.Tool.Name]; ok {
+		return enabled
+	}
+	for _, g := range reg.Options.Groups {
+		if r.disabledToolGroups[g] {
+			return false
+		}
+	}
+	return true
+}
+
+// FunctionTools returns the registry tool definitions enabled for this request, sorted by name.
+func (r *ResponsesRequest) FunctionTools() []FunctionTool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var defs []FunctionTool
+	for _, reg := range r.toolRegistry.snapshot() {
+		if reg.Custom == nil && r.toolEnabledLocked(reg) {
+			defs = append(defs, reg.Tool)
+		}
+	}
+	return defs
+}
+
+// EffectiveTools returns the `tools` field as sent to the provider: the entries of Tools
+// (hosted tools, hand-written definitions) followed by the enabled registry tools
+// (function tools, then custom tools).
+// A function or custom tool of Tools with the same name as a registry tool is superseded by the registry,
+// and the definitions kept in Tools by RegisterFunctionTool* are only sent while registered.
+func (r *ResponsesRequest) EffectiveTools() []any {
+	defs := r.FunctionTools()
+	customs := r.CustomTools()
+
+	r.mu.Lock()
+	reg := r.toolRegistry
+	tools := slices.Clone(r.Tools)
+	mirrored := maps.Clone(r.mirroredTools)
+	r.mu.Unlock()
+
+	out := make([]any, 0, len(tools)+len(defs)+len(customs))
+	for _, t := range tools {
+		if name, ok := functionToolName(t); ok {
+			if _, known := reg.lookup(name); known || mirrored[name] {
+				continue
+			}
+		}
+		out = append(out, t)
+	}
+	for _, d := range defs {
+		out = append(out, d)
+	}
+	for _, c := range customs {
+		out = append(out, c)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// syncTool keeps the definition of a tool registered through the request in Tools,
+// as callers inspecting req.Tools after registering expect. EffectiveTools does not duplicate it.
+func (r *ResponsesRequest) syncTool(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reg := r.toolRegistryLocked()
+	registered, ok := reg.lookup(reg.prefix + strings.TrimSpace(name))
+	if !ok {
+		return
+	}
+	var def any = registered.Tool
+	if registered.Custom != nil {
+		def = *registered.Custom
+	}
+	if r.mirroredTools == nil {
+		r.mirroredTools = make(map[string]bool)
+	}
+	r.mirroredTools[registered.Tool.Name] = true
+	for i, t := range r.Tools {
+		if n, ok := functionToolName(t); ok && n == registered.Tool.Name {
+			r.Tools[i] = def
+			return
+		}
+	}
+	r.Tools = append(r.Tools, def)
+}
+
+// effectiveFunctionTools returns the function tools of EffectiveTools, for wire APIs
+// that only support function tools (hosted and custom tools are skipped).
+func (r *ResponsesRequest) effectiveFunctionTools() []FunctionTool {
+	var tools []FunctionTool
+	for _, t := range r.EffectiveTools() {
+		var ft FunctionTool
+		switch v := t.(type) {
+		case FunctionTool:
+			ft = v
+		case *FunctionTool:
+			if v == nil {
+				continue
+			}
+			ft = *v
+		case map[string]any:
+			if typ, _ := v["type"].(string); typ != "function" {
+				continue
+			}
+			ft.Name, _ = v["name"].(string)
+			ft.Description, _ = v["description"].(string)
+			ft.Parameters = v["parameters"]
+		default:
+			continue
+		}
+		if ft.Parameters == nil {
+			ft.Parameters = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		tools = append(tools, ft)
+	}
+	return tools
+}
+
+// functionToolName returns the name of a function (or custom) tool definition.
+func functionToolName(t any) (string, bool) {
+	switch v := t.(type) {
+	case FunctionTool:
+		return v.Name, v.Type == "function"
+	case *FunctionTool:
+		if v == nil {
+			return "", false
+		}
+		return v.Name, v.Type == "function"
+	case CustomTool:
+		return v.Name, true
+	case *CustomTool:
+		if v == nil {
+			return "", false
+		}
+		return v.Name, true
+	case map[string]any:
+		typ, _ := v["type"].(string)
+		nm, _ := v["name"].(string)
+		return nm, typ == "function" || typ == "custom"
+	default:
+		return "", false
+	}
+}
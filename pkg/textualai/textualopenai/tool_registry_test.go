@@ -0,0 +1,125 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func noopTool(context.Context, json.RawMessage) (json.RawMessage, error) {
+	return json.RawMessage(`{}`), nil
+}
+
+// toolNames lists the names (or types, for hosted tools) of tools.
+func toolNames(tools []any) string {
+	var names []string
+	for _, t := range tools {
+		if name, ok := functionToolName(t); ok {
+			names = append(names, name)
+		} else if m, ok := t.(map[string]any); ok {
+			names = append(names, fmt.Sprint(m["type"]))
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func TestSetToolRegistryPrunesMirroredTools(t *testing.T) {
+	req := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+	if err := req.RegisterFunctionTool("local", "Local tool", nil, noopTool); err != nil {
+		t.Fatal(err)
+	}
+	req.Tools = append(req.Tools, map[string]any{"type": "web_search"})
+	if got := toolNames(req.Tools); got != "local,web_search" {
+		t.Fatalf("Tools = %s", got)
+	}
+
+	shared := NewToolRegistry()
+	if err := shared.RegisterFunctionTool("shared", "Shared tool", nil, noopTool); err != nil {
+		t.Fatal(err)
+	}
+	req.SetToolRegistry(shared)
+	if got := toolNames(req.Tools); got != "web_search" {
+		t.Fatalf("Tools after SetToolRegistry = %s", got)
+	}
+	if got := toolNames(req.EffectiveTools()); got != "web_search,shared" {
+		t.Fatalf("EffectiveTools = %s", got)
+	}
+}
+
+func TestEffectiveToolsDropsToolsUnregisteredFromSharedRegistry(t *testing.T) {
+	shared := NewToolRegistry()
+	a := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+	b := NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+	a.SetToolRegistry(shared)
+	b.SetToolRegistry(shared)
+
+	if err := a.RegisterFunctionTool("lookup", "Lookup", nil, noopTool); err != nil {
+		t.Fatal(err)
+	}
+	if got := toolNames(b.EffectiveTools()); got != "lookup" {
+		t.Fatalf("b.EffectiveTools = %s", got)
+	}
+	if err := b.UnregisterFunctionTool("lookup"); err != nil {
+		t.Fatal(err)
+	}
+	// a still holds the definition in Tools, but the registry is authoritative.
+	if got := toolNames(a.EffectiveTools()); got != "" {
+		t.Fatalf("a.EffectiveTools = %s, want none", got)
+	}
+}
+
+func TestSharedToolRegistryConcurrency(t *testing.T) {
+	shared := NewToolRegistry()
+	reqs := make([]*ResponsesRequest, 4)
+	for i := range reqs {
+		reqs[i] = NewResponsesRequest(context.Background(), testModel(t, "openai:gpt-4.1"))
+		reqs[i].SetToolRegistry(shared)
+	}
+
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		for j := range 8 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				if err := req.RegisterFunctionTool(fmt.Sprintf("tool_%d_%d", i, j), "Tool", nil, noopTool); err != nil {
+					t.Error(err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				_ = req.EffectiveTools()
+				_ = req.effectiveFunctionTools()
+				req.DisableTools(fmt.Sprintf("tool_%d_%d", (i+1)%len(reqs), j))
+			}()
+		}
+	}
+	wg.Wait()
+
+	if n := shared.Len(); n != 32 {
+		t.Fatalf("shared registry has %d tools, want 32", n)
+	}
+	// Every request sees the tools registered through the others, except the disabled ones.
+	for i, req := range reqs {
+		if n := len(req.EffectiveTools()); n != 24 {
+			t.Errorf("request %d: %d effective tools, want 24", i, n)
+		}
+	}
+}
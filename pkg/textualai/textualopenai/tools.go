@@ -21,6 +21,7 @@ import (
 
 // JSONFunction is a minimal, dependency-free function signature for OpenAI function calling.
 // The arguments are provided as raw JSON. The returned value MUST be valid JSON.
+// The function MUST return when ctx is done: a timed out call is abandoned, not stopped.
 type JSONFunction func(ctx context.Context, args json.RawMessage) (json.RawMessage, error)
 
 // FunctionCall describes a function call emitted by the model.
@@ -33,15 +34,17 @@ type FunctionCall struct {
 }
 
 // FunctionCallOutputItem is an input item you can send back to the Responses API to provide
-// the output of a function call.
+// the output of a function call (or of a custom tool call).
 type FunctionCallOutputItem struct {
-	Type   string `json:"type"`    // always "function_call_output"
+	Type   string `json:"type"`    // "function_call_output" or "custom_tool_call_output"
 	CallID string `json:"call_id"` // required
-	Output string `json:"output"`  // JSON-encoded string
+	Output string `json:"output"`  // JSON-encoded string (plain text for custom tools)
 }
 
 // FunctionCallObserver is called whenever a registered function call is finalized and executed
 // by the embedded delegate.
+//
+// Handlers run on a worker pool, so the observer may be called concurrently from several goroutines.
 type FunctionCallObserver func(ctx context.Context, call FunctionCall, output *FunctionCallOutputItem, err error)
 
 // FunctionTool defines a "function" tool for the Responses API.
@@ -63,6 +66,11 @@ func Float64Ptr(v float64) *float64 {
 	return &v
 }
 
+// IntPtr returns a pointer to the provided int.
+func IntPtr(v int) *int {
+	return &v
+}
+
 // StringPtr returns a pointer to the provided string.
 func StringPtr(v string) *string {
 	return &v
@@ -0,0 +1,133 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// inputVisitor maps the items of a request input to a wire API that is not the Responses API
+// (see walkInput). Every callback is required.
+type inputVisitor struct {
+	// api names the wire API in errors ("Messages", "Gemini", ...).
+	api string
+	// message receives a message; content is a string, a ContentPart or a list of parts.
+	message func(role string, content any) error
+	// functionCall receives a call; args is a JSON object ("{}" when empty).
+	functionCall func(callID, name string, args json.RawMessage) error
+	// functionOutput receives the output of a function or custom tool call.
+	functionOutput func(callID, output string) error
+	// reasoning receives a reasoning item, with or without encrypted content.
+	reasoning func(item ReasoningItem) error
+}
+
+// walkInput calls v for each item of the request input, in order: strings, InputItems,
+// content parts, tool calls, tool outputs, reasoning items, and their JSON forms
+// (maps and json.RawMessage, as returned by ContinuationItems).
+func walkInput(input any, v inputVisitor) error {
+	switch t := input.(type) {
+	case nil:
+		return nil
+	case string:
+		return v.message("user", t)
+	case InputItem:
+		return v.message(t.Role, t.Content)
+	case *InputItem:
+		if t == nil {
+			return nil
+		}
+		return v.message(t.Role, t.Content)
+	case []InputItem:
+		for _, item := range t {
+			if err := v.message(item.Role, item.Content); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		for _, e := range t {
+			if err := walkInput(e, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ContentPart, []ContentPart:
+		return v.message("user", t)
+	case FunctionCallOutputItem:
+		return v.functionOutput(t.CallID, t.Output)
+	case []FunctionCallOutputItem:
+		for _, out := range t {
+			if err := v.functionOutput(out.CallID, out.Output); err != nil {
+				return err
+			}
+		}
+		return nil
+	case ReasoningItem:
+		return v.reasoning(t)
+	case map[string]any:
+		return walkInputMap(t, v)
+	default:
+		var m map[string]any
+		if err := remarshal(t, &m); err != nil {
+			return fmt.Errorf("textualopenai: unsupported input item %T: %w", input, err)
+		}
+		return walkInputMap(m, v)
+	}
+}
+
+// walkInputMap dispatches an input item in its JSON form.
+func walkInputMap(m map[string]any, v inputVisitor) error {
+	if role, _ := m["role"].(string); role != "" {
+		return v.message(role, m["content"])
+	}
+	typ, _ := m["type"].(string)
+	switch typ {
+	case "function_call":
+		callID, _ := m["call_id"].(string)
+		name, _ := m["name"].(string)
+		args, _ := m["arguments"].(string)
+		if strings.TrimSpace(args) == "" {
+			args = "{}"
+		}
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(args), &obj); err != nil {
+			return fmt.Errorf("textualopenai: invalid function call arguments for %s: %w", name, err)
+		}
+		return v.functionCall(callID, name, json.RawMessage(args))
+	case "function_call_output", "custom_tool_call_output":
+		callID, _ := m["call_id"].(string)
+		output, _ := m["output"].(string)
+		return v.functionOutput(callID, output)
+	case ReasoningItemType:
+		var item ReasoningItem
+		if err := remarshal(m, &item); err != nil {
+			return err
+		}
+		return v.reasoning(item)
+	default:
+		return fmt.Errorf("textualopenai: input item type %q is not supported by the %s API", typ, v.api)
+	}
+}
+
+// remarshal converts v to out through JSON.
+func remarshal(v any, out any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+)
+
+// translatedStream builds the StreamEvents of a stream translated from another wire API
+// (Messages, Gemini, Ollama chat): sequence numbers, output indexes and the output items,
+// shaped like the Responses API ones so the built-in delegates and listeners apply.
+//
+// Sequential APIs stream one text or reasoning item at a time (openItem, closeOpen);
+// the translators keep their own lock and flush the events of each chunk with flush.
+type translatedStream struct {
+	responseID string
+	seq        int
+	nextIndex  int
+	calls      int
+	open       *translatedItem
+	events     []StreamEvent
+}
+
+// translatedItem is a message or reasoning item being streamed.
+type translatedItem struct {
+	Type        string // "message" or "reasoning"
+	ItemID      string
+	OutputIndex int
+	content     strings.Builder
+	signature   string
+}
+
+// reset prepares the translation of a new stream. Sequence numbers keep increasing:
+// the request drops the events numbered below the last one it handled (see trackProgress).
+func (s *translatedStream) reset() {
+	*s = translatedStream{seq: s.seq}
+}
+
+// push numbers and records an event.
+func (s *translatedStream) push(e StreamEvent) {
+	s.seq++
+	e.SequenceNumber = s.seq
+	e.ResponseID = s.responseID
+	s.events = append(s.events, e)
+}
+
+// flush returns the recorded events.
+func (s *translatedStream) flush() []StreamEvent {
+	out := s.events
+	s.events = nil
+	return out
+}
+
+// response returns a response object with the given status and extra fields.
+func (s *translatedStream) response(model string, status ResponseStatus, extra map[string]any) json.RawMessage {
+	fields := map[string]any{"id": s.responseID, "object": "response", "model": model, "status": status}
+	maps.Copy(fields, extra)
+	b, _ := json.Marshal(fields)
+	return b
+}
+
+func (s *translatedStream) itemAdded(outputIndex int, item any) {
+	b, _ := json.Marshal(item)
+	s.push(StreamEvent{Type: OutputItemAdded, OutputIndex: outputIndex, Item: b})
+}
+
+func (s *translatedStream) itemDone(outputIndex int, item any) {
+	b, _ := json.Marshal(item)
+	s.push(StreamEvent{Type: OutputItemDone, OutputIndex: outputIndex, Item: b})
+}
+
+func (s *translatedStream) messageAdded(outputIndex int, itemID string) {
+	s.itemAdded(outputIndex, map[string]any{
+		"type": "message", "id": itemID, "role": "assistant", "status": "in_progress", "content": []any{},
+	})
+}
+
+func (s *translatedStream) messageDone(outputIndex int, itemID, text string) {
+	s.push(StreamEvent{Type: TextDone, OutputIndex: outputIndex, ItemID: itemID, Text: text})
+	s.itemDone(outputIndex, map[string]any{
+		"type": "message", "id": itemID, "role": "assistant", "status": "completed",
+		"content": []any{map[string]any{"type": "output_text", "text": text, "annotations": []any{}}},
+	})
+}
+
+func (s *translatedStream) reasoningAdded(outputIndex int, itemID string) {
+	s.itemAdded(outputIndex, map[string]any{"type": ReasoningItemType, "id": itemID, "summary": []any{}})
+}
+
+// reasoningDone ends a reasoning item; the text becomes its summary and the signature its encrypted content.
+func (s *translatedStream) reasoningDone(outputIndex int, itemID, text, signature string) {
+	summary := []ReasoningSummaryPart{}
+	if text != "" {
+		s.push(StreamEvent{Type: ReasoningTextDone, OutputIndex: outputIndex, ItemID: itemID, Text: text})
+		summary = append(summary, ReasoningSummaryPart{Type: "summary_text", Text: text})
+	}
+	s.itemDone(outputIndex, ReasoningItem{Type: ReasoningItemType, ID: itemID, Summary: summary, EncryptedContent: signature})
+}
+
+func (s *translatedStream) functionCallAdded(outputIndex int, callID, name string) {
+	s.itemAdded(outputIndex, map[string]any{
+		"type": "function_call", "id": callID, "call_id": callID, "name": name, "arguments": "", "status": "in_progress",
+	})
+}
+
+func (s *translatedStream) functionCallDone(outputIndex int, callID, name, args string) {
+	s.push(StreamEvent{Type: FunctionCallArgumentsDone, OutputIndex: outputIndex, ItemID: callID, Name: name, Arguments: args})
+	s.itemDone(outputIndex, map[string]any{
+		"type": "function_call", "id": callID, "call_id": callID, "name": name, "arguments": args, "status": "completed",
+	})
+}
+
+// functionCall emits a call received complete, as a single arguments delta.
+// Calls without id are numbered after the response.
+func (s *translatedStream) functionCall(callID, name string, args json.RawMessage) {
+	if callID == "" {
+		callID = fmt.Sprintf("%s_call_%d", s.responseID, s.calls)
+	}
+	s.calls++
+	a := strings.TrimSpace(string(args))
+	if a == "" || a == "null" {
+		a = "{}"
+	}
+	idx := s.nextIndex
+	s.nextIndex++
+	s.functionCallAdded(idx, callID, name)
+	s.push(StreamEvent{Type: FunctionCallArgumentsDelta, OutputIndex: idx, ItemID: callID, Delta: a})
+	s.functionCallDone(idx, callID, name, a)
+}
+
+// openItem returns the open item of type typ, closing the open item of another type.
+func (s *translatedStream) openItem(typ string) *translatedItem {
+	if s.open != nil && s.open.Type == typ {
+		return s.open
+	}
+	s.closeOpen()
+	it := &translatedItem{Type: typ, OutputIndex: s.nextIndex, ItemID: fmt.Sprintf("%s_%d", s.responseID, s.nextIndex)}
+	s.nextIndex++
+	s.open = it
+	if typ == "message" {
+		s.messageAdded(it.OutputIndex, it.ItemID)
+	} else {
+		s.reasoningAdded(it.OutputIndex, it.ItemID)
+	}
+	return it
+}
+
+// closeOpen ends the open item, if any.
+func (s *translatedStream) closeOpen() {
+	it := s.open
+	if it == nil {
+		return
+	}
+	s.open = nil
+	if it.Type == "message" {
+		s.messageDone(it.OutputIndex, it.ItemID, it.content.String())
+		return
+	}
+	s.reasoningDone(it.OutputIndex, it.ItemID, it.content.String(), it.signature)
+}
+
+// transcodeTranslated transcodes body with a Transcoder made by translatingTranscoder.
+func transcodeTranslated[T any](ctx context.Context, r *ResponsesRequest, body io.Reader, t textual.TranscoderFunc[textual.JsonGenericCarrier[T], textual.StringCarrier]) <-chan textual.StringCarrier {
+	ioT := textual.NewIOReaderTranscoder[textual.JsonGenericCarrier[T], textual.StringCarrier](t, body)
+	ioT.SetSplitFunc(r.SplitFunc())
+	ioT.SetContext(ctx)
+	return ioT.Start()
+}
+
+// translatingTranscoder translates the chunks of another wire API into StreamEvents, then
+// dispatches them like ResponsesRequest.Transcoder (built-in delegates, observers, listeners).
+func translatingTranscoder[T any](r *ResponsesRequest, translate func(T) []StreamEvent) textual.TranscoderFunc[textual.JsonGenericCarrier[T], textual.StringCarrier] {
+	return func(ctx context.Context, in <-chan textual.JsonGenericCarrier[T]) <-chan textual.StringCarrier {
+		return textual.AsyncEmitter(ctx, in, func(ctx context.Context, c textual.JsonGenericCarrier[T], emit func(s textual.StringCarrier)) {
+			var events []StreamEvent
+			if c.Error != nil {
+				events = []StreamEvent{{Type: Error, Message: c.Error.Error()}}
+			} else {
+				events = translate(c.Value)
+			}
+			for _, ev := range events {
+				r.handleEvent(ctx, textual.JsonGenericCarrier[StreamEvent]{Index: c.Index, Value: ev}, func(_ StreamEvent, s textual.StringCarrier) {
+					emit(s)
+				}, nil)
+			}
+		})
+	}
+}
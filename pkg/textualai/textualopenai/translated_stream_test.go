@@ -0,0 +1,77 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/benoit-pereira-da-silva/textual/pkg/textual"
+)
+
+func TestTranslatedStreamsResetOnEachStream(t *testing.T) {
+	ctx := context.Background()
+	messages := NewMessagesRequest(ctx, testModel(t, "anthropic:claude-sonnet-4-5"))
+	gemini := NewGenerateContentRequest(ctx, testModel(t, "gemini:gemini-2.5-pro"))
+	ollama := NewOllamaChatRequest(ctx, testModel(t, "ollama:llama3.2"))
+	tests := []struct {
+		name      string
+		req       *ResponsesRequest
+		transcode func(io.Reader) <-chan textual.StringCarrier
+		body      string
+	}{
+		{"messages", messages.ResponsesRequest, func(r io.Reader) <-chan textual.StringCarrier { return messages.TranscodeStream(ctx, r) }, messagesSSE},
+		{"gemini", gemini.ResponsesRequest, func(r io.Reader) <-chan textual.StringCarrier { return gemini.TranscodeStream(ctx, r) }, sseEvents(geminiChunks...)},
+		{"ollama", ollama.ResponsesRequest, func(r io.Reader) <-chan textual.StringCarrier { return ollama.TranscodeStream(ctx, r) }, strings.Join(ollamaChunks, "\n") + "\n"},
+	}
+	for _, tt := range tests {
+		var mu sync.Mutex
+		var events []string
+		var seqs []int
+		_ = tt.req.AddObservers(func(e textual.JsonGenericCarrier[StreamEvent]) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, fmt.Sprintf("%s@%d", e.Value.Type, e.Value.OutputIndex))
+			seqs = append(seqs, e.Value.SequenceNumber)
+		}, AllEvent)
+
+		// A request streamed twice (e.g. a retried attempt) translates both streams alike.
+		var runs [2]string
+		for i := range runs {
+			for range tt.transcode(strings.NewReader(tt.body)) {
+			}
+			mu.Lock()
+			runs[i], events = strings.Join(events, " "), nil
+			mu.Unlock()
+		}
+		if !strings.HasPrefix(runs[0], string(ResponseCreated)+"@0 ") {
+			t.Errorf("%s: first stream = %s", tt.name, runs[0])
+		}
+		if runs[1] != runs[0] {
+			t.Errorf("%s: second stream = %s\nwant %s", tt.name, runs[1], runs[0])
+		}
+		// Sequence numbers keep increasing, so the second stream is not taken for a replay.
+		for i := 1; i < len(seqs); i++ {
+			if seqs[i] <= seqs[i-1] {
+				t.Errorf("%s: sequence numbers %v", tt.name, seqs)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Usage sample :
+// // Azure OpenAI (provider "azure"): AZURE_OPENAI_ENDPOINT + AZURE_OPENAI_API_KEY ("api-key" header),
+// // {base}/openai/{endpoint}?api-version=... is the provider default template.
+// model, _ := models.ModelFromString("azure:gpt-4.1") // the model id is the deployment name
+// client, _ := textualopenai.ClientFrom("", model, ctx)
+// client = client.WithURLTemplate("", map[string]string{"api_version": "2025-04-01-preview"})
+//
+// // Deployment based routes behind a corporate gateway.
+// client = client.WithURLTemplate("{base}/deployments/{deployment}/{endpoint}?api-version={api_version=2024-10-21}",
+// 	map[string]string{"deployment": "gpt41-prod"})
+
+// ExpandURLTemplate replaces the {name} placeholders of pattern with vars.
+// A placeholder may carry a default value: {api_version=2025-04-01-preview}.
+// Values are inserted as-is; a placeholder without value nor default is an error.
+func ExpandURLTemplate(pattern string, vars map[string]string) (string, error) {
+	var b strings.Builder
+	rest := pattern
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("textualopenai: URL template %q: unclosed placeholder", pattern)
+		}
+		end += start
+		b.WriteString(rest[:start])
+
+		name, def, hasDefault := strings.Cut(rest[start+1:end], "=")
+		name = strings.TrimSpace(name)
+		if v, ok := vars[name]; ok && v != "" {
+			b.WriteString(v)
+		} else if hasDefault {
+			b.WriteString(def)
+		} else {
+			return "", fmt.Errorf("textualopenai: URL template %q: missing variable %q", pattern, name)
+		}
+		rest = rest[end+1:]
+	}
+}
+
+// endpointURL returns the URL of path (relative to the base URL, e.g. "responses/{id}") with query.
+func (c Client) endpointURL(path string, query url.Values) (string, error) {
+	endpoint := strings.TrimSuffix(c.baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	return c.expandEndpoint(endpoint)
+}
+
+// expandEndpoint applies the URL template to an endpoint under the base URL.
+//
+// The template variables are {base}, {endpoint} (the path relative to the base URL),
+// {model} and {deployment} (the model id unless set), plus the client URL variables.
+// The query of the endpoint is merged with the query of the template. Endpoints outside
+// of the base URL (e.g. the native Ollama API) are returned unchanged.
+func (c Client) expandEndpoint(endpoint string) (string, error) {
+	base := strings.TrimSuffix(c.baseURL, "/")
+	if c.urlTemplate == "" || !strings.HasPrefix(endpoint, base) {
+		return endpoint, nil
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(endpoint, base), "/")
+	relPath, relQuery, _ := strings.Cut(rel, "?")
+
+	vars := map[string]string{
+		"base":       base,
+		"endpoint":   relPath,
+		"model":      string(c.model.ID),
+		"deployment": string(c.model.ID),
+	}
+	for k, v := range c.urlVars {
+		vars[k] = v
+	}
+	expanded, err := ExpandURLTemplate(c.urlTemplate, vars)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(expanded)
+	if err != nil {
+		return "", fmt.Errorf("textualopenai: invalid URL %q: %w", expanded, err)
+	}
+	if relQuery != "" {
+		extra, err := url.ParseQuery(relQuery)
+		if err != nil {
+			return "", fmt.Errorf("textualopenai: invalid query %q: %w", relQuery, err)
+		}
+		q := u.Query()
+		for k, values := range extra {
+			for _, v := range values {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String(), nil
+}
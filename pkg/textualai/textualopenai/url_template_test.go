@@ -0,0 +1,47 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandURLTemplate(t *testing.T) {
+	vars := map[string]string{"base": "https://gw.example.com/openai", "deployment": "gpt41-prod", "endpoint": "responses", "empty": ""}
+	tests := []struct {
+		pattern string
+		want    string
+		wantErr string
+	}{
+		{"{base}/deployments/{deployment}/{endpoint}", "https://gw.example.com/openai/deployments/gpt41-prod/responses", ""},
+		{"{base}/{endpoint}?api-version={api_version=2024-10-21}", "https://gw.example.com/openai/responses?api-version=2024-10-21", ""},
+		{"{base}/{ endpoint }", "https://gw.example.com/openai/responses", ""},
+		{"{base}/{empty=fallback}", "https://gw.example.com/openai/fallback", ""},
+		{"no placeholders", "no placeholders", ""},
+		{"{base}/{missing}", "", `missing variable "missing"`},
+		{"{base}/{empty}", "", `missing variable "empty"`},
+		{"{base}/{endpoint", "", "unclosed placeholder"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandURLTemplate(tt.pattern, vars)
+		switch {
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("ExpandURLTemplate(%q) error = %v, want %q", tt.pattern, err, tt.wantErr)
+		case tt.wantErr == "" && (err != nil || got != tt.want):
+			t.Errorf("ExpandURLTemplate(%q) = %q, %v, want %q", tt.pattern, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Usage is the token usage of a response, normalized across the provider dialects
+// (Responses, Chat Completions, Anthropic Messages, Gemini and Ollama).
+type Usage struct {
+	InputTokens     int
+	OutputTokens    int
+	ReasoningTokens int // included in OutputTokens
+	CachedTokens    int // included in InputTokens
+	TotalTokens     int
+}
+
+// ParseUsage decodes a usage payload; ok is false when raw carries no token count.
+func ParseUsage(raw json.RawMessage) (u Usage, ok bool) {
+	var p struct {
+		// Responses API, Anthropic, Ollama (translated)
+		InputTokens        int `json:"input_tokens"`
+		OutputTokens       int `json:"output_tokens"`
+		TotalTokens        int `json:"total_tokens"`
+		InputTokensDetails struct {
+			CachedTokens int `json:"cached_tokens"`
+		} `json:"input_tokens_details"`
+		OutputTokensDetails struct {
+			ReasoningTokens int `json:"reasoning_tokens"`
+		} `json:"output_tokens_details"`
+
+		// Anthropic prompt caching (not included in input_tokens)
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+
+		// Chat Completions
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+
+		// Gemini (thoughts are not included in candidates)
+		PromptTokenCount        int `json:"promptTokenCount"`
+		CandidatesTokenCount    int `json:"candidatesTokenCount"`
+		ThoughtsTokenCount      int `json:"thoughtsTokenCount"`
+		CachedContentTokenCount int `json:"cachedContentTokenCount"`
+		TotalTokenCount         int `json:"totalTokenCount"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &p) != nil {
+		return u, false
+	}
+	switch {
+	case p.PromptTokenCount > 0 || p.CandidatesTokenCount > 0 || p.TotalTokenCount > 0:
+		u = Usage{
+			InputTokens:     p.PromptTokenCount,
+			OutputTokens:    p.CandidatesTokenCount + p.ThoughtsTokenCount,
+			ReasoningTokens: p.ThoughtsTokenCount,
+			CachedTokens:    p.CachedContentTokenCount,
+			TotalTokens:     p.TotalTokenCount,
+		}
+	case p.PromptTokens > 0 || p.CompletionTokens > 0:
+		u = Usage{InputTokens: p.PromptTokens, OutputTokens: p.CompletionTokens, TotalTokens: p.TotalTokens}
+	default:
+		u = Usage{
+			InputTokens:     p.InputTokens + p.CacheReadInputTokens + p.CacheCreationInputTokens,
+			OutputTokens:    p.OutputTokens,
+			ReasoningTokens: p.OutputTokensDetails.ReasoningTokens,
+			CachedTokens:    p.InputTokensDetails.CachedTokens + p.CacheReadInputTokens,
+			TotalTokens:     p.TotalTokens,
+		}
+	}
+	if u.TotalTokens == 0 {
+		u.TotalTokens = u.InputTokens + u.OutputTokens
+	}
+	return u, u.TotalTokens > 0
+}
+
+// TokenUsage returns the parsed Usage of the response (see ParseUsage).
+func (r Response) TokenUsage() (Usage, bool) {
+	return ParseUsage(r.Usage)
+}
+
+// FinishReason returns why the response ended: the provider stop reason when the payload
+// carries one (Anthropic stop_reason, Gemini finish_reason, Ollama done_reason), otherwise
+// "stop" for completed responses, the incomplete reason (e.g. "max_output_tokens"),
+// "error" or "cancelled". It is empty while the response is in progress.
+func FinishReason(raw json.RawMessage) string {
+	var p struct {
+		Status            ResponseStatus `json:"status"`
+		StopReason        string         `json:"stop_reason"`
+		FinishReason      string         `json:"finish_reason"`
+		DoneReason        string         `json:"done_reason"`
+		IncompleteDetails struct {
+			Reason string `json:"reason"`
+		} `json:"incomplete_details"`
+	}
+	if len(raw) == 0 || json.Unmarshal(raw, &p) != nil {
+		return ""
+	}
+	if reason := firstNonBlank(p.StopReason, p.FinishReason, p.DoneReason); reason != "" {
+		return strings.ToLower(reason)
+	}
+	switch p.Status {
+	case ResponseStatusCompleted:
+		return "stop"
+	case ResponseStatusIncomplete:
+		return firstNonBlank(p.IncompleteDetails.Reason, "length")
+	case ResponseStatusFailed:
+		return "error"
+	case ResponseStatusCancelled:
+		return "cancelled"
+	}
+	return ""
+}
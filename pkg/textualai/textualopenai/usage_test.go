@@ -0,0 +1,69 @@
+// Copyright 2026 Benoit Pereira da Silva
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textualopenai
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Usage
+		ok   bool
+	}{
+		{"responses", `{"input_tokens":100,"input_tokens_details":{"cached_tokens":40},"output_tokens":30,"output_tokens_details":{"reasoning_tokens":10},"total_tokens":130}`,
+			Usage{InputTokens: 100, OutputTokens: 30, ReasoningTokens: 10, CachedTokens: 40, TotalTokens: 130}, true},
+		{"anthropic cache", `{"input_tokens":25,"cache_read_input_tokens":10,"cache_creation_input_tokens":5,"output_tokens":42}`,
+			Usage{InputTokens: 40, OutputTokens: 42, CachedTokens: 10, TotalTokens: 82}, true},
+		{"chat completions", `{"prompt_tokens":12,"completion_tokens":8,"total_tokens":20}`,
+			Usage{InputTokens: 12, OutputTokens: 8, TotalTokens: 20}, true},
+		{"gemini thoughts", `{"promptTokenCount":12,"candidatesTokenCount":8,"thoughtsTokenCount":4,"cachedContentTokenCount":2,"totalTokenCount":24}`,
+			Usage{InputTokens: 12, OutputTokens: 12, ReasoningTokens: 4, CachedTokens: 2, TotalTokens: 24}, true},
+		{"empty", `{}`, Usage{}, false},
+		{"missing", ``, Usage{}, false},
+		{"invalid", `{"input_tokens":`, Usage{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseUsage(json.RawMessage(tt.raw))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: ParseUsage = %+v, %v, want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFinishReason(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{`{"status":"completed"}`, "stop"},
+		{`{"status":"completed","stop_reason":"tool_use"}`, "tool_use"},
+		{`{"status":"completed","finish_reason":"STOP"}`, "stop"},
+		{`{"status":"incomplete","done_reason":"length"}`, "length"},
+		{`{"status":"incomplete","incomplete_details":{"reason":"max_output_tokens"}}`, "max_output_tokens"},
+		{`{"status":"incomplete"}`, "length"},
+		{`{"status":"failed"}`, "error"},
+		{`{"status":"cancelled"}`, "cancelled"},
+		{`{"status":"in_progress"}`, ""},
+		{``, ""},
+	}
+	for _, tt := range tests {
+		if got := FinishReason(json.RawMessage(tt.raw)); got != tt.want {
+			t.Errorf("FinishReason(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}